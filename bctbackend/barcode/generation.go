@@ -3,15 +3,47 @@ package barcode
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"log/slog"
 
 	bclib "github.com/boombuler/barcode"
 	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/datamatrix"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
 )
 
-func GenerateBarcode(data string, width int, height int) (image.Image, error) {
-	slog.Debug("Generating barcode", "data", data)
-	barcode, err := code128.Encode(data)
+// Modules holds the modules of an encoded barcode. One-dimensional barcodes consist of a single row.
+// Quiet zones are not included.
+type Modules struct {
+	Columns int
+	Rows    int
+	dark    []bool
+}
+
+// IsDark returns whether the module at the given column and row is dark.
+func (modules *Modules) IsDark(column int, row int) bool {
+	return modules.dark[row*modules.Columns+column]
+}
+
+func encode(symbology Symbology, data string) (bclib.Barcode, error) {
+	switch symbology {
+	case Code128:
+		return code128.Encode(data)
+	case QR:
+		return qr.Encode(data, qr.M, qr.Auto)
+	case DataMatrix:
+		return datamatrix.Encode(data)
+	case EAN13:
+		return ean.Encode(data)
+	default:
+		return nil, fmt.Errorf("unknown symbology %q", symbology)
+	}
+}
+
+func GenerateBarcode(symbology Symbology, data string, width int, height int) (image.Image, error) {
+	slog.Debug("Generating barcode", "symbology", symbology, "data", data)
+	barcode, err := encode(symbology, data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode barcode: %w", err)
 	}
@@ -24,3 +56,27 @@ func GenerateBarcode(data string, width int, height int) (image.Image, error) {
 
 	return scaledBarcode, nil
 }
+
+// EncodeModules encodes the data and returns its modules.
+func EncodeModules(symbology Symbology, data string) (*Modules, error) {
+	barcode, err := encode(symbology, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode barcode: %w", err)
+	}
+
+	bounds := barcode.Bounds()
+	modules := &Modules{
+		Columns: bounds.Dx(),
+		Rows:    bounds.Dy(),
+		dark:    make([]bool, bounds.Dx()*bounds.Dy()),
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray := color.GrayModel.Convert(barcode.At(x, y)).(color.Gray)
+			modules.dark[(y-bounds.Min.Y)*modules.Columns+(x-bounds.Min.X)] = gray.Y < 128
+		}
+	}
+
+	return modules, nil
+}
@@ -0,0 +1,191 @@
+package barcode
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Payloads consist of the event prefix, the item id and a check digit, e.g., "KQB1234" followed by its check digit.
+// The event prefix is generated when the database is initialized, so that labels of earlier events are recognized as foreign.
+// The check digit is computed using the Luhn algorithm over the payload with every letter replaced by its value 10-35,
+// so that most misreads are detected.
+//
+// EAN-13 barcodes can only hold digits, so they carry digits derived from the event prefix instead, see InternalEan13Content.
+// Their own check digit protects them from misreads.
+
+// Number of letters of an event prefix
+const EventPrefixLength = 3
+
+const eventPrefixAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var (
+	// ErrMalformedPayload indicates that the payload does not follow any of the known formats
+	ErrMalformedPayload = errors.New("malformed barcode payload")
+
+	// ErrCorruptedPayload indicates that the check digit does not match, e.g., because the barcode was misread
+	ErrCorruptedPayload = errors.New("corrupted barcode payload")
+
+	// ErrForeignPayload indicates that the barcode was generated for a different event
+	ErrForeignPayload = errors.New("barcode payload of other event")
+)
+
+// GenerateEventPrefix returns a random event prefix.
+func GenerateEventPrefix() (string, error) {
+	var builder strings.Builder
+
+	for range EventPrefixLength {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(eventPrefixAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate event prefix: %w", err)
+		}
+
+		builder.WriteByte(eventPrefixAlphabet[index.Int64()])
+	}
+
+	return builder.String(), nil
+}
+
+func IsValidEventPrefix(prefix string) bool {
+	if len(prefix) != EventPrefixLength {
+		return false
+	}
+
+	for _, character := range prefix {
+		if !strings.ContainsRune(eventPrefixAlphabet, character) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FormatPayload returns the payload identifying the item.
+func FormatPayload(eventPrefix string, itemId int64) string {
+	payload := eventPrefix + strconv.FormatInt(itemId, 10)
+
+	return payload + string(luhnCheckDigit(payload))
+}
+
+// ParsePayload extracts the item id from a scanned payload.
+// Payloads of other events are rejected with ErrForeignPayload, misread payloads with ErrCorruptedPayload
+// and anything else with ErrMalformedPayload.
+func ParsePayload(payload string, eventPrefix string) (int64, error) {
+	if isEan13(payload) {
+		return parseInternalEan13(payload, eventPrefix)
+	}
+
+	if len(payload) < EventPrefixLength+2 {
+		return 0, fmt.Errorf("payload %q is too short: %w", payload, ErrMalformedPayload)
+	}
+
+	prefix := payload[:EventPrefixLength]
+	digits := payload[EventPrefixLength : len(payload)-1]
+	checkDigit := payload[len(payload)-1]
+
+	if !IsValidEventPrefix(prefix) || !isDigits(digits) || !isDigits(string(checkDigit)) {
+		return 0, fmt.Errorf("payload %q does not consist of an event prefix, an item id and a check digit: %w", payload, ErrMalformedPayload)
+	}
+
+	if luhnCheckDigit(payload[:len(payload)-1]) != checkDigit {
+		return 0, fmt.Errorf("check digit of payload %q does not match: %w", payload, ErrCorruptedPayload)
+	}
+
+	if prefix != eventPrefix {
+		return 0, fmt.Errorf("payload %q has event prefix %s instead of %s: %w", payload, prefix, eventPrefix, ErrForeignPayload)
+	}
+
+	itemId, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse item id of payload %q: %w", payload, ErrMalformedPayload)
+	}
+
+	return itemId, nil
+}
+
+func isDigits(str string) bool {
+	if len(str) == 0 {
+		return false
+	}
+
+	for _, character := range str {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// luhnCheckDigit computes the check digit of the string, whose letters count as two digits with value 10-35.
+func luhnCheckDigit(str string) byte {
+	var digits []int
+	for _, character := range str {
+		switch {
+		case character >= '0' && character <= '9':
+			digits = append(digits, int(character-'0'))
+		default:
+			value := int(character-'A') + 10
+			digits = append(digits, value/10, value%10)
+		}
+	}
+
+	// Starting from the right, every other digit is doubled, beginning with the rightmost one
+	sum := 0
+	for index := range digits {
+		digit := digits[len(digits)-1-index]
+		if index%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isEan13(payload string) bool {
+	return len(payload) == 13 && isDigits(payload)
+}
+
+// parseInternalEan13 extracts the item id from an EAN-13 code generated by InternalEan13Content.
+func parseInternalEan13(payload string, eventPrefix string) (int64, error) {
+	if ean13CheckDigit(payload[:12]) != payload[12] {
+		return 0, fmt.Errorf("check digit of EAN-13 code %s does not match: %w", payload, ErrCorruptedPayload)
+	}
+
+	if !strings.HasPrefix(payload, internalEan13Prefix) {
+		return 0, fmt.Errorf("EAN-13 code %s is not meant for internal use: %w", payload, ErrForeignPayload)
+	}
+
+	eventDigits := payload[len(internalEan13Prefix) : len(internalEan13Prefix)+internalEan13EventDigits]
+	if discriminator, _ := strconv.Atoi(eventDigits); discriminator != ean13EventDiscriminator(eventPrefix) {
+		return 0, fmt.Errorf("EAN-13 code %s was generated for another event: %w", payload, ErrForeignPayload)
+	}
+
+	itemId, err := strconv.ParseInt(payload[len(internalEan13Prefix)+internalEan13EventDigits:12], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse item id of EAN-13 code %s: %w", payload, ErrMalformedPayload)
+	}
+
+	return itemId, nil
+}
+
+// ean13CheckDigit computes the check digit of the first 12 digits of an EAN-13 code.
+func ean13CheckDigit(digits string) byte {
+	sum := 0
+	for index, character := range digits {
+		digit := int(character - '0')
+		if index%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
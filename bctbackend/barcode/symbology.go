@@ -0,0 +1,87 @@
+package barcode
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Symbology determines how data is encoded in a barcode.
+type Symbology string
+
+const (
+	Code128    Symbology = "code128"
+	QR         Symbology = "qr"
+	DataMatrix Symbology = "datamatrix"
+
+	// EAN13 barcodes only hold digits, so items are encoded with the prefix reserved for internal use, see InternalEan13Content.
+	EAN13 Symbology = "ean13"
+)
+
+// Symbologies lists all supported symbologies.
+var Symbologies = []Symbology{Code128, QR, DataMatrix, EAN13}
+
+// EAN-13 prefix reserved by GS1 for numbers that are only used within a company
+const internalEan13Prefix = "20"
+
+// Number of digits of an internal EAN-13 code that identify the event, see ean13EventDiscriminator
+const internalEan13EventDigits = 3
+
+// Number of digits left for the item in an EAN-13 code, excluding the prefixes and the check digit
+const internalEan13ItemDigits = 7
+
+func IsValidSymbology(symbology Symbology) bool {
+	return slices.Contains(Symbologies, symbology)
+}
+
+// IsTwoDimensional returns true for symbologies that encode data in a matrix of square modules.
+// Such barcodes are drawn as squares rather than as bars of a fixed module width.
+func (symbology Symbology) IsTwoDimensional() bool {
+	return symbology == QR || symbology == DataMatrix
+}
+
+// QuietZoneModules is the number of light modules that must surround the barcode.
+func (symbology Symbology) QuietZoneModules() int {
+	switch symbology {
+	case QR:
+		return 4
+	case DataMatrix:
+		return 1
+	case EAN13:
+		return 11
+	default:
+		return 10
+	}
+}
+
+// InternalEan13Content derives the 12 digits of an EAN-13 code for internal use from the number.
+// The number is preceded by digits derived from the event prefix, so that codes of other events are recognized as foreign.
+// The check digit is added when encoding.
+func InternalEan13Content(eventPrefix string, number int) (string, error) {
+	if !IsValidEventPrefix(eventPrefix) {
+		return "", fmt.Errorf("invalid event prefix %q", eventPrefix)
+	}
+
+	content := fmt.Sprintf("%s%0*d%0*d", internalEan13Prefix, internalEan13EventDigits, ean13EventDiscriminator(eventPrefix), internalEan13ItemDigits, number)
+	if number < 0 || len(content) != len(internalEan13Prefix)+internalEan13EventDigits+internalEan13ItemDigits {
+		return "", fmt.Errorf("number %d cannot be encoded in an EAN-13 barcode", number)
+	}
+
+	return content, nil
+}
+
+// ean13EventDiscriminator reduces the event prefix to the digits that identify the event in EAN-13 codes.
+// Different prefixes can share a discriminator, but the odds that a leftover label is mistaken for one of this event are small.
+func ean13EventDiscriminator(eventPrefix string) int {
+	value := 0
+	for _, character := range eventPrefix {
+		value = value*len(eventPrefixAlphabet) + strings.IndexRune(eventPrefixAlphabet, character)
+	}
+
+	modulus := 1
+	for range internalEan13EventDigits {
+		modulus *= 10
+	}
+
+	return value % modulus
+}
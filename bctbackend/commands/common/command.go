@@ -2,9 +2,12 @@ package common
 
 import (
 	"bctbackend/algorithms"
+	"bctbackend/barcode"
 	"bctbackend/database"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/pdf"
+	"bctbackend/zpl"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -107,6 +110,15 @@ func (c *Command) GetConfigurationInt(key string) (int, error) {
 	return viper.GetInt(key), nil
 }
 
+func (c *Command) GetConfigurationFloat(key string) (float64, error) {
+	if !viper.IsSet(key) {
+		c.PrintErrorf("Configuration key '%s' is not set\n", key)
+		return 0, fmt.Errorf("configuration key '%s' is not set", key)
+	}
+
+	return viper.GetFloat64(key), nil
+}
+
 func (c *Command) GetConfigurationString(key string) (string, error) {
 	if !viper.IsSet(key) {
 		c.PrintErrorf("Configuration key '%s' is not set\n", key)
@@ -124,3 +136,191 @@ func (c *Command) GetConfigurationBool(key string) (bool, error) {
 
 	return viper.GetBool(key), nil
 }
+
+func (c *Command) GetConfigurationStringSlice(key string) ([]string, error) {
+	if !viper.IsSet(key) {
+		c.PrintErrorf("Configuration key '%s' is not set\n", key)
+		return nil, fmt.Errorf("configuration key '%s' is not set", key)
+	}
+
+	return viper.GetStringSlice(key), nil
+}
+
+// GetPdfConfiguration collects the settings needed to generate labels.
+func (c *Command) GetPdfConfiguration() (*pdf.Configuration, error) {
+	fontDirectory, err := c.GetConfigurationString(FlagFontDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	fontFilename, err := c.GetConfigurationString(FlagFontFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	fontFamily, err := c.GetConfigurationString(FlagFontFamily)
+	if err != nil {
+		return nil, err
+	}
+
+	minimumFontSize, err := c.GetConfigurationFloat(FlagMinimumFontSize)
+	if err != nil {
+		return nil, err
+	}
+
+	barcodeWidth, err := c.GetConfigurationInt(FlagBarcodeWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	barcodeHeight, err := c.GetConfigurationInt(FlagBarcodeHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	barcodeSettings, err := c.GetBarcodeSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pdf.Configuration{
+		FontDirectory:      fontDirectory,
+		FontFilename:       fontFilename,
+		FontFamily:         fontFamily,
+		MinimumFontSize:    minimumFontSize,
+		BarcodeRenderer:    barcodeSettings.Renderer,
+		BarcodeSymbology:   barcodeSettings.Symbology,
+		BarcodeModuleWidth: barcodeSettings.ModuleWidth,
+		BarcodeBarHeight:   barcodeSettings.BarHeight,
+		BarcodeShowText:    barcodeSettings.ShowText,
+		BarcodeWidth:       barcodeWidth,
+		BarcodeHeight:      barcodeHeight,
+	}, nil
+}
+
+// BarcodeSettings groups the configuration of vector barcodes.
+type BarcodeSettings struct {
+	Renderer    pdf.BarcodeRenderer
+	Symbology   barcode.Symbology
+	ModuleWidth float64
+	BarHeight   float64
+	ShowText    bool
+}
+
+// GetBarcodeSettings reads how barcodes are to be rendered.
+func (c *Command) GetBarcodeSettings() (*BarcodeSettings, error) {
+	renderer, err := c.GetConfigurationString(FlagBarcodeRenderer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pdf.IsValidBarcodeRenderer(pdf.BarcodeRenderer(renderer)) {
+		c.PrintErrorf("Invalid barcode renderer '%s'; expected '%s' or '%s'\n", renderer, pdf.VectorBarcodes, pdf.RasterBarcodes)
+		return nil, fmt.Errorf("invalid barcode renderer %s", renderer)
+	}
+
+	symbology, err := c.GetConfigurationString(FlagBarcodeSymbology)
+	if err != nil {
+		return nil, err
+	}
+
+	if !barcode.IsValidSymbology(barcode.Symbology(symbology)) {
+		c.PrintErrorf("Invalid barcode symbology '%s'; expected one of %v\n", symbology, barcode.Symbologies)
+		return nil, fmt.Errorf("invalid barcode symbology %s", symbology)
+	}
+
+	moduleWidth, err := c.GetConfigurationFloat(FlagBarcodeModuleWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	barHeight, err := c.GetConfigurationFloat(FlagBarcodeBarHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	showText, err := c.GetConfigurationBool(FlagBarcodeShowText)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BarcodeSettings{
+		Renderer:    pdf.BarcodeRenderer(renderer),
+		Symbology:   barcode.Symbology(symbology),
+		ModuleWidth: moduleWidth,
+		BarHeight:   barHeight,
+		ShowText:    showText,
+	}, nil
+}
+
+// GetZplConfiguration collects the settings needed to generate labels for thermal printers.
+// The barcode settings are shared with PDF labels.
+func (c *Command) GetZplConfiguration() (*zpl.Configuration, error) {
+	labelWidth, err := c.GetConfigurationInt(FlagZplLabelWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	labelHeight, err := c.GetConfigurationInt(FlagZplLabelHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	labelPadding, err := c.GetConfigurationInt(FlagZplLabelPadding)
+	if err != nil {
+		return nil, err
+	}
+
+	dpi, err := c.GetConfigurationInt(FlagZplDpi)
+	if err != nil {
+		return nil, err
+	}
+
+	fontSize, err := c.GetConfigurationFloat(FlagZplFontSize)
+	if err != nil {
+		return nil, err
+	}
+
+	barcodeSettings, err := c.GetBarcodeSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return &zpl.Configuration{
+		LabelWidth:         labelWidth,
+		LabelHeight:        labelHeight,
+		LabelPadding:       labelPadding,
+		Dpi:                dpi,
+		FontSize:           fontSize,
+		BarcodeSymbology:   barcodeSettings.Symbology,
+		BarcodeModuleWidth: barcodeSettings.ModuleWidth,
+		BarcodeBarHeight:   barcodeSettings.BarHeight,
+		BarcodeShowText:    barcodeSettings.ShowText,
+	}, nil
+}
+
+// GetLabelTemplates reads the label templates defined in the configuration file, e.g.,
+//
+//	label-templates:
+//	  compact:
+//	    fields:
+//	      - { kind: barcode, x: 0, y: 0, height: 0.5 }
+//	      - { kind: description, x: 0, y: 0.55, bold: true }
+//	      - { kind: category, x: 0, y: 0, below: true }
+//	      - { kind: text, x: 0.5, y: 1, align: center, vertical-align: bottom, text: "Spring sale" }
+func (c *Command) GetLabelTemplates() (map[string]*pdf.LabelTemplate, error) {
+	templates := map[string]*pdf.LabelTemplate{}
+	if err := viper.UnmarshalKey(FlagLabelTemplates, &templates); err != nil {
+		c.PrintErrorf("Failed to read label templates: %v\n", err)
+		return nil, fmt.Errorf("failed to read label templates: %w", err)
+	}
+
+	for name, template := range templates {
+		if err := pdf.ValidateTemplate(template); err != nil {
+			c.PrintErrorf("Invalid label template %s: %v\n", name, err)
+			return nil, fmt.Errorf("invalid label template %s: %w", name, err)
+		}
+	}
+
+	return templates, nil
+}
@@ -1,11 +1,23 @@
 package common
 
 const (
-	FlagConfigurationPath = "config"
-	FlagDatabase          = "database"
-	FlagFontDirectory     = "font.directory"
-	FlagFontFamily        = "font.family"
-	FlagFontFilename      = "font.filename"
-	FlagBarcodeWidth      = "barcode.width"
-	FlagBarcodeHeight     = "barcode.height"
+	FlagConfigurationPath  = "config"
+	FlagDatabase           = "database"
+	FlagFontDirectory      = "font.directory"
+	FlagFontFamily         = "font.family"
+	FlagFontFilename       = "font.filename"
+	FlagMinimumFontSize    = "font.minimum-size"
+	FlagBarcodeWidth       = "barcode.width"
+	FlagBarcodeHeight      = "barcode.height"
+	FlagBarcodeRenderer    = "barcode.renderer"
+	FlagBarcodeSymbology   = "barcode.symbology"
+	FlagBarcodeModuleWidth = "barcode.module-width"
+	FlagBarcodeBarHeight   = "barcode.bar-height"
+	FlagBarcodeShowText    = "barcode.show-text"
+	FlagLabelTemplates     = "label-templates"
+	FlagZplLabelWidth      = "zpl.label-width"
+	FlagZplLabelHeight     = "zpl.label-height"
+	FlagZplLabelPadding    = "zpl.label-padding"
+	FlagZplDpi             = "zpl.dpi"
+	FlagZplFontSize        = "zpl.font-size"
 )
@@ -21,6 +21,7 @@ func NewItemCommand() *cobra.Command {
 	command.AddCommand(NewRemoveItemCommand())
 	command.AddCommand(NewCopyItemCommand())
 	command.AddCommand(NewUpdateItemCommand())
+	command.AddCommand(NewLabelsCommand())
 
 	return &command
 }
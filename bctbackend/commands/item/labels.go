@@ -0,0 +1,350 @@
+package item
+
+import (
+	"bctbackend/barcode"
+	"bctbackend/commands/common"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/labels"
+	"bctbackend/pdf"
+	"bctbackend/zpl"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pdfFormat = "pdf"
+	zplFormat = "zpl"
+)
+
+type labelsCommand struct {
+	common.Command
+	output       string
+	format       string
+	layoutId     string
+	template     string
+	symbology    string
+	paperWidth   float64
+	paperHeight  float64
+	paperMargin  float64
+	columns      int
+	rows         int
+	labelMargin  float64
+	labelPadding float64
+	fontSize     float64
+	startColumn  int
+	startRow     int
+	skippedCells []string
+	preview      bool
+	showGrid     bool
+}
+
+func NewLabelsCommand() *cobra.Command {
+	var command *labelsCommand
+
+	command = &labelsCommand{
+		Command: common.Command{
+			CobraCommand: &cobra.Command{
+				Use:   "labels <item-id> ...",
+				Short: "Generates labels",
+				Long: heredoc.Doc(`
+				This command generates a PDF with labels for the given items and freezes them.
+				Use --format zpl to generate ZPL for thermal printers instead, printing each label separately.
+				The size of these labels is set in the configuration file, so that only --layout, --template,
+				--symbology and --preview apply to them.
+				Columns and rows are counted from 0, starting at the top left of the sheet.
+				Use --start-column and --start-row to continue on a partially used sheet,
+				and --skip to leave specific cells of the first sheet empty, e.g., --skip 0:1,2:1.
+				Use --layout to print on a sheet described by one of the stored label layout presets,
+				in which case the paper, grid, margin, padding and font size flags are ignored.
+				Use --template to pick one of the label templates defined in the configuration file.
+				It defaults to the template of the layout preset, if any, and to the default template otherwise.
+				Likewise, --symbology overrides the barcode symbology of the layout preset and the configuration.
+				Use --preview to check the alignment of the labels: the labels are marked
+				as a preview and the items are not frozen.
+			   `),
+				Args: cobra.MinimumNArgs(1),
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return command.execute(args)
+				},
+			},
+		},
+	}
+
+	flags := command.CobraCommand.Flags()
+	flags.StringVar(&command.output, "output", "", "File to write the labels to; defaults to labels.pdf or labels.zpl")
+	flags.StringVar(&command.format, "format", pdfFormat, "Format of the labels: pdf or zpl")
+	flags.StringVar(&command.layoutId, "layout", "", "Id of the label layout preset to use")
+	flags.StringVar(&command.template, "template", "", "Name of the label template to use")
+	flags.StringVar(&command.symbology, "symbology", "", "Barcode symbology: code128, qr, datamatrix or ean13")
+	flags.Float64Var(&command.paperWidth, "paper-width", 210, "Width of the paper in mm")
+	flags.Float64Var(&command.paperHeight, "paper-height", 297, "Height of the paper in mm")
+	flags.Float64Var(&command.paperMargin, "paper-margin", 10, "Margin of the paper in mm")
+	flags.IntVar(&command.columns, "columns", 2, "Number of labels per row")
+	flags.IntVar(&command.rows, "rows", 8, "Number of labels per column")
+	flags.Float64Var(&command.labelMargin, "label-margin", 2, "Margin around each label in mm")
+	flags.Float64Var(&command.labelPadding, "label-padding", 2, "Padding inside each label in mm")
+	flags.Float64Var(&command.fontSize, "font-size", 5, "Font size in mm")
+	flags.IntVar(&command.startColumn, "start-column", 0, "Column of the first sheet at which to start printing")
+	flags.IntVar(&command.startRow, "start-row", 0, "Row of the first sheet at which to start printing")
+	flags.StringSliceVar(&command.skippedCells, "skip", []string{}, "Cells of the first sheet to leave empty, as column:row")
+	flags.BoolVar(&command.preview, "preview", false, "Mark the labels as a preview and do not freeze the items")
+	flags.BoolVar(&command.showGrid, "show-grid", false, "Draw a grid over each label")
+
+	return command.AsCobraCommand()
+}
+
+func (c *labelsCommand) execute(args []string) error {
+	if err := c.validateFormat(); err != nil {
+		c.PrintErrorf("%v\n", err)
+		return err
+	}
+
+	return c.WithOpenedDatabase(func(db *sql.DB) error {
+		itemIds, err := c.ParseItemIds(args)
+		if err != nil {
+			return err
+		}
+
+		layout, preset, err := c.determineLayout(db)
+		if err != nil {
+			c.PrintErrorf("Invalid layout: %v\n", err)
+			return err
+		}
+
+		template, err := c.determineTemplate(preset)
+		if err != nil {
+			return err
+		}
+
+		symbology, err := c.determineSymbology(preset)
+		if err != nil {
+			c.PrintErrorf("%v\n", err)
+			return err
+		}
+
+		generationOptions, err := c.parseGenerationOptions()
+		if err != nil {
+			c.PrintErrorf("%v\n", err)
+			return err
+		}
+		generationOptions = append(generationOptions, pdf.UsingTemplate(template), pdf.UsingSymbology(symbology))
+
+		itemTable, err := queries.GetItemsWithIds(db, itemIds)
+		if err != nil {
+			c.PrintErrorf("Failed to get items: %v\n", err)
+			return err
+		}
+
+		labelData, err := labels.CollectLabelData(db, itemTable, itemIds)
+		if err != nil {
+			c.PrintErrorf("Failed to collect label data: %v\n", err)
+			return err
+		}
+
+		output := c.output
+		if output == "" {
+			output = "labels." + c.format
+		}
+
+		if c.format == zplFormat {
+			err = c.writeZpl(output, labelData, template, symbology)
+		} else {
+			err = c.writePdf(output, layout, labelData, generationOptions)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !c.preview {
+			if err := queries.UpdateFreezeStatusOfItems(db, itemIds, true); err != nil {
+				c.PrintErrorf("Failed to freeze items: %v\n", err)
+				return err
+			}
+		}
+
+		c.Printf("Labels written to %s\n", output)
+		return nil
+	})
+}
+
+// validateFormat checks the format and rejects the flags that only apply to sheets when generating ZPL.
+func (c *labelsCommand) validateFormat() error {
+	switch c.format {
+	case pdfFormat:
+		return nil
+	case zplFormat:
+		if c.startColumn != 0 || c.startRow != 0 || len(c.skippedCells) > 0 || c.showGrid {
+			return fmt.Errorf("--start-column, --start-row, --skip and --show-grid only apply to PDF labels")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid format %s; expected %s or %s", c.format, pdfFormat, zplFormat)
+	}
+}
+
+func (c *labelsCommand) writePdf(output string, layout *pdf.LayoutSettings, labelData []*pdf.LabelData, generationOptions []pdf.GenerationOption) error {
+	pdfConfiguration, err := c.GetPdfConfiguration()
+	if err != nil {
+		return err
+	}
+
+	builder, err := pdf.GeneratePdf(pdfConfiguration, layout, labelData, generationOptions...)
+	if err != nil {
+		c.PrintErrorf("Failed to generate labels: %v\n", err)
+		return err
+	}
+
+	if err := builder.WriteToFile(output); err != nil {
+		c.PrintErrorf("Failed to write labels to %s: %v\n", output, err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *labelsCommand) writeZpl(output string, labelData []*pdf.LabelData, template *pdf.LabelTemplate, symbology barcode.Symbology) error {
+	zplConfiguration, err := c.GetZplConfiguration()
+	if err != nil {
+		return err
+	}
+
+	options := []zpl.GenerationOption{zpl.UsingTemplate(template), zpl.UsingSymbology(symbology)}
+	if c.preview {
+		options = append(options, zpl.MarkedAsPreview())
+	}
+
+	buffer, err := zpl.GenerateZpl(zplConfiguration, labelData, options...)
+	if err != nil {
+		c.PrintErrorf("Failed to generate labels: %v\n", err)
+		return err
+	}
+
+	if err := os.WriteFile(output, buffer.Bytes(), 0644); err != nil {
+		c.PrintErrorf("Failed to write labels to %s: %v\n", output, err)
+		return err
+	}
+
+	return nil
+}
+
+// determineLayout uses the layout preset if one was specified, and the layout flags otherwise.
+// It also returns the preset, which is nil if none was specified.
+func (c *labelsCommand) determineLayout(db *sql.DB) (*pdf.LayoutSettings, *models.LabelLayout, error) {
+	if c.layoutId == "" {
+		layout, err := pdf.NewLayoutSettings(
+			pdf.WithPaperSize(c.paperWidth, c.paperHeight),
+			pdf.WithUniformPaperMargin(c.paperMargin),
+			pdf.WithGridSize(c.columns, c.rows),
+			pdf.WithUniformLabelMargin(c.labelMargin),
+			pdf.WithUniformLabelPadding(c.labelPadding),
+			pdf.WithFontSize(c.fontSize),
+		)
+		return layout, nil, err
+	}
+
+	layoutId, err := models.ParseId(c.layoutId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid layout id %s: %w", c.layoutId, err)
+	}
+
+	layout, err := queries.GetLabelLayoutWithId(db, layoutId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings, err := labels.LayoutSettings(layout)
+	return settings, layout, err
+}
+
+// determineTemplate finds the template given by --template, falling back on the template of the layout preset.
+func (c *labelsCommand) determineTemplate(preset *models.LabelLayout) (*pdf.LabelTemplate, error) {
+	templates, err := c.GetLabelTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	templateName := ""
+	if preset != nil {
+		templateName = preset.Template
+	}
+	if c.template != "" {
+		templateName = c.template
+	}
+
+	template, err := labels.FindTemplate(templates, templateName)
+	if err != nil {
+		c.PrintErrorf("%v\n", err)
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// determineSymbology returns the symbology given by --symbology, falling back on the symbology of the layout preset.
+// An empty symbology means the one of the configuration is used.
+func (c *labelsCommand) determineSymbology(preset *models.LabelLayout) (barcode.Symbology, error) {
+	if c.symbology != "" {
+		symbology := barcode.Symbology(c.symbology)
+		if !barcode.IsValidSymbology(symbology) {
+			return "", fmt.Errorf("invalid symbology %s; expected one of %v", c.symbology, barcode.Symbologies)
+		}
+
+		return symbology, nil
+	}
+
+	if preset == nil {
+		return "", nil
+	}
+
+	return labels.LayoutSymbology(preset)
+}
+
+func (c *labelsCommand) parseGenerationOptions() ([]pdf.GenerationOption, error) {
+	options := []pdf.GenerationOption{pdf.StartingAt(pdf.Cell{Column: c.startColumn, Row: c.startRow})}
+
+	if c.preview {
+		options = append(options, pdf.WithWatermark(pdf.PreviewWatermark))
+	}
+
+	if c.showGrid {
+		options = append(options, pdf.ShowingGrid())
+	}
+
+	for _, skippedCell := range c.skippedCells {
+		cell, err := parseCell(skippedCell)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, pdf.SkippingCells(cell))
+	}
+
+	return options, nil
+}
+
+// parseCell parses a cell written as column:row.
+func parseCell(str string) (pdf.Cell, error) {
+	columnString, rowString, found := strings.Cut(str, ":")
+	if !found {
+		return pdf.Cell{}, fmt.Errorf("invalid cell %s; expected column:row", str)
+	}
+
+	column, err := strconv.Atoi(columnString)
+	if err != nil {
+		return pdf.Cell{}, fmt.Errorf("invalid column in cell %s: %w", str, err)
+	}
+
+	row, err := strconv.Atoi(rowString)
+	if err != nil {
+		return pdf.Cell{}, fmt.Errorf("invalid row in cell %s: %w", str, err)
+	}
+
+	return pdf.Cell{Column: column, Row: row}, nil
+}
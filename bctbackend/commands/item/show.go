@@ -1,6 +1,7 @@
 package item
 
 import (
+	"bctbackend/barcode"
 	"bctbackend/commands/common"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
@@ -8,12 +9,14 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
 type showItemCommand struct {
 	common.Command
+	barcode bool
 }
 
 func NewShowItemCommand() *cobra.Command {
@@ -24,8 +27,11 @@ func NewShowItemCommand() *cobra.Command {
 			CobraCommand: &cobra.Command{
 				Use:   "show <item-id>",
 				Short: "Show item info",
-				Long:  `This command shows detailed information about a specific item.`,
-				Args:  cobra.ExactArgs(1), // Expect exactly one argument (the item ID)
+				Long: heredoc.Doc(`
+					This command shows detailed information about a specific item.
+					With --barcode, the argument is the payload of a scanned barcode instead of an item id.
+				`),
+				Args: cobra.ExactArgs(1), // Expect exactly one argument (the item ID)
 				RunE: func(cmd *cobra.Command, args []string) error {
 					return command.execute(args)
 				},
@@ -33,14 +39,15 @@ func NewShowItemCommand() *cobra.Command {
 		},
 	}
 
+	command.CobraCommand.Flags().BoolVar(&command.barcode, "barcode", false, "Interpret the argument as a scanned barcode")
+
 	return command.CobraCommand
 }
 
 func (c *showItemCommand) execute(args []string) error {
 	return c.WithOpenedDatabase(func(db *sql.DB) error {
-		itemId, err := c.ParseItemId(args[0])
+		itemId, err := c.determineItemId(db, args[0])
 		if err != nil {
-			c.PrintErrorf("Invalid item ID\n")
 			return err
 		}
 
@@ -53,6 +60,33 @@ func (c *showItemCommand) execute(args []string) error {
 	})
 }
 
+// determineItemId parses the argument as an item id or, with --barcode, as a scanned barcode.
+func (c *showItemCommand) determineItemId(db *sql.DB, argument string) (models.Id, error) {
+	if !c.barcode {
+		itemId, err := c.ParseItemId(argument)
+		if err != nil {
+			c.PrintErrorf("Invalid item ID\n")
+			return 0, err
+		}
+
+		return itemId, nil
+	}
+
+	eventPrefix, err := queries.GetEventPrefix(db)
+	if err != nil {
+		c.PrintErrorf("Failed to get event prefix: %v\n", err)
+		return 0, err
+	}
+
+	itemId, err := barcode.ParsePayload(argument, eventPrefix)
+	if err != nil {
+		c.PrintErrorf("Invalid barcode: %v\n", err)
+		return 0, err
+	}
+
+	return models.Id(itemId), nil
+}
+
 func (c *showItemCommand) printItem(db *sql.DB, itemId models.Id) error {
 	categoryNameTable, err := c.GetCategoryNameTable(db)
 	if err != nil {
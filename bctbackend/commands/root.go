@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"bctbackend/barcode"
 	"bctbackend/commands/category"
 	"bctbackend/commands/common"
 	"bctbackend/commands/database"
@@ -9,7 +10,10 @@ import (
 	"bctbackend/commands/item"
 	"bctbackend/commands/sale"
 	"bctbackend/commands/server"
+	"bctbackend/commands/session"
+	"bctbackend/commands/token"
 	"bctbackend/commands/user"
+	"bctbackend/pdf"
 	"fmt"
 	"log/slog"
 	"os"
@@ -35,6 +39,7 @@ func NewRootCommand() *cobra.Command {
 	cobra.OnInitialize(func() {
 		if verbose {
 			slog.SetLogLoggerLevel(slog.LevelDebug)
+			viper.Set("log.level", "debug")
 			slog.Info("Verbose mode enabled")
 		}
 
@@ -74,14 +79,27 @@ func NewRootCommand() *cobra.Command {
 	viper.SetDefault(common.FlagFontDirectory, ".")
 	viper.SetDefault(common.FlagFontFilename, "arial.ttf")
 	viper.SetDefault(common.FlagFontFamily, "Arial")
+	viper.SetDefault(common.FlagMinimumFontSize, 2)
 	viper.SetDefault(common.FlagBarcodeWidth, 150)
 	viper.SetDefault(common.FlagBarcodeHeight, 30)
+	viper.SetDefault(common.FlagBarcodeRenderer, string(pdf.VectorBarcodes))
+	viper.SetDefault(common.FlagBarcodeSymbology, string(barcode.Code128))
+	viper.SetDefault(common.FlagBarcodeModuleWidth, 0.33)
+	viper.SetDefault(common.FlagBarcodeBarHeight, 10)
+	viper.SetDefault(common.FlagBarcodeShowText, false)
+	viper.SetDefault(common.FlagZplLabelWidth, 812)
+	viper.SetDefault(common.FlagZplLabelHeight, 406)
+	viper.SetDefault(common.FlagZplLabelPadding, 16)
+	viper.SetDefault(common.FlagZplDpi, 203)
+	viper.SetDefault(common.FlagZplFontSize, 3)
 
 	rootCommand.AddCommand(item.NewItemCommand())
 	rootCommand.AddCommand(user.NewUserCommand())
 	rootCommand.AddCommand(database.NewDatabaseCommand())
 	rootCommand.AddCommand(sale.NewSaleCommand())
 	rootCommand.AddCommand(server.NewServerCommand())
+	rootCommand.AddCommand(session.NewSessionCommand())
+	rootCommand.AddCommand(token.NewTokenCommand())
 	rootCommand.AddCommand(category.NewCategoryCommand())
 	rootCommand.AddCommand(initialize.NewInitializeCommand())
 	rootCommand.AddCommand(download.NewDownloadCommand())
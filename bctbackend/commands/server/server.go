@@ -1,13 +1,13 @@
 package server
 
 import (
-	"bctbackend/algorithms"
 	"bctbackend/commands/common"
 	"bctbackend/server"
 	"bctbackend/server/configuration"
+	"bctbackend/server/logging"
 	"database/sql"
 	"fmt"
-	"path"
+	"log/slog"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -33,15 +33,45 @@ func NewServerCommand() *cobra.Command {
 		},
 	}
 
+	command.CobraCommand.Flags().String("bind", "localhost", "Address to listen on; use 0.0.0.0 to accept connections from other devices")
 	command.CobraCommand.Flags().Int("port", 8000, "Port to run the server on")
 	command.CobraCommand.Flags().Bool("debug", false, "Run server in debug mode")
 	command.CobraCommand.Flags().String("html", "index.html", "Path to the HTML file to serve")
+	command.CobraCommand.Flags().String("tls-cert", "", "Path to the TLS certificate; enables HTTPS")
+	command.CobraCommand.Flags().String("tls-key", "", "Path to the TLS private key")
+	command.CobraCommand.Flags().Bool("tls-self-signed", false, "Generate a self-signed certificate if the certificate does not exist yet")
+	command.CobraCommand.Flags().StringSlice("allowed-origins", []string{}, "Origins from which cross-origin requests and websocket connections are accepted")
+	command.CobraCommand.Flags().Bool("allow-all-origins", false, "Accept requests from any origin; only use during development")
+	command.CobraCommand.Flags().String("cookie-domain", "", "Domain of the session cookie; defaults to the host the request was sent to")
+	command.CobraCommand.Flags().String("log-format", logging.TextFormat, "Format of log messages: text or json")
+	command.CobraCommand.Flags().String("log-file", "", "File to write log messages to; defaults to stdout")
+	viper.BindPFlag("bind", command.CobraCommand.Flags().Lookup("bind"))
 	viper.BindPFlag("port", command.CobraCommand.Flags().Lookup("port"))
 	viper.BindPFlag("debug", command.CobraCommand.Flags().Lookup("debug"))
 	viper.BindPFlag("html", command.CobraCommand.Flags().Lookup("html"))
+	viper.BindPFlag("tls.certificate", command.CobraCommand.Flags().Lookup("tls-cert"))
+	viper.BindPFlag("tls.key", command.CobraCommand.Flags().Lookup("tls-key"))
+	viper.BindPFlag("tls.self-signed", command.CobraCommand.Flags().Lookup("tls-self-signed"))
+	viper.BindPFlag("origins.allowed", command.CobraCommand.Flags().Lookup("allowed-origins"))
+	viper.BindPFlag("origins.allow-all", command.CobraCommand.Flags().Lookup("allow-all-origins"))
+	viper.BindPFlag("cookie.domain", command.CobraCommand.Flags().Lookup("cookie-domain"))
+	viper.BindPFlag("log.format", command.CobraCommand.Flags().Lookup("log-format"))
+	viper.BindPFlag("log.file", command.CobraCommand.Flags().Lookup("log-file"))
+	viper.SetDefault("bind", "localhost")
 	viper.SetDefault("port", 8000)
 	viper.SetDefault("debug", false)
 	viper.SetDefault("html", "index.html")
+	viper.SetDefault("tls.certificate", "")
+	viper.SetDefault("tls.key", "")
+	viper.SetDefault("tls.self-signed", false)
+	viper.SetDefault("origins.allowed", []string{})
+	viper.SetDefault("origins.allow-all", false)
+	viper.SetDefault("cookie.domain", "")
+	viper.SetDefault("log.format", logging.TextFormat)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.file", "")
+	viper.SetDefault("log.max-size", 10)
+	viper.SetDefault("log.max-backups", 5)
 
 	return command.AsCobraCommand()
 }
@@ -56,6 +86,19 @@ func (c *ServerCommand) execute() error {
 		return err
 	}
 
+	loggingOptions, err := c.getLoggingOptions()
+	if err != nil {
+		return err
+	}
+
+	logger, logFile, err := logging.NewLogger(loggingOptions)
+	if err != nil {
+		c.PrintErrorf("Failed to set up logging: %v\n", err)
+		return err
+	}
+	defer logFile.Close()
+	slog.SetDefault(logger)
+
 	return c.WithOpenedDatabase(func(db *sql.DB) error {
 		if err := server.StartServer(db, configuration); err != nil {
 			c.PrintErrorf("Failed to start REST service\n")
@@ -82,6 +125,11 @@ func (c *ServerCommand) getConfiguration() (*configuration.Configuration, error)
 		return nil, err
 	}
 
+	minimumFontSize, err := c.GetConfigurationFloat(common.FlagMinimumFontSize)
+	if err != nil {
+		return nil, err
+	}
+
 	barcodeWidth, err := c.GetConfigurationInt(common.FlagBarcodeWidth)
 	if err != nil {
 		return nil, err
@@ -92,6 +140,16 @@ func (c *ServerCommand) getConfiguration() (*configuration.Configuration, error)
 		return nil, err
 	}
 
+	barcodeSettings, err := c.GetBarcodeSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	bindAddress, err := c.GetConfigurationString("bind")
+	if err != nil {
+		return nil, err
+	}
+
 	port, err := c.GetConfigurationInt("port")
 	if err != nil {
 		return nil, err
@@ -114,42 +172,126 @@ func (c *ServerCommand) getConfiguration() (*configuration.Configuration, error)
 		return nil, err
 	}
 
+	tlsCertificatePath, err := c.GetConfigurationString("tls.certificate")
+	if err != nil {
+		return nil, err
+	}
+
+	tlsKeyPath, err := c.GetConfigurationString("tls.key")
+	if err != nil {
+		return nil, err
+	}
+
+	generateSelfSignedCertificate, err := c.GetConfigurationBool("tls.self-signed")
+	if err != nil {
+		return nil, err
+	}
+
+	if (tlsCertificatePath == "") != (tlsKeyPath == "") {
+		c.PrintErrorf("TLS certificate and key must be specified together\n")
+		return nil, fmt.Errorf("tls certificate and key must be specified together")
+	}
+
+	if generateSelfSignedCertificate && tlsCertificatePath == "" {
+		c.PrintErrorf("Generating a self-signed certificate requires the certificate and key paths\n")
+		return nil, fmt.Errorf("generating a self-signed certificate requires the certificate and key paths")
+	}
+
+	cookieDomain, err := c.GetConfigurationString("cookie.domain")
+	if err != nil {
+		return nil, err
+	}
+
+	allowedOrigins, err := c.GetConfigurationStringSlice("origins.allowed")
+	if err != nil {
+		return nil, err
+	}
+
+	allowAllOrigins, err := c.GetConfigurationBool("origins.allow-all")
+	if err != nil {
+		return nil, err
+	}
+
+	labelTemplates, err := c.GetLabelTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	zplConfiguration, err := c.GetZplConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
 	return &configuration.Configuration{
-		FontDirectory: fontDirectory,
-		FontFilename:  fontFilename,
-		FontFamily:    fontFamily,
-		BarcodeWidth:  barcodeWidth,
-		BarcodeHeight: barcodeHeight,
-		Port:          port,
-		GinMode:       ginMode,
-		HTMLPath:      htmlPath,
+		FontDirectory:                 fontDirectory,
+		FontFilename:                  fontFilename,
+		FontFamily:                    fontFamily,
+		MinimumFontSize:               minimumFontSize,
+		BarcodeRenderer:               barcodeSettings.Renderer,
+		BarcodeSymbology:              barcodeSettings.Symbology,
+		BarcodeModuleWidth:            barcodeSettings.ModuleWidth,
+		BarcodeBarHeight:              barcodeSettings.BarHeight,
+		BarcodeShowText:               barcodeSettings.ShowText,
+		BarcodeWidth:                  barcodeWidth,
+		BarcodeHeight:                 barcodeHeight,
+		ZplLabelWidth:                 zplConfiguration.LabelWidth,
+		ZplLabelHeight:                zplConfiguration.LabelHeight,
+		ZplLabelPadding:               zplConfiguration.LabelPadding,
+		ZplDpi:                        zplConfiguration.Dpi,
+		ZplFontSize:                   zplConfiguration.FontSize,
+		BindAddress:                   bindAddress,
+		Port:                          port,
+		GinMode:                       ginMode,
+		HTMLPath:                      htmlPath,
+		TLSCertificatePath:            tlsCertificatePath,
+		TLSKeyPath:                    tlsKeyPath,
+		GenerateSelfSignedCertificate: generateSelfSignedCertificate,
+		CookieDomain:                  cookieDomain,
+		AllowedOrigins:                allowedOrigins,
+		AllowAllOrigins:               allowAllOrigins,
+		LabelTemplates:                labelTemplates,
 	}, nil
 }
 
-func (c *ServerCommand) ensureRequiredFilesExist(configuration *configuration.Configuration) error {
-	fontPath := path.Join(configuration.FontDirectory, configuration.FontFilename)
-	if err := c.ensureFileExists(fontPath); err != nil {
-		return fmt.Errorf("failed while checking font file existence: %w", err)
+func (c *ServerCommand) getLoggingOptions() (*logging.Options, error) {
+	format, err := c.GetConfigurationString("log.format")
+	if err != nil {
+		return nil, err
 	}
 
-	if err := c.ensureFileExists(configuration.HTMLPath); err != nil {
-		return fmt.Errorf("failed while checking for html file existence: %w", err)
+	level, err := c.GetConfigurationString("log.level")
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
+	file, err := c.GetConfigurationString("log.file")
+	if err != nil {
+		return nil, err
+	}
 
-func (c *ServerCommand) ensureFileExists(path string) error {
-	exists, err := algorithms.FileExists(path)
+	maxSize, err := c.GetConfigurationInt("log.max-size")
+	if err != nil {
+		return nil, err
+	}
 
+	maxBackups, err := c.GetConfigurationInt("log.max-backups")
 	if err != nil {
-		c.PrintErrorf("Failed to check if file exists: %v\n", err)
-		return fmt.Errorf("failed to check if file exists: %w", err)
+		return nil, err
 	}
 
-	if !exists {
-		c.PrintErrorf("Required file does not exist: %s\n", path)
-		return fmt.Errorf("required file does not exist: %s", path)
+	return &logging.Options{
+		Format:             format,
+		Level:              level,
+		File:               file,
+		MaxSizeInMegabytes: maxSize,
+		MaxBackups:         maxBackups,
+	}, nil
+}
+
+func (c *ServerCommand) ensureRequiredFilesExist(configuration *configuration.Configuration) error {
+	if err := configuration.CheckRequiredFiles(); err != nil {
+		c.PrintErrorf("%v\n", err)
+		return err
 	}
 
 	return nil
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"bctbackend/commands/common"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"database/sql"
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+type sessionListCommand struct {
+	common.Command
+	userId      int
+	showExpired bool
+}
+
+func NewSessionListCommand() *cobra.Command {
+	var command *sessionListCommand
+
+	command = &sessionListCommand{
+		Command: common.Command{
+			CobraCommand: &cobra.Command{
+				Use:   "list",
+				Short: "List sessions",
+				Long:  `This command lists all active sessions in the database.`,
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return command.execute()
+				},
+			},
+		},
+	}
+
+	command.CobraCommand.Flags().IntVar(&command.userId, "user", 0, "Only list sessions of this user")
+	command.CobraCommand.Flags().BoolVar(&command.showExpired, "expired", false, "Also list expired sessions that have not been cleaned up yet")
+
+	return command.AsCobraCommand()
+}
+
+func (c *sessionListCommand) execute() error {
+	return c.WithOpenedDatabase(func(db *sql.DB) error {
+		sessions, err := c.getSessions(db)
+		if err != nil {
+			c.PrintErrorf("Failed to get sessions\n")
+			return fmt.Errorf("error while listing sessions: %w", err)
+		}
+
+		tableData := pterm.TableData{
+			{"Session ID", "User", "Created At", "Expires At"},
+		}
+
+		now := models.Now()
+		sessionCount := 0
+
+		for _, session := range sessions {
+			if !c.showExpired && session.ExpirationTime <= now {
+				continue
+			}
+
+			tableData = append(tableData, []string{
+				string(session.SessionID),
+				session.UserID.String(),
+				session.CreatedAt.FormattedDateTime(),
+				session.ExpirationTime.FormattedDateTime(),
+			})
+
+			sessionCount++
+		}
+
+		if sessionCount == 0 {
+			c.Printf("No sessions found\n")
+			return nil
+		}
+
+		if err := pterm.DefaultTable.WithHasHeader().WithHeaderRowSeparator("-").WithData(tableData).Render(); err != nil {
+			c.PrintErrorf("Failed to render table: %v\n", err)
+			return fmt.Errorf("error while rendering table: %w", err)
+		}
+
+		c.Printf("Number of sessions listed: %d\n", sessionCount)
+		return nil
+	})
+}
+
+func (c *sessionListCommand) getSessions(db *sql.DB) ([]models.Session, error) {
+	if c.CobraCommand.Flags().Changed("user") {
+		return queries.GetSessionsOfUser(db, models.Id(c.userId))
+	}
+
+	return queries.GetSessions(db)
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"bctbackend/commands/common"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"database/sql"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+)
+
+type sessionRevokeCommand struct {
+	common.Command
+	userId int
+}
+
+func NewSessionRevokeCommand() *cobra.Command {
+	var command *sessionRevokeCommand
+
+	command = &sessionRevokeCommand{
+		Command: common.Command{
+			CobraCommand: &cobra.Command{
+				Use:   "revoke [session-id...]",
+				Short: "Revokes sessions",
+				Long: heredoc.Doc(`
+				This command revokes the given sessions, logging out whoever uses them.
+				Use the --user flag to log a user out everywhere instead.
+				`),
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return command.execute(args)
+				},
+			},
+		},
+	}
+
+	command.CobraCommand.Flags().IntVar(&command.userId, "user", 0, "Revoke all sessions of this user")
+
+	return command.AsCobraCommand()
+}
+
+func (c *sessionRevokeCommand) execute(args []string) error {
+	revokeUserSessions := c.CobraCommand.Flags().Changed("user")
+
+	if !revokeUserSessions && len(args) == 0 {
+		c.PrintErrorf("Specify session IDs or a user\n")
+		return fmt.Errorf("no sessions specified")
+	}
+
+	return c.WithOpenedDatabase(func(db *sql.DB) error {
+		for _, arg := range args {
+			if err := queries.DeleteSession(db, models.SessionId(arg)); err != nil {
+				c.PrintErrorf("Failed to revoke session %s: %v\n", arg, err)
+				return err
+			}
+
+			c.Printf("Revoked session %s\n", arg)
+		}
+
+		if revokeUserSessions {
+			removedSessionCount, err := queries.DeleteSessionsOfUser(db, models.Id(c.userId))
+			if err != nil {
+				c.PrintErrorf("Failed to revoke sessions of user %d: %v\n", c.userId, err)
+				return err
+			}
+
+			c.Printf("Revoked %d session(s) of user %d\n", removedSessionCount, c.userId)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,18 @@
+package session
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewSessionCommand() *cobra.Command {
+	command := cobra.Command{
+		Use:   "session",
+		Short: "Manage sessions",
+		Long:  `Commands to manage login sessions in the BCT backend system.`,
+	}
+
+	command.AddCommand(NewSessionListCommand())
+	command.AddCommand(NewSessionRevokeCommand())
+
+	return &command
+}
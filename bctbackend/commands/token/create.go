@@ -0,0 +1,98 @@
+package token
+
+import (
+	"bctbackend/commands/common"
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+)
+
+type tokenCreateCommand struct {
+	common.Command
+	userId      int
+	description string
+	actions     []string
+	expiresIn   time.Duration
+}
+
+func NewTokenCreateCommand() *cobra.Command {
+	var command *tokenCreateCommand
+
+	command = &tokenCreateCommand{
+		Command: common.Command{
+			CobraCommand: &cobra.Command{
+				Use:   "create",
+				Short: "Create an API token",
+				Long: heredoc.Doc(`
+				This command creates an API token that acts on behalf of the given user.
+				Clients pass it in the "Authorization: Bearer <token>" header.
+
+				By default, the token can be used for everything the user's role allows.
+				Use --action (repeatedly) to restrict it to specific actions.
+				The token is printed only once; only its hash is stored.
+				`),
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return command.execute()
+				},
+			},
+		},
+	}
+
+	command.CobraCommand.Flags().IntVar(&command.userId, "user", 0, "ID of the user the token acts on behalf of")
+	command.CobraCommand.Flags().StringVar(&command.description, "description", "", "Description of the token, e.g., where it is used")
+	command.CobraCommand.Flags().StringSliceVar(&command.actions, "action", nil, "Action the token is restricted to (can be repeated)")
+	command.CobraCommand.Flags().DurationVar(&command.expiresIn, "expires-in", 0, "Time until the token expires, e.g., 72h (default: never)")
+	command.CobraCommand.MarkFlagRequired("user")
+
+	return command.AsCobraCommand()
+}
+
+func (c *tokenCreateCommand) execute() error {
+	if c.expiresIn < 0 {
+		c.PrintErrorf("Expiration time must lie in the future\n")
+		return fmt.Errorf("negative expiration duration %s", c.expiresIn)
+	}
+
+	return c.WithOpenedDatabase(func(db *sql.DB) error {
+		user, err := queries.GetUserWithId(db, models.Id(c.userId))
+		if err != nil {
+			if errors.Is(err, dberr.ErrNoSuchUser) {
+				c.PrintErrorf("User with ID %d does not exist\n", c.userId)
+				return err
+			}
+
+			c.PrintErrorf("Failed to get user with ID %d\n", c.userId)
+			return err
+		}
+
+		if err := authorization.ValidateTokenActions(user.RoleId, c.actions); err != nil {
+			c.PrintErrorf("Invalid action: %s\n", err.Error())
+			return err
+		}
+
+		var expirationTime *models.Timestamp
+		if c.expiresIn > 0 {
+			timestamp := models.Now() + models.Timestamp(c.expiresIn/time.Second)
+			expirationTime = &timestamp
+		}
+
+		tokenId, token, err := queries.AddApiToken(db, user.UserId, c.description, c.actions, expirationTime)
+		if err != nil {
+			c.PrintErrorf("Failed to create token\n")
+			return err
+		}
+
+		c.Printf("Created token %d\n", tokenId)
+		c.Printf("%s\n", token)
+		c.Printf("Store it safely: it cannot be shown again\n")
+		return nil
+	})
+}
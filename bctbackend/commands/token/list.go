@@ -0,0 +1,83 @@
+package token
+
+import (
+	"bctbackend/commands/common"
+	"bctbackend/database/queries"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+type tokenListCommand struct {
+	common.Command
+}
+
+func NewTokenListCommand() *cobra.Command {
+	var command *tokenListCommand
+
+	command = &tokenListCommand{
+		Command: common.Command{
+			CobraCommand: &cobra.Command{
+				Use:   "list",
+				Short: "List API tokens",
+				Long:  `This command lists all API tokens in the database.`,
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return command.execute()
+				},
+			},
+		},
+	}
+
+	return command.AsCobraCommand()
+}
+
+func (c *tokenListCommand) execute() error {
+	return c.WithOpenedDatabase(func(db *sql.DB) error {
+		tokens, err := queries.GetApiTokens(db)
+		if err != nil {
+			c.PrintErrorf("Failed to get tokens\n")
+			return fmt.Errorf("error while listing tokens: %w", err)
+		}
+
+		if len(tokens) == 0 {
+			c.Printf("No tokens found\n")
+			return nil
+		}
+
+		tableData := pterm.TableData{
+			{"ID", "User", "Description", "Actions", "Created At", "Expires At"},
+		}
+
+		for _, token := range tokens {
+			actions := "all"
+			if len(token.Actions) > 0 {
+				actions = strings.Join(token.Actions, ", ")
+			}
+
+			expirationTime := "never"
+			if token.ExpirationTime != nil {
+				expirationTime = token.ExpirationTime.FormattedDateTime()
+			}
+
+			tableData = append(tableData, []string{
+				token.TokenId.String(),
+				token.UserId.String(),
+				token.Description,
+				actions,
+				token.CreatedAt.FormattedDateTime(),
+				expirationTime,
+			})
+		}
+
+		if err := pterm.DefaultTable.WithHasHeader().WithHeaderRowSeparator("-").WithData(tableData).Render(); err != nil {
+			c.PrintErrorf("Failed to render table: %v\n", err)
+			return fmt.Errorf("error while rendering table: %w", err)
+		}
+
+		c.Printf("Number of tokens listed: %d\n", len(tokens))
+		return nil
+	})
+}
@@ -0,0 +1,55 @@
+package token
+
+import (
+	"bctbackend/commands/common"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"database/sql"
+
+	"github.com/spf13/cobra"
+)
+
+type tokenRevokeCommand struct {
+	common.Command
+}
+
+func NewTokenRevokeCommand() *cobra.Command {
+	var command *tokenRevokeCommand
+
+	command = &tokenRevokeCommand{
+		Command: common.Command{
+			CobraCommand: &cobra.Command{
+				Use:   "revoke <token-id...>",
+				Short: "Revokes API tokens",
+				Long:  `This command revokes the given API tokens. Clients using them are rejected from then on.`,
+				Args:  cobra.MinimumNArgs(1),
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return command.execute(args)
+				},
+			},
+		},
+	}
+
+	return command.AsCobraCommand()
+}
+
+func (c *tokenRevokeCommand) execute(args []string) error {
+	return c.WithOpenedDatabase(func(db *sql.DB) error {
+		for _, arg := range args {
+			tokenId, err := models.ParseId(arg)
+			if err != nil {
+				c.PrintErrorf("Invalid token ID: %s\n", arg)
+				return err
+			}
+
+			if err := queries.RemoveApiToken(db, tokenId); err != nil {
+				c.PrintErrorf("Failed to revoke token %d: %v\n", tokenId, err)
+				return err
+			}
+
+			c.Printf("Revoked token %d\n", tokenId)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,19 @@
+package token
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewTokenCommand() *cobra.Command {
+	command := cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens",
+		Long:  `Commands to manage API tokens used by non-browser clients such as scripts, kiosks and label printing stations.`,
+	}
+
+	command.AddCommand(NewTokenCreateCommand())
+	command.AddCommand(NewTokenListCommand())
+	command.AddCommand(NewTokenRevokeCommand())
+
+	return &command
+}
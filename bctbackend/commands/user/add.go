@@ -1,10 +1,13 @@
 package user
 
 import (
+	"bctbackend/algorithms"
 	"bctbackend/commands/common"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -33,7 +36,7 @@ func NewUserAddCommand() *cobra.Command {
 	}
 
 	command.CobraCommand.Flags().IntVar(&command.userId, "id", 0, "ID of the user to add")
-	command.CobraCommand.Flags().StringVar(&command.role, "role", "", "Role of the user (admin, seller, cashier)")
+	command.CobraCommand.Flags().StringVar(&command.role, "role", "", fmt.Sprintf("Role of the user (%s)", strings.Join(roleNames(), ", ")))
 	command.CobraCommand.Flags().StringVar(&command.password, "password", "", "Password for the user")
 	command.CobraCommand.MarkFlagRequired("id")
 	command.CobraCommand.MarkFlagRequired("role")
@@ -50,7 +53,7 @@ func (c *AddUserCommand) execute() error {
 	return c.WithOpenedDatabase(func(db *sql.DB) error {
 		roleId, err := models.ParseRole(role)
 		if err != nil {
-			c.PrintErrorf("Invalid role; should be admin, seller or cashier\n")
+			c.PrintErrorf("Invalid role; should be one of %s\n", strings.Join(roleNames(), ", "))
 			return err
 		}
 
@@ -66,3 +69,7 @@ func (c *AddUserCommand) execute() error {
 		return nil
 	})
 }
+
+func roleNames() []string {
+	return algorithms.Map(models.Roles(), models.RoleId.Name)
+}
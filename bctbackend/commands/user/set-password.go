@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 )
 
@@ -22,8 +23,12 @@ func NewUserSetPasswordCommand() *cobra.Command {
 			CobraCommand: &cobra.Command{
 				Use:   "set-password <user-id> <new-password>",
 				Short: "Sets user password",
-				Long:  `This command updates a user's password.`,
-				Args:  cobra.ExactArgs(2),
+				Long: heredoc.Doc(`
+				This command updates a user's password.
+				All sessions of the user are revoked, so that they need to log in again
+				using the new password. A running server notices this within a short delay.
+				`),
+				Args: cobra.ExactArgs(2),
 				RunE: func(cmd *cobra.Command, args []string) error {
 					return command.execute(args)
 				},
@@ -50,7 +55,15 @@ func (c *SetUserPasswordCommand) execute(args []string) error {
 			return fmt.Errorf("failed to update database: %w", err)
 		}
 
-		c.Printf("Password updated successfully")
+		c.Printf("Password updated successfully\n")
+
+		removedSessionCount, err := queries.DeleteSessionsOfUser(db, userId)
+		if err != nil {
+			c.PrintErrorf("Failed to revoke sessions of user\n")
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+
+		c.Printf("Revoked %d session(s)\n", removedSessionCount)
 		return nil
 	})
 }
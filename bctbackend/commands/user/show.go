@@ -60,29 +60,20 @@ func (c *showUserCommand) execute(args []string) error {
 		}
 
 		// Display user information based on their role
-		return models.VisitRole(user.RoleId, &showUser{command: c, database: db, user: user})
+		switch {
+		case user.RoleId.IsSeller():
+			return c.showSeller(db, user)
+		case user.RoleId.IsCashier():
+			return c.showCashier(db, user)
+		default:
+			return c.showUser(user)
+		}
 	})
 }
 
-type showUser struct {
-	command  *showUserCommand
-	database *sql.DB
-	user     *models.User
-}
-
-func (s *showUser) Admin() error {
-	return s.command.showAdmin(s.user)
-}
-
-func (s *showUser) Seller() error {
-	return s.command.showSeller(s.database, s.user)
-}
-
-func (s *showUser) Cashier() error {
-	return s.command.showCashier(s.database, s.user)
-}
-
-func (c *showUserCommand) showAdmin(user *models.User) error {
+// showUser shows the data common to all users.
+// Roles without role-specific information, such as admins, only get this.
+func (c *showUserCommand) showUser(user *models.User) error {
 	pterm.DefaultSection.Println("User Data")
 
 	if err := c.printUserTable(user); err != nil {
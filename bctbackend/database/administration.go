@@ -2,6 +2,7 @@ package database
 
 import (
 	"bctbackend/algorithms"
+	"bctbackend/barcode"
 	dberr "bctbackend/database/errors"
 	models "bctbackend/database/models"
 	"database/sql"
@@ -98,6 +99,16 @@ func OpenDatabase(path string) (*sql.DB, error) {
 	return db, nil
 }
 
+// Checkpoint moves all changes from the write-ahead log into the database file and truncates the log,
+// so that the database file is complete by itself, e.g., for backups.
+func Checkpoint(db *sql.DB) error {
+	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+
+	return nil
+}
+
 func ResetDatabase(db *sql.DB) error {
 	if err := removeAllViews(db); err != nil {
 		return err
@@ -110,6 +121,11 @@ func ResetDatabase(db *sql.DB) error {
 	return InitializeDatabase(db)
 }
 
+// SchemaVersion identifies the layout of the tables the code expects.
+// It must be incremented whenever tables or columns change, so that outdated databases are detected.
+// Databases created before versions were recorded have version 0.
+const SchemaVersion = 1
+
 func InitializeDatabase(db *sql.DB) error {
 	if err := createTables(db); err != nil {
 		return fmt.Errorf("failed to create tables: %w", err)
@@ -123,13 +139,52 @@ func InitializeDatabase(db *sql.DB) error {
 		return fmt.Errorf("failed to populate tables: %w", err)
 	}
 
+	// PRAGMA does not support parameters
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
 	return nil
 }
 
-func removeAllViews(db *sql.DB) error {
-	views := []string{"visible_items", "hidden_items"}
+// Tables and views in the order in which they can be dropped without violating foreign key constraints
+var (
+	tableNames = []string{"event", "label_layouts", "api_tokens", "sessions", "sale_items", "sales", "items", "item_categories", "users", "roles"}
+	viewNames  = []string{"visible_items", "hidden_items"}
+)
+
+// CheckSchema verifies that the database has the schema version the code expects and that all tables and views exist.
+func CheckSchema(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if version != SchemaVersion {
+		return fmt.Errorf("schema version is %d, expected %d", version, SchemaVersion)
+	}
+
+	for _, names := range []struct {
+		kind  string
+		names []string
+	}{{"table", tableNames}, {"view", viewNames}} {
+		for _, name := range names.names {
+			var count int
+			if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?", names.kind, name).Scan(&count); err != nil {
+				return fmt.Errorf("failed to check existence of %s %s: %w", names.kind, name, err)
+			}
+
+			if count == 0 {
+				return fmt.Errorf("missing %s %s", names.kind, name)
+			}
+		}
+	}
+
+	return nil
+}
 
-	for _, view := range views {
+func removeAllViews(db *sql.DB) error {
+	for _, view := range viewNames {
 		if err := dropView(db, view); err != nil {
 			return fmt.Errorf("failed to drop view %s: %w", view, err)
 		}
@@ -139,9 +194,7 @@ func removeAllViews(db *sql.DB) error {
 }
 
 func removeAllTables(db *sql.DB) error {
-	tables := []string{"sessions", "sale_items", "sales", "items", "item_categories", "users", "roles"}
-
-	for _, table := range tables {
+	for _, table := range tableNames {
 		if err := dropTable(db, table); err != nil {
 			return fmt.Errorf("failed to drop all tables: %w", err)
 		}
@@ -199,6 +252,18 @@ func createTables(db *sql.DB) error {
 		return fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	if err := createApiTokenTable(db); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	if err := createLabelLayoutTable(db); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	if err := createEventTable(db); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+
 	return nil
 }
 
@@ -341,6 +406,7 @@ func createSessionTable(db *sql.DB) error {
 		CREATE TABLE sessions (
 			session_id          TEXT NOT NULL,
 			user_id             INTEGER NOT NULL,
+			created_at          INTEGER NOT NULL,
 			expiration_time     INTEGER NOT NULL,
 
 			PRIMARY KEY (session_id),
@@ -355,34 +421,124 @@ func createSessionTable(db *sql.DB) error {
 	return nil
 }
 
+func createApiTokenTable(db *sql.DB) error {
+	slog.Debug("Creating api_tokens table")
+
+	_, err := db.Exec(`
+		CREATE TABLE api_tokens (
+			token_id            INTEGER NOT NULL,
+			token_hash          TEXT NOT NULL UNIQUE,
+			user_id             INTEGER NOT NULL,
+			description         TEXT NOT NULL,
+			actions             TEXT NOT NULL,
+			created_at          INTEGER NOT NULL,
+			expiration_time     INTEGER,
+
+			PRIMARY KEY (token_id),
+			CONSTRAINT api_token_foreign_key_user FOREIGN KEY (user_id) REFERENCES users (user_id)
+		)
+	`)
+
+	if err != nil {
+		return fmt.Errorf("failed to create api_tokens table: %w", err)
+	}
+
+	return nil
+}
+
+// createEventTable creates a table holding a single row with information about the event the database is used for.
+func createEventTable(db *sql.DB) error {
+	slog.Debug("Creating event table")
+
+	_, err := db.Exec(`
+		CREATE TABLE event (
+			event_id       INTEGER NOT NULL CHECK (event_id = 1),
+			barcode_prefix TEXT NOT NULL,
+
+			PRIMARY KEY (event_id)
+		)
+	`)
+
+	if err != nil {
+		return fmt.Errorf("failed to create event table: %w", err)
+	}
+
+	return nil
+}
+
+func createLabelLayoutTable(db *sql.DB) error {
+	slog.Debug("Creating label_layouts table")
+
+	_, err := db.Exec(`
+		CREATE TABLE label_layouts (
+			layout_id            INTEGER NOT NULL,
+			name                 TEXT NOT NULL UNIQUE CHECK (LENGTH(name) > 0),
+			paper_width          REAL NOT NULL,
+			paper_height         REAL NOT NULL,
+			paper_margin_top     REAL NOT NULL,
+			paper_margin_right   REAL NOT NULL,
+			paper_margin_bottom  REAL NOT NULL,
+			paper_margin_left    REAL NOT NULL,
+			column_count         INTEGER NOT NULL,
+			row_count            INTEGER NOT NULL,
+			label_margin_top     REAL NOT NULL,
+			label_margin_right   REAL NOT NULL,
+			label_margin_bottom  REAL NOT NULL,
+			label_margin_left    REAL NOT NULL,
+			label_padding_top    REAL NOT NULL,
+			label_padding_right  REAL NOT NULL,
+			label_padding_bottom REAL NOT NULL,
+			label_padding_left   REAL NOT NULL,
+			font_size            REAL NOT NULL,
+			template             TEXT NOT NULL,
+			symbology            TEXT NOT NULL,
+
+			PRIMARY KEY (layout_id)
+		)
+	`)
+
+	if err != nil {
+		return fmt.Errorf("failed to create label_layouts table: %w", err)
+	}
+
+	return nil
+}
+
 func populateTables(db *sql.DB) error {
 	if err := populateRoleTable(db); err != nil {
 		return err
 	}
 
+	if err := populateEventTable(db); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func populateRoleTable(db *sql.DB) error {
 	slog.Debug("Populating roles table")
 
-	_, err := db.Exec(`
-			INSERT INTO roles (role_id, name)
-			VALUES
-				($1, $2),
-				($3, $4),
-				($5, $6)
-		`,
-		models.AdminRoleId,
-		models.AdminName,
-		models.SellerRoleId,
-		models.SellerName,
-		models.CashierRoleId,
-		models.CashierName,
-	)
+	for _, roleId := range models.Roles() {
+		if _, err := db.Exec(`INSERT INTO roles (role_id, name) VALUES (?, ?)`, roleId.Id, roleId.Name()); err != nil {
+			return fmt.Errorf("failed to populate roles: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// populateEventTable generates the event prefix, which sets barcodes of this database apart from those of other events.
+func populateEventTable(db *sql.DB) error {
+	slog.Debug("Populating event table")
 
+	prefix, err := barcode.GenerateEventPrefix()
 	if err != nil {
-		return fmt.Errorf("failed to populate roles: %w", err)
+		return fmt.Errorf("failed to populate event: %w", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO event (event_id, barcode_prefix) VALUES (1, ?)`, prefix); err != nil {
+		return fmt.Errorf("failed to populate event: %w", err)
 	}
 
 	return nil
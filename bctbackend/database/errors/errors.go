@@ -2,6 +2,9 @@ package errors
 
 import (
 	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 var ErrIdAlreadyInUse = errors.New("id already in use")
@@ -13,6 +16,7 @@ var ErrDuplicateItemInSale = errors.New("duplicate item in sale")
 var ErrItemFrozen = errors.New("item is frozen")
 var ErrItemHidden = errors.New("item is hidden")
 var ErrHiddenFrozenItem = errors.New("items cannot be hidden and frozen at the same time")
+var ErrLabelLayoutNameInUse = errors.New("label layout name already in use")
 var ErrDatabaseAlreadyExists = errors.New("database already exists")
 
 var ErrNoSuchUser = errors.New("no such user")
@@ -21,7 +25,21 @@ var ErrNoSuchSale = errors.New("no such sale")
 var ErrNoSuchSession = errors.New("no such session")
 var ErrNoSuchCategory = errors.New("no such category")
 var ErrNoSuchRole = errors.New("no such role")
+var ErrNoSuchApiToken = errors.New("no such api token")
+var ErrNoSuchLabelLayout = errors.New("no such label layout")
 
 var ErrInvalidPrice = errors.New("invalid price")
 var ErrInvalidItemDescription = errors.New("invalid item description")
 var ErrInvalidCategoryName = errors.New("category name is invalid")
+var ErrInvalidLabelLayoutName = errors.New("label layout name is invalid")
+
+// IsDatabaseBusy checks whether the error was caused by the database being locked by another connection.
+func IsDatabaseBusy(err error) bool {
+	var sqliteError *sqlite.Error
+	if !errors.As(err, &sqliteError) {
+		return false
+	}
+
+	// Extended result codes, e.g., SQLITE_BUSY_SNAPSHOT, keep the primary code in the lowest byte
+	return sqliteError.Code()&0xff == sqlite3.SQLITE_BUSY
+}
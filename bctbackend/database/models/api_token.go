@@ -0,0 +1,16 @@
+package models
+
+type ApiToken struct {
+	TokenId     Id
+	UserId      Id
+	Description string
+
+	// Actions the token is restricted to. An empty list means the token
+	// can be used for everything its owner's role is allowed to do.
+	Actions []string
+
+	CreatedAt Timestamp
+
+	// Nil for tokens that never expire
+	ExpirationTime *Timestamp
+}
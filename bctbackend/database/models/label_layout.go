@@ -0,0 +1,34 @@
+package models
+
+// Insets are distances in millimeters from the edges.
+type Insets struct {
+	Top    float64
+	Bottom float64
+	Left   float64
+	Right  float64
+}
+
+// LabelLayout is a named description of a sheet of labels, e.g., "Avery L7160 21-up".
+// All lengths are in millimeters.
+type LabelLayout struct {
+	LayoutId     Id
+	Name         string
+	PaperWidth   float64
+	PaperHeight  float64
+	PaperMargins Insets
+	Columns      int
+	Rows         int
+	LabelMargins Insets
+	LabelPadding Insets
+	FontSize     float64
+
+	// Name of the label template to use; empty for the default template
+	Template string
+
+	// Barcode symbology, e.g., "qr"; empty for the symbology of the configuration
+	Symbology string
+}
+
+func IsValidLabelLayoutName(name string) bool {
+	return len(name) > 0
+}
@@ -2,24 +2,41 @@ package models
 
 import (
 	dberr "bctbackend/database/errors"
+	"cmp"
 	"fmt"
+	"slices"
 )
 
 const (
-	AdminRoleId   Id     = 1
-	SellerRoleId  Id     = 2
-	CashierRoleId Id     = 3
-	AdminName     string = "admin"
-	SellerName    string = "seller"
-	CashierName   string = "cashier"
+	AdminRoleId      Id     = 1
+	SellerRoleId     Id     = 2
+	CashierRoleId    Id     = 3
+	VolunteerRoleId  Id     = 4
+	SupervisorRoleId Id     = 5
+	AdminName        string = "admin"
+	SellerName       string = "seller"
+	CashierName      string = "cashier"
+	VolunteerName    string = "volunteer"
+	SupervisorName   string = "supervisor"
 )
 
+// roleNames lists all known roles.
+// Adding a role only requires adding an entry here; what the role is allowed to do
+// is defined in the authorization package.
+var roleNames = map[Id]string{
+	AdminRoleId:      AdminName,
+	SellerRoleId:     SellerName,
+	CashierRoleId:    CashierName,
+	VolunteerRoleId:  VolunteerName,
+	SupervisorRoleId: SupervisorName,
+}
+
 type RoleId struct {
 	Id
 }
 
 func NewRoleId(id Id) RoleId {
-	if id != AdminRoleId && id != SellerRoleId && id != CashierRoleId {
+	if _, ok := roleNames[id]; !ok {
 		panic(fmt.Sprintf("invalid role id: %d", id))
 	}
 
@@ -38,30 +55,43 @@ func NewCashierRoleId() RoleId {
 	return NewRoleId(CashierRoleId)
 }
 
+func NewVolunteerRoleId() RoleId {
+	return NewRoleId(VolunteerRoleId)
+}
+
+func NewSupervisorRoleId() RoleId {
+	return NewRoleId(SupervisorRoleId)
+}
+
+// Roles returns all known roles, ordered by id.
+func Roles() []RoleId {
+	result := make([]RoleId, 0, len(roleNames))
+	for id := range roleNames {
+		result = append(result, RoleId{Id: id})
+	}
+
+	slices.SortFunc(result, func(x, y RoleId) int { return cmp.Compare(x.Id, y.Id) })
+
+	return result
+}
+
 func (roleId RoleId) Name() string {
-	switch roleId.Id {
-	case AdminRoleId:
-		return AdminName
-	case SellerRoleId:
-		return SellerName
-	case CashierRoleId:
-		return CashierName
-	default:
+	name, ok := roleNames[roleId.Id]
+	if !ok {
 		panic(fmt.Sprintf("unknown role id: %d", roleId.Id))
 	}
+
+	return name
 }
 
 func ParseRole(role string) (RoleId, error) {
-	switch role {
-	case "admin":
-		return RoleId{Id: AdminRoleId}, nil
-	case "seller":
-		return RoleId{Id: SellerRoleId}, nil
-	case "cashier":
-		return RoleId{Id: CashierRoleId}, nil
-	default:
-		return RoleId{}, fmt.Errorf("unknown role %s: %w", role, dberr.ErrNoSuchRole)
+	for id, name := range roleNames {
+		if name == role {
+			return RoleId{Id: id}, nil
+		}
 	}
+
+	return RoleId{}, fmt.Errorf("unknown role %s: %w", role, dberr.ErrNoSuchRole)
 }
 
 func (roleId RoleId) IsAdmin() bool {
@@ -76,25 +106,15 @@ func (roleId RoleId) IsCashier() bool {
 	return roleId.Id == CashierRoleId
 }
 
-type RoleVisitor[T any] interface {
-	Admin() T
-	Seller() T
-	Cashier() T
+func (roleId RoleId) IsVolunteer() bool {
+	return roleId.Id == VolunteerRoleId
 }
 
-func VisitRole[T any](roleId RoleId, visitor RoleVisitor[T]) T {
-	switch roleId.Id {
-	case AdminRoleId:
-		return visitor.Admin()
-	case SellerRoleId:
-		return visitor.Seller()
-	case CashierRoleId:
-		return visitor.Cashier()
-	default:
-		panic(fmt.Sprintf("unknown role id: %d", roleId.Id))
-	}
+func (roleId RoleId) IsSupervisor() bool {
+	return roleId.Id == SupervisorRoleId
 }
 
 func (roleId RoleId) IsValid() bool {
-	return roleId.Id == AdminRoleId || roleId.Id == SellerRoleId || roleId.Id == CashierRoleId
+	_, ok := roleNames[roleId.Id]
+	return ok
 }
@@ -30,6 +30,20 @@ func TestRoleParsing(t *testing.T) {
 		require.Equal(t, NewCashierRoleId(), roleId)
 	})
 
+	t.Run("volunteer", func(t *testing.T) {
+		t.Parallel()
+		roleId, err := ParseRole("volunteer")
+		require.NoError(t, err)
+		require.Equal(t, NewVolunteerRoleId(), roleId)
+	})
+
+	t.Run("supervisor", func(t *testing.T) {
+		t.Parallel()
+		roleId, err := ParseRole("supervisor")
+		require.NoError(t, err)
+		require.Equal(t, NewSupervisorRoleId(), roleId)
+	})
+
 	t.Run("unknown", func(t *testing.T) {
 		t.Parallel()
 		_, err := ParseRole("invalid")
@@ -5,5 +5,6 @@ type SessionId string
 type Session struct {
 	SessionID      SessionId
 	UserID         Id
+	CreatedAt      Timestamp
 	ExpirationTime Timestamp
 }
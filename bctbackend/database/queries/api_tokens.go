@@ -0,0 +1,154 @@
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/security"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AddApiToken creates a new API token for the given user.
+// The token itself is returned only once; the database only stores its hash.
+// An empty list of actions means the token is not restricted beyond its owner's role.
+func AddApiToken(
+	db *sql.DB,
+	userId models.Id,
+	description string,
+	actions []string,
+	expirationTime *models.Timestamp) (models.Id, string, error) {
+
+	if err := EnsureUserExists(db, userId); err != nil {
+		return 0, "", fmt.Errorf("failed to add api token: %w", err)
+	}
+
+	token := security.GenerateApiToken()
+
+	result, err := db.Exec(
+		`
+			INSERT INTO api_tokens (token_hash, user_id, description, actions, created_at, expiration_time)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`,
+		security.HashApiToken(token),
+		userId,
+		description,
+		strings.Join(actions, " "),
+		models.Now(),
+		expirationTime,
+	)
+
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to insert api token: %w", err)
+	}
+
+	tokenId, err := result.LastInsertId()
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to determine id of inserted api token: %w", err)
+	}
+
+	return models.Id(tokenId), token, nil
+}
+
+type ApiTokenData struct {
+	TokenId models.Id
+	UserId  models.Id
+	RoleId  models.RoleId
+	Actions []string
+}
+
+// GetApiTokenData looks up the token and the role of its owner.
+// An ErrNoSuchApiToken is returned if the token does not exist or has expired.
+func GetApiTokenData(db *sql.DB, token string) (*ApiTokenData, error) {
+	row := db.QueryRow(
+		`
+			SELECT token_id, users.user_id, role_id, actions
+			FROM api_tokens INNER JOIN users ON api_tokens.user_id = users.user_id
+			WHERE token_hash = ? AND (expiration_time IS NULL OR ? < expiration_time)
+		`,
+		security.HashApiToken(token),
+		models.Now(),
+	)
+
+	var tokenData ApiTokenData
+	var actions string
+	if err := row.Scan(&tokenData.TokenId, &tokenData.UserId, &tokenData.RoleId.Id, &actions); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dberr.ErrNoSuchApiToken
+		}
+		return nil, err
+	}
+
+	tokenData.Actions = strings.Fields(actions)
+	return &tokenData, nil
+}
+
+// GetApiTokens returns all API tokens, including expired ones.
+func GetApiTokens(db *sql.DB) (r_result []*models.ApiToken, r_err error) {
+	rows, err := db.Query(
+		`
+			SELECT token_id, user_id, description, actions, created_at, expiration_time
+			FROM api_tokens
+			ORDER BY token_id
+		`,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { r_err = errors.Join(r_err, rows.Close()) }()
+
+	tokens := []*models.ApiToken{}
+
+	for rows.Next() {
+		var token models.ApiToken
+		var actions string
+		var expirationTime sql.NullInt64
+		if err := rows.Scan(&token.TokenId, &token.UserId, &token.Description, &actions, &token.CreatedAt, &expirationTime); err != nil {
+			return nil, err
+		}
+
+		token.Actions = strings.Fields(actions)
+		if expirationTime.Valid {
+			timestamp := models.Timestamp(expirationTime.Int64)
+			token.ExpirationTime = &timestamp
+		}
+
+		tokens = append(tokens, &token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating over rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RemoveApiToken revokes the token with the given id.
+// An ErrNoSuchApiToken is returned if there is no such token.
+func RemoveApiToken(db *sql.DB, tokenId models.Id) error {
+	result, err := db.Exec(
+		`
+			DELETE FROM api_tokens
+			WHERE token_id = ?
+		`,
+		tokenId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to remove api token %d: %w", tokenId, dberr.ErrNoSuchApiToken)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package queries
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GetEventPrefix returns the prefix that sets barcodes generated for this event apart from those of other events.
+func GetEventPrefix(db *sql.DB) (string, error) {
+	var prefix string
+
+	if err := db.QueryRow(`SELECT barcode_prefix FROM event WHERE event_id = 1`).Scan(&prefix); err != nil {
+		return "", fmt.Errorf("failed to get event prefix: %w", err)
+	}
+
+	return prefix, nil
+}
@@ -0,0 +1,245 @@
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// AddLabelLayout stores a new label layout preset.
+// The layout id of the given layout is ignored.
+// An ErrLabelLayoutNameInUse is returned if another layout already has the same name.
+func AddLabelLayout(db *sql.DB, layout *models.LabelLayout) (models.Id, error) {
+	if !models.IsValidLabelLayoutName(layout.Name) {
+		return 0, dberr.ErrInvalidLabelLayoutName
+	}
+
+	if err := ensureLabelLayoutNameIsAvailable(db, layout.Name, nil); err != nil {
+		return 0, fmt.Errorf("failed to add label layout: %w", err)
+	}
+
+	result, err := db.Exec(
+		`
+			INSERT INTO label_layouts (
+				name,
+				paper_width, paper_height,
+				paper_margin_top, paper_margin_right, paper_margin_bottom, paper_margin_left,
+				column_count, row_count,
+				label_margin_top, label_margin_right, label_margin_bottom, label_margin_left,
+				label_padding_top, label_padding_right, label_padding_bottom, label_padding_left,
+				font_size, template, symbology
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+		layout.Name,
+		layout.PaperWidth, layout.PaperHeight,
+		layout.PaperMargins.Top, layout.PaperMargins.Right, layout.PaperMargins.Bottom, layout.PaperMargins.Left,
+		layout.Columns, layout.Rows,
+		layout.LabelMargins.Top, layout.LabelMargins.Right, layout.LabelMargins.Bottom, layout.LabelMargins.Left,
+		layout.LabelPadding.Top, layout.LabelPadding.Right, layout.LabelPadding.Bottom, layout.LabelPadding.Left,
+		layout.FontSize, layout.Template, layout.Symbology,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert label layout: %w", err)
+	}
+
+	layoutId, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to determine id of inserted label layout: %w", err)
+	}
+
+	return models.Id(layoutId), nil
+}
+
+const labelLayoutColumns = `
+	layout_id, name,
+	paper_width, paper_height,
+	paper_margin_top, paper_margin_right, paper_margin_bottom, paper_margin_left,
+	column_count, row_count,
+	label_margin_top, label_margin_right, label_margin_bottom, label_margin_left,
+	label_padding_top, label_padding_right, label_padding_bottom, label_padding_left,
+	font_size, template, symbology
+`
+
+type rowScanner interface {
+	Scan(destinations ...any) error
+}
+
+func scanLabelLayout(row rowScanner) (*models.LabelLayout, error) {
+	var layout models.LabelLayout
+
+	err := row.Scan(
+		&layout.LayoutId, &layout.Name,
+		&layout.PaperWidth, &layout.PaperHeight,
+		&layout.PaperMargins.Top, &layout.PaperMargins.Right, &layout.PaperMargins.Bottom, &layout.PaperMargins.Left,
+		&layout.Columns, &layout.Rows,
+		&layout.LabelMargins.Top, &layout.LabelMargins.Right, &layout.LabelMargins.Bottom, &layout.LabelMargins.Left,
+		&layout.LabelPadding.Top, &layout.LabelPadding.Right, &layout.LabelPadding.Bottom, &layout.LabelPadding.Left,
+		&layout.FontSize, &layout.Template, &layout.Symbology,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &layout, nil
+}
+
+// GetLabelLayouts returns all label layout presets ordered by name.
+func GetLabelLayouts(db *sql.DB) (r_result []*models.LabelLayout, r_err error) {
+	rows, err := db.Query(
+		fmt.Sprintf(`
+			SELECT %s
+			FROM label_layouts
+			ORDER BY name
+		`, labelLayoutColumns),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get label layouts: %w", err)
+	}
+	defer func() { r_err = errors.Join(r_err, rows.Close()) }()
+
+	layouts := []*models.LabelLayout{}
+
+	for rows.Next() {
+		layout, err := scanLabelLayout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+
+		layouts = append(layouts, layout)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating over rows: %w", err)
+	}
+
+	return layouts, nil
+}
+
+// GetLabelLayoutWithId returns the label layout preset with the given id.
+// An ErrNoSuchLabelLayout is returned if there is no such layout.
+func GetLabelLayoutWithId(db *sql.DB, layoutId models.Id) (*models.LabelLayout, error) {
+	row := db.QueryRow(
+		fmt.Sprintf(`
+			SELECT %s
+			FROM label_layouts
+			WHERE layout_id = ?
+		`, labelLayoutColumns),
+		layoutId,
+	)
+
+	layout, err := scanLabelLayout(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get label layout %d: %w", layoutId, dberr.ErrNoSuchLabelLayout)
+		}
+
+		return nil, fmt.Errorf("failed to get label layout %d: %w", layoutId, err)
+	}
+
+	return layout, nil
+}
+
+// UpdateLabelLayout overwrites the label layout preset with the id of the given layout.
+// An ErrNoSuchLabelLayout is returned if there is no such layout and
+// an ErrLabelLayoutNameInUse if another layout already has the same name.
+func UpdateLabelLayout(db *sql.DB, layout *models.LabelLayout) error {
+	if !models.IsValidLabelLayoutName(layout.Name) {
+		return dberr.ErrInvalidLabelLayoutName
+	}
+
+	if err := ensureLabelLayoutNameIsAvailable(db, layout.Name, &layout.LayoutId); err != nil {
+		return fmt.Errorf("failed to update label layout %d: %w", layout.LayoutId, err)
+	}
+
+	result, err := db.Exec(
+		`
+			UPDATE label_layouts
+			SET name = ?,
+				paper_width = ?, paper_height = ?,
+				paper_margin_top = ?, paper_margin_right = ?, paper_margin_bottom = ?, paper_margin_left = ?,
+				column_count = ?, row_count = ?,
+				label_margin_top = ?, label_margin_right = ?, label_margin_bottom = ?, label_margin_left = ?,
+				label_padding_top = ?, label_padding_right = ?, label_padding_bottom = ?, label_padding_left = ?,
+				font_size = ?, template = ?, symbology = ?
+			WHERE layout_id = ?
+		`,
+		layout.Name,
+		layout.PaperWidth, layout.PaperHeight,
+		layout.PaperMargins.Top, layout.PaperMargins.Right, layout.PaperMargins.Bottom, layout.PaperMargins.Left,
+		layout.Columns, layout.Rows,
+		layout.LabelMargins.Top, layout.LabelMargins.Right, layout.LabelMargins.Bottom, layout.LabelMargins.Left,
+		layout.LabelPadding.Top, layout.LabelPadding.Right, layout.LabelPadding.Bottom, layout.LabelPadding.Left,
+		layout.FontSize, layout.Template, layout.Symbology,
+		layout.LayoutId,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update label layout %d: %w", layout.LayoutId, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to determine number of updated label layouts: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update label layout %d: %w", layout.LayoutId, dberr.ErrNoSuchLabelLayout)
+	}
+
+	return nil
+}
+
+// RemoveLabelLayout removes the label layout preset with the given id.
+// An ErrNoSuchLabelLayout is returned if there is no such layout.
+func RemoveLabelLayout(db *sql.DB, layoutId models.Id) error {
+	result, err := db.Exec(
+		`
+			DELETE FROM label_layouts
+			WHERE layout_id = ?
+		`,
+		layoutId,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove label layout %d: %w", layoutId, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to determine number of removed label layouts: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to remove label layout %d: %w", layoutId, dberr.ErrNoSuchLabelLayout)
+	}
+
+	return nil
+}
+
+// ensureLabelLayoutNameIsAvailable checks that no layout other than the excluded one has the given name.
+func ensureLabelLayoutNameIsAvailable(db *sql.DB, name string, excludedLayoutId *models.Id) error {
+	row := db.QueryRow(
+		`
+			SELECT layout_id
+			FROM label_layouts
+			WHERE name = ?
+		`,
+		name,
+	)
+
+	var layoutId models.Id
+	if err := row.Scan(&layoutId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to look up label layout with name %s: %w", name, err)
+	}
+
+	if excludedLayoutId != nil && *excludedLayoutId == layoutId {
+		return nil
+	}
+
+	return dberr.ErrLabelLayoutNameInUse
+}
@@ -22,11 +22,12 @@ func AddSession(
 
 	_, err := db.Exec(
 		`
-			INSERT INTO sessions (session_id, user_id, expiration_time)
-			VALUES (?, ?, ?)
+			INSERT INTO sessions (session_id, user_id, created_at, expiration_time)
+			VALUES (?, ?, ?, ?)
 		`,
 		sessionId,
 		userId,
+		models.Now(),
 		expirationTime,
 	)
 
@@ -43,7 +44,7 @@ func GetSessionById(
 
 	row := db.QueryRow(
 		`
-			SELECT user_id, expiration_time
+			SELECT user_id, created_at, expiration_time
 			FROM sessions
 			WHERE session_id = ?
 		`,
@@ -51,8 +52,9 @@ func GetSessionById(
 	)
 
 	var userId models.Id
+	var createdAt models.Timestamp
 	var expirationTime models.Timestamp
-	if err := row.Scan(&userId, &expirationTime); err != nil {
+	if err := row.Scan(&userId, &createdAt, &expirationTime); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("failed to get session with id %s: %w", sessionId, dberr.ErrNoSuchSession)
 		}
@@ -62,21 +64,24 @@ func GetSessionById(
 	session := models.Session{
 		SessionID:      sessionId,
 		UserID:         userId,
+		CreatedAt:      createdAt,
 		ExpirationTime: expirationTime,
 	}
 	return &session, nil
 }
 
 type SessionData struct {
-	UserId models.Id
-	RoleId models.RoleId
+	UserId         models.Id
+	RoleId         models.RoleId
+	CreatedAt      models.Timestamp
+	ExpirationTime models.Timestamp
 }
 
 func GetSessionData(db *sql.DB, sessionId models.SessionId) (*SessionData, error) {
 	now := models.Now()
 	row := db.QueryRow(
 		`
-			SELECT users.user_id, role_id
+			SELECT users.user_id, role_id, sessions.created_at, expiration_time
 			FROM sessions INNER JOIN users ON sessions.user_id = users.user_id
 			WHERE session_id = ? AND ? < expiration_time
 		`,
@@ -86,7 +91,9 @@ func GetSessionData(db *sql.DB, sessionId models.SessionId) (*SessionData, error
 
 	var userId models.Id
 	var roleId models.RoleId
-	if err := row.Scan(&userId, &roleId.Id); err != nil {
+	var createdAt models.Timestamp
+	var expirationTime models.Timestamp
+	if err := row.Scan(&userId, &roleId.Id, &createdAt, &expirationTime); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, dberr.ErrNoSuchSession
 		}
@@ -94,19 +101,62 @@ func GetSessionData(db *sql.DB, sessionId models.SessionId) (*SessionData, error
 	}
 
 	sessionData := SessionData{
-		UserId: userId,
-		RoleId: roleId,
+		UserId:         userId,
+		RoleId:         roleId,
+		CreatedAt:      createdAt,
+		ExpirationTime: expirationTime,
 	}
 	return &sessionData, nil
 }
 
-func GetSessions(db *sql.DB) (r_result []models.Session, r_err error) {
-	rows, err := db.Query(
+// GetSessions returns all sessions, including expired ones that have not been cleaned up yet.
+func GetSessions(db *sql.DB) ([]models.Session, error) {
+	return getSessions(
+		db,
+		`
+			SELECT session_id, user_id, created_at, expiration_time
+			FROM sessions
+		`,
+	)
+}
+
+// GetSessionWithHandle looks up the session with the given public handle (see security.DeriveSessionHandle).
+// An ErrNoSuchSession is returned if there is no such session.
+func GetSessionWithHandle(db *sql.DB, handle string) (*models.Session, error) {
+	sessions, err := GetSessions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if security.DeriveSessionHandle(session.SessionID) == handle {
+			return &session, nil
+		}
+	}
+
+	return nil, dberr.ErrNoSuchSession
+}
+
+// GetSessionsOfUser returns all sessions belonging to the given user.
+// An ErrNoSuchUser is returned if the user does not exist.
+func GetSessionsOfUser(db *sql.DB, userId models.Id) ([]models.Session, error) {
+	if err := EnsureUserExists(db, userId); err != nil {
+		return nil, fmt.Errorf("failed to get sessions of user %d: %w", userId, err)
+	}
+
+	return getSessions(
+		db,
 		`
-			SELECT session_id, user_id, expiration_time
+			SELECT session_id, user_id, created_at, expiration_time
 			FROM sessions
+			WHERE user_id = ?
 		`,
+		userId,
 	)
+}
+
+func getSessions(db *sql.DB, query string, args ...any) (r_result []models.Session, r_err error) {
+	rows, err := db.Query(query, args...)
 
 	if err != nil {
 		return nil, err
@@ -114,19 +164,21 @@ func GetSessions(db *sql.DB) (r_result []models.Session, r_err error) {
 
 	defer func() { r_err = errors.Join(r_err, rows.Close()) }()
 
-	var sessions []models.Session
+	sessions := []models.Session{}
 
 	for rows.Next() {
 		var sessionId models.SessionId
 		var userId models.Id
+		var createdAt models.Timestamp
 		var expirationTime models.Timestamp
-		if err := rows.Scan(&sessionId, &userId, &expirationTime); err != nil {
+		if err := rows.Scan(&sessionId, &userId, &createdAt, &expirationTime); err != nil {
 			return nil, err
 		}
 
 		session := models.Session{
 			SessionID:      sessionId,
 			UserID:         userId,
+			CreatedAt:      createdAt,
 			ExpirationTime: expirationTime,
 		}
 		sessions = append(sessions, session)
@@ -139,6 +191,36 @@ func GetSessions(db *sql.DB) (r_result []models.Session, r_err error) {
 	return sessions, nil
 }
 
+// RenewSession pushes the expiration time of a session forward to the given expiration time.
+// The expiration time is never moved beyond maximumDurationInSeconds after the creation of the session,
+// nor is it ever moved backwards.
+// An ErrNoSuchSession is returned if the session does not exist.
+func RenewSession(db *sql.DB, sessionId models.SessionId, expirationTime models.Timestamp, maximumDurationInSeconds int64) error {
+	result, err := db.Exec(
+		`
+			UPDATE sessions
+			SET expiration_time = MAX(expiration_time, MIN(?, created_at + ?))
+			WHERE session_id = ?
+		`,
+		expirationTime,
+		maximumDurationInSeconds,
+		sessionId,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to renew session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to renew session: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to renew session: %w", dberr.ErrNoSuchSession)
+	}
+
+	return nil
+}
+
 func DeleteSession(db *sql.DB, sessionId models.SessionId) error {
 	result, err := db.Exec(
 		`
@@ -173,3 +255,81 @@ func DeleteExpiredSessions(db *sql.DB, cutOff models.Timestamp) error {
 
 	return err
 }
+
+// DeleteSessionsOfUser removes all sessions of the given user, effectively logging them out everywhere.
+// The number of removed sessions is returned.
+// An ErrNoSuchUser is returned if the user does not exist.
+func DeleteSessionsOfUser(db *sql.DB, userId models.Id) (int, error) {
+	if err := EnsureUserExists(db, userId); err != nil {
+		return 0, fmt.Errorf("failed to delete sessions of user %d: %w", userId, err)
+	}
+
+	result, err := db.Exec(
+		`
+			DELETE FROM sessions
+			WHERE user_id = ?
+		`,
+		userId,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions of user %d: %w", userId, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions of user %d: %w", userId, err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// UpdateActivity writes a batch of session expiration times and last activity timestamps in a single transaction.
+// Expiration times are never moved backwards.
+// Sessions and users that no longer exist are silently skipped.
+func UpdateActivity(
+	db *sql.DB,
+	sessionExpirationTimes map[models.SessionId]models.Timestamp,
+	lastActivities map[models.Id]models.Timestamp) (r_err error) {
+
+	transaction, err := NewTransaction(db)
+	if err != nil {
+		return fmt.Errorf("failed to update activity: %w", err)
+	}
+	defer func() { r_err = errors.Join(r_err, transaction.Rollback()) }()
+
+	for sessionId, expirationTime := range sessionExpirationTimes {
+		_, err := transaction.Exec(
+			`
+				UPDATE sessions
+				SET expiration_time = MAX(expiration_time, ?)
+				WHERE session_id = ?
+			`,
+			expirationTime,
+			sessionId,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update expiration time of session: %w", err)
+		}
+	}
+
+	for userId, lastActivity := range lastActivities {
+		_, err := transaction.Exec(
+			`
+				UPDATE users
+				SET last_activity = MAX(COALESCE(last_activity, 0), ?)
+				WHERE user_id = ?
+			`,
+			lastActivity,
+			userId,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update last activity of user %d: %w", userId, err)
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("failed to update activity: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package labels
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/barcode"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/pdf"
+	"database/sql"
+	"fmt"
+)
+
+// CollectLabelData gathers the information to be printed on the labels of the given items, in the given order.
+// itemTable must contain all items.
+func CollectLabelData(db *sql.DB, itemTable map[models.Id]*models.Item, itemIds []models.Id) ([]*pdf.LabelData, error) {
+	categoryNameTable, err := queries.GetCategoryNameTable(db)
+	if err != nil {
+		return nil, err
+	}
+
+	eventPrefix, err := queries.GetEventPrefix(db)
+	if err != nil {
+		return nil, err
+	}
+
+	createLabelData := func(itemId models.Id) (*pdf.LabelData, error) {
+		item, ok := itemTable[itemId]
+		if !ok {
+			return nil, fmt.Errorf("bug: item with id %d not found; should never occur: this error should have be caught earlier", itemId)
+		}
+
+		return createLabelDataFromItem(categoryNameTable, eventPrefix, item)
+	}
+
+	labelData, err := algorithms.MapError(itemIds, createLabelData)
+	if err != nil {
+		return nil, err
+	}
+
+	return labelData, nil
+}
+
+func createLabelDataFromItem(categoryNameTable map[models.Id]string, eventPrefix string, item *models.Item) (*pdf.LabelData, error) {
+	barcodeData := barcode.FormatPayload(eventPrefix, item.ItemID.Int64())
+
+	category, ok := categoryNameTable[item.CategoryID]
+	if !ok {
+		return nil, fmt.Errorf("unknown category id: %v", item.CategoryID)
+	}
+
+	labelData := &pdf.LabelData{
+		BarcodeData:      barcodeData,
+		EventPrefix:      eventPrefix,
+		Description:      item.Description,
+		Category:         category,
+		ItemIdentifier:   int(item.ItemID),
+		PriceInCents:     int(item.PriceInCents),
+		SellerIdentifier: int(item.SellerID),
+		SellerZone:       SellerZone(item.SellerID),
+		Charity:          item.Charity,
+		Donation:         item.Donation,
+	}
+
+	return labelData, nil
+}
+
+// SellerZone determines the zone of the seller.
+// Seller ids consist of the zone followed by two digits, see the add-sellers command.
+func SellerZone(sellerId models.Id) int {
+	return int(sellerId) / 100
+}
@@ -0,0 +1,58 @@
+package labels
+
+import (
+	"bctbackend/barcode"
+	"bctbackend/database/models"
+	"bctbackend/pdf"
+	"errors"
+	"fmt"
+)
+
+// DefaultTemplateName refers to the template used when none is specified.
+// Unless the configuration defines a template with this name, it is the built-in pdf.DefaultLabelTemplate.
+const DefaultTemplateName = "default"
+
+var ErrUnknownTemplate = errors.New("unknown label template")
+
+var ErrUnknownSymbology = errors.New("unknown barcode symbology")
+
+// LayoutSettings converts a stored label layout to validated pdf layout settings.
+func LayoutSettings(layout *models.LabelLayout) (*pdf.LayoutSettings, error) {
+	return pdf.NewLayoutSettings(
+		pdf.WithPaperSize(layout.PaperWidth, layout.PaperHeight),
+		pdf.WithPaperMargins(layout.PaperMargins.Top, layout.PaperMargins.Right, layout.PaperMargins.Bottom, layout.PaperMargins.Left),
+		pdf.WithGridSize(layout.Columns, layout.Rows),
+		pdf.WithLabelMargins(layout.LabelMargins.Top, layout.LabelMargins.Right, layout.LabelMargins.Bottom, layout.LabelMargins.Left),
+		pdf.WithLabelPadding(layout.LabelPadding.Top, layout.LabelPadding.Right, layout.LabelPadding.Bottom, layout.LabelPadding.Left),
+		pdf.WithFontSize(layout.FontSize),
+	)
+}
+
+// FindTemplate looks up the template with the given name among the configured templates.
+// An empty name refers to the default template.
+func FindTemplate(templates map[string]*pdf.LabelTemplate, name string) (*pdf.LabelTemplate, error) {
+	if name == "" {
+		name = DefaultTemplateName
+	}
+
+	if template, ok := templates[name]; ok {
+		return template, nil
+	}
+
+	if name == DefaultTemplateName {
+		return pdf.DefaultLabelTemplate(), nil
+	}
+
+	return nil, fmt.Errorf("failed to find template %s: %w", name, ErrUnknownTemplate)
+}
+
+// LayoutSymbology returns the barcode symbology of the layout.
+// An empty symbology means that of the configuration is used.
+func LayoutSymbology(layout *models.LabelLayout) (barcode.Symbology, error) {
+	symbology := barcode.Symbology(layout.Symbology)
+	if symbology != "" && !barcode.IsValidSymbology(symbology) {
+		return "", fmt.Errorf("failed to use symbology %s: %w", layout.Symbology, ErrUnknownSymbology)
+	}
+
+	return symbology, nil
+}
@@ -0,0 +1,164 @@
+package pdf
+
+import (
+	"fmt"
+
+	"bctbackend/barcode"
+)
+
+// BarcodeRenderer determines how barcodes are put on the labels.
+type BarcodeRenderer string
+
+const (
+	// VectorBarcodes draws each bar as a rectangle, so that bars stay sharp regardless of the printer's resolution
+	VectorBarcodes BarcodeRenderer = "vector"
+
+	// RasterBarcodes embeds the barcode as a PNG of BarcodeWidth×BarcodeHeight pixels
+	RasterBarcodes BarcodeRenderer = "raster"
+)
+
+// Font size in mm of the human-readable text beneath vector barcodes
+const barcodeTextFontSize = 2.5
+
+func IsValidBarcodeRenderer(renderer BarcodeRenderer) bool {
+	return renderer == VectorBarcodes || renderer == RasterBarcodes
+}
+
+// UsingSymbology overrides the symbology of the configuration. An empty symbology leaves it unchanged.
+func UsingSymbology(symbology barcode.Symbology) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.symbology = symbology
+	}
+}
+
+// applySymbology makes the builder use the given symbology instead of the one of the configuration, unless it is empty.
+func (builder *PdfBuilder) applySymbology(symbology barcode.Symbology) error {
+	if symbology == "" {
+		return nil
+	}
+
+	if !barcode.IsValidSymbology(symbology) {
+		return &PdfError{Message: fmt.Sprintf("unknown barcode symbology %q", symbology)}
+	}
+
+	builder.symbology = symbology
+	return nil
+}
+
+// validateBarcodeConfiguration checks the settings needed by the selected barcode renderer.
+func validateBarcodeConfiguration(configuration *Configuration) error {
+	if !barcode.IsValidSymbology(configuration.BarcodeSymbology) {
+		return &PdfError{Message: fmt.Sprintf("unknown barcode symbology %q", configuration.BarcodeSymbology)}
+	}
+
+	switch configuration.BarcodeRenderer {
+	case VectorBarcodes:
+		if configuration.BarcodeModuleWidth <= 0 {
+			return &PdfError{Message: "barcode module width must be positive"}
+		}
+		if configuration.BarcodeBarHeight <= 0 {
+			return &PdfError{Message: "barcode bar height must be positive"}
+		}
+	case RasterBarcodes:
+		if configuration.BarcodeWidth <= 0 || configuration.BarcodeHeight <= 0 {
+			return &PdfError{Message: "barcode width and height must be positive"}
+		}
+	default:
+		return &PdfError{Message: fmt.Sprintf("unknown barcode renderer %q", configuration.BarcodeRenderer)}
+	}
+
+	return nil
+}
+
+func (builder *PdfBuilder) barcodeContent(labelData *LabelData) (string, error) {
+	return BarcodeContent(builder.symbology, labelData)
+}
+
+// BarcodeContent determines the data encoded in the barcode of the label.
+// EAN-13 barcodes can only hold digits, so they encode the item id along with digits derived from the event prefix instead.
+func BarcodeContent(symbology barcode.Symbology, labelData *LabelData) (string, error) {
+	if symbology == barcode.EAN13 {
+		return barcode.InternalEan13Content(labelData.EventPrefix, labelData.ItemIdentifier)
+	}
+
+	return labelData.BarcodeData, nil
+}
+
+// drawVectorBarcodeField draws the barcode as rectangles, one per run of dark modules in a row.
+// The quiet zones are part of the field, so that nothing else is placed too close to the barcode.
+//
+// One-dimensional barcodes get the configured module width and bar height.
+// Two-dimensional barcodes are sized by their height alone, so that they fill a square.
+func (builder *PdfBuilder) drawVectorBarcodeField(field *TemplateField, rectangle *Rectangle, data string) error {
+	modules, err := barcode.EncodeModules(builder.symbology, data)
+	if err != nil {
+		return &PdfError{Message: fmt.Sprintf("failed to encode barcode for data %s", data), Wrapped: err}
+	}
+
+	quietZone := builder.symbology.QuietZoneModules()
+
+	textHeight := 0.0
+	if builder.configuration.BarcodeShowText {
+		textHeight = barcodeTextFontSize
+	}
+
+	codeHeight := builder.configuration.BarcodeBarHeight
+	if field.Height > 0 {
+		codeHeight = max(field.Height*rectangle.Height-textHeight, 0)
+	}
+
+	moduleWidth := builder.configuration.BarcodeModuleWidth
+	moduleHeight := codeHeight
+	barsHeight := codeHeight
+	if builder.symbology.IsTwoDimensional() {
+		moduleWidth = codeHeight / float64(modules.Rows+2*quietZone)
+		moduleHeight = moduleWidth
+		barsHeight = float64(modules.Rows) * moduleHeight
+	}
+
+	width := float64(modules.Columns+2*quietZone) * moduleWidth
+	height := codeHeight + textHeight
+
+	x, y := builder.placeField(field, rectangle, width, height)
+	barsLeft := x + float64(quietZone)*moduleWidth
+	barsTop := y + (codeHeight-barsHeight)/2
+
+	r, g, b := builder.pdf.GetFillColor()
+	defer builder.pdf.SetFillColor(r, g, b)
+	builder.pdf.SetFillColor(0, 0, 0)
+
+	for row := 0; row < modules.Rows; row++ {
+		top := barsTop + float64(row)*moduleHeight
+
+		for start := 0; start < modules.Columns; {
+			if !modules.IsDark(start, row) {
+				start++
+				continue
+			}
+
+			end := start
+			for end < modules.Columns && modules.IsDark(end, row) {
+				end++
+			}
+
+			builder.pdf.Rect(barsLeft+float64(start)*moduleWidth, top, float64(end-start)*moduleWidth, moduleHeight, "F")
+			start = end
+		}
+	}
+
+	if err := builder.pdf.Error(); err != nil {
+		return &PdfError{Message: "failed to draw modules", Wrapped: err}
+	}
+
+	if builder.configuration.BarcodeShowText {
+		builder.pdf.SetFontUnitSize(barcodeTextFontSize)
+		defer builder.pdf.SetFontUnitSize(builder.layout.fontSize)
+
+		textX := x + (width-builder.pdf.GetStringWidth(data))/2
+		if err := builder.drawText(data, textX, y+codeHeight+barcodeTextFontSize); err != nil {
+			return &PdfError{Message: "failed to draw barcode text", Wrapped: err}
+		}
+	}
+
+	return nil
+}
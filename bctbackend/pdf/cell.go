@@ -0,0 +1,38 @@
+package pdf
+
+import "fmt"
+
+// Cell identifies a label on a sheet. Columns and rows are counted from 0, starting at the top left.
+type Cell struct {
+	Column int
+	Row    int
+}
+
+func (cell Cell) String() string {
+	return fmt.Sprintf("(%d, %d)", cell.Column, cell.Row)
+}
+
+// ContainsCell checks whether the cell lies on the sheet.
+func (ls *LayoutSettings) ContainsCell(cell Cell) bool {
+	return 0 <= cell.Column && cell.Column < ls.columns && 0 <= cell.Row && cell.Row < ls.rows
+}
+
+// IsFirstSheetFull checks whether no cell of the first sheet remains available
+// when printing starts at startCell and skippedCells are left empty.
+func (ls *LayoutSettings) IsFirstSheetFull(startCell Cell, skippedCells []Cell) bool {
+	availableCells := make(map[Cell]bool)
+	for index := ls.cellIndex(startCell); index < ls.columns*ls.rows; index++ {
+		availableCells[Cell{Column: index % ls.columns, Row: index / ls.columns}] = true
+	}
+
+	for _, cell := range skippedCells {
+		delete(availableCells, cell)
+	}
+
+	return len(availableCells) == 0
+}
+
+// cellIndex numbers the cells row by row.
+func (ls *LayoutSettings) cellIndex(cell Cell) int {
+	return cell.Row*ls.columns + cell.Column
+}
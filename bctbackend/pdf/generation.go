@@ -18,11 +18,13 @@ const (
 
 type LabelData struct {
 	BarcodeData      string
+	EventPrefix      string
 	Description      string
 	Category         string
 	ItemIdentifier   int
 	PriceInCents     int
 	SellerIdentifier int
+	SellerZone       int
 	Charity          bool
 	Donation         bool
 }
@@ -35,21 +37,127 @@ type PdfBuilder struct {
 	labels        []*LabelData
 	showGrid      bool
 	configuration *Configuration
+
+	// Cells of the first sheet that have already been used
+	unavailableCells map[Cell]bool
+
+	// Text drawn diagonally across every page; empty for no watermark
+	watermark string
+
+	template *LabelTemplate
+
+	symbology barcode.Symbology
+
+	// Starts the labels of each seller on a new sheet, preceded by a separator page
+	separateSellers bool
+
+	// Called after each label with the number of labels drawn so far; nil if progress is not reported
+	reportProgress func(labelCount int) error
+
+	// Bottom of the last field that was drawn, so that the next field can be placed below it
+	fieldBottom float64
+}
+
+// generationSettings describe how labels are to be distributed over the sheets.
+type generationSettings struct {
+	startCell       Cell
+	skippedCells    []Cell
+	showGrid        bool
+	watermark       string
+	template        *LabelTemplate
+	symbology       barcode.Symbology
+	separateSellers bool
+	reportProgress  func(labelCount int) error
+}
+
+type GenerationOption func(*generationSettings)
+
+// StartingAt makes printing start at the given cell of the first sheet,
+// so that a partially used sheet can be reused. Later sheets are filled from the top left.
+func StartingAt(cell Cell) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.startCell = cell
+	}
+}
+
+// SkippingCells leaves the given cells of the first sheet empty, e.g., because their labels have already been peeled off.
+func SkippingCells(cells ...Cell) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.skippedCells = append(settings.skippedCells, cells...)
+	}
+}
+
+// ShowingGrid draws a grid over each label, which helps to check the alignment of the printer.
+func ShowingGrid() GenerationOption {
+	return func(settings *generationSettings) {
+		settings.showGrid = true
+	}
+}
+
+// WithWatermark draws the text diagonally across every page, e.g., to mark a preview.
+func WithWatermark(text string) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.watermark = text
+	}
 }
 
 type Configuration struct {
 	FontDirectory string
 	FontFilename  string
 	FontFamily    string
+
+	// Size in mm down to which the font of descriptions is shrunk to make them fit.
+	// Zero means descriptions are never shrunk.
+	MinimumFontSize float64
+
+	BarcodeRenderer BarcodeRenderer
+
+	// Symbology used unless overridden by UsingSymbology
+	BarcodeSymbology barcode.Symbology
+
+	// Width in mm of the narrowest bar and height in mm of the bars of vector barcodes.
+	// A template field with a height overrides the bar height.
+	BarcodeModuleWidth float64
+	BarcodeBarHeight   float64
+
+	// BarcodeShowText prints the encoded data beneath vector barcodes
+	BarcodeShowText bool
+
+	// Size in pixels of raster barcodes
 	BarcodeWidth  int
 	BarcodeHeight int
 }
 
-func GeneratePdf(configuration *Configuration, layout *LayoutSettings, labels []*LabelData) (*PdfBuilder, error) {
+func GeneratePdf(configuration *Configuration, layout *LayoutSettings, labels []*LabelData, options ...GenerationOption) (*PdfBuilder, error) {
+	var settings generationSettings
+	for _, option := range options {
+		option(&settings)
+	}
+
+	unavailableCells, err := determineUnavailableCells(layout, &settings)
+	if err != nil {
+		return nil, err
+	}
+
 	builder, err := newPdfBuilder(configuration, layout, labels)
 	if err != nil {
 		return nil, &PdfError{Message: "failed to create pdf builder", Wrapped: err}
 	}
+	builder.unavailableCells = unavailableCells
+	builder.showGrid = settings.showGrid
+	builder.watermark = settings.watermark
+	builder.separateSellers = settings.separateSellers
+	builder.reportProgress = settings.reportProgress
+	if settings.template != nil {
+		if err := ValidateTemplate(settings.template); err != nil {
+			return nil, &PdfError{Message: "invalid label template", Wrapped: err}
+		}
+
+		builder.template = settings.template
+	}
+	if err := builder.applySymbology(settings.symbology); err != nil {
+		return nil, err
+	}
 
 	if err := builder.drawLabels(); err != nil {
 		return nil, &PdfError{Message: "failed to draw labels", Wrapped: err}
@@ -83,6 +191,10 @@ func newPdfGenerator(fontDirectory string) *fpdf.Fpdf {
 }
 
 func newPdfBuilder(configuration *Configuration, layout *LayoutSettings, labels []*LabelData) (*PdfBuilder, error) {
+	if err := validateBarcodeConfiguration(configuration); err != nil {
+		return nil, &PdfError{Message: "invalid barcode configuration", Wrapped: err}
+	}
+
 	builder := PdfBuilder{
 		imageCache:    make(map[string]string),
 		pdf:           newPdfGenerator(configuration.FontDirectory),
@@ -91,6 +203,8 @@ func newPdfBuilder(configuration *Configuration, layout *LayoutSettings, labels
 		labels:        labels,
 		showGrid:      false,
 		configuration: configuration,
+		template:      DefaultLabelTemplate(),
+		symbology:     configuration.BarcodeSymbology,
 	}
 
 	if err := builder.setFont(); err != nil {
@@ -128,9 +242,77 @@ func (builder *PdfBuilder) registerImages() error {
 	return nil
 }
 
+func determineUnavailableCells(layout *LayoutSettings, settings *generationSettings) (map[Cell]bool, error) {
+	if !layout.ContainsCell(settings.startCell) {
+		return nil, &PdfError{Message: fmt.Sprintf("start cell %s lies outside of the sheet", settings.startCell)}
+	}
+
+	unavailableCells := make(map[Cell]bool)
+	for row := 0; row < layout.rows; row++ {
+		for column := 0; column < layout.columns; column++ {
+			cell := Cell{Column: column, Row: row}
+			if layout.cellIndex(cell) < layout.cellIndex(settings.startCell) {
+				unavailableCells[cell] = true
+			}
+		}
+	}
+
+	for _, cell := range settings.skippedCells {
+		if !layout.ContainsCell(cell) {
+			return nil, &PdfError{Message: fmt.Sprintf("skipped cell %s lies outside of the sheet", cell)}
+		}
+
+		unavailableCells[cell] = true
+	}
+
+	// Otherwise, the first sheet would be skipped altogether, while the labels end up on the first page of the PDF
+	if len(unavailableCells) == layout.columns*layout.rows {
+		return nil, &PdfError{Message: "no cell of the first sheet is available"}
+	}
+
+	return unavailableCells, nil
+}
+
+func (builder *PdfBuilder) isCurrentCellAvailable() bool {
+	return builder.gridWalker.CurrentPage > 0 || !builder.unavailableCells[builder.gridWalker.CurrentCell()]
+}
+
 func (builder *PdfBuilder) drawLabels() error {
-	for _, label := range builder.labels {
-		if builder.gridWalker.IsAtStart() {
+	pageAdded := false
+
+	for index, label := range builder.labels {
+		if builder.separateSellers && (index == 0 || label.SellerIdentifier != builder.labels[index-1].SellerIdentifier) {
+			if pageAdded {
+				if err := builder.finishPage(); err != nil {
+					return err
+				}
+			}
+
+			if err := builder.drawSeparatorPage(label.SellerIdentifier, builder.countSellerLabels(index)); err != nil {
+				return &PdfError{Message: "failed to draw separator page", Wrapped: err}
+			}
+
+			// The labels of the previous seller stay on their own sheet
+			if index > 0 && !builder.gridWalker.IsAtStart() {
+				builder.gridWalker.NextPage()
+			}
+			pageAdded = false
+		}
+
+		for !builder.isCurrentCellAvailable() {
+			builder.gridWalker.Next()
+		}
+
+		// The first page does not necessarily start at the first cell
+		if !pageAdded || builder.gridWalker.IsAtStart() {
+			if pageAdded {
+				if err := builder.finishPage(); err != nil {
+					return err
+				}
+			}
+
+			pageAdded = true
+
 			if err := builder.addPage(); err != nil {
 				return &PdfError{Message: "failed to add new page", Wrapped: err}
 			}
@@ -144,6 +326,29 @@ func (builder *PdfBuilder) drawLabels() error {
 		}
 
 		builder.gridWalker.Next()
+
+		if builder.reportProgress != nil {
+			if err := builder.reportProgress(index + 1); err != nil {
+				return &PdfError{Message: "generation aborted", Wrapped: err}
+			}
+		}
+	}
+
+	if pageAdded {
+		if err := builder.finishPage(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finishPage draws what needs to end up on top of the labels of the current page.
+func (builder *PdfBuilder) finishPage() error {
+	if builder.watermark != "" {
+		if err := builder.drawWatermark(builder.watermark); err != nil {
+			return &PdfError{Message: "failed to draw watermark", Wrapped: err}
+		}
 	}
 
 	return nil
@@ -164,97 +369,180 @@ func (builder *PdfBuilder) drawLabel(labelRectangle *Rectangle, labelData *Label
 	}
 
 	rectangle := labelRectangle.Shrink(builder.layout.labelPadding)
+	builder.fieldBottom = rectangle.Top
 
-	barcodeX := rectangle.Left
-	barcodeY := rectangle.Top
-	barcodeImageName, err := builder.drawBarcode(labelData.BarcodeData, barcodeX, barcodeY)
-	if err != nil {
-		return &PdfError{Message: "failed to draw barcode", Wrapped: err}
-	}
+	for index := range builder.template.Fields {
+		field := &builder.template.Fields[index]
 
-	_, barcodeHeight, err := builder.determineImageSize(barcodeImageName)
-	if err != nil {
-		return &PdfError{Message: "failed to determine barcode size", Wrapped: err}
+		if err := builder.drawField(field, fieldArea(field, rectangle, builder.fieldBottom), labelData); err != nil {
+			return &PdfError{Message: fmt.Sprintf("failed to draw %s field", field.Kind), Wrapped: err}
+		}
 	}
-	descriptionX := rectangle.Left
-	descriptionY := barcodeY + barcodeHeight + builder.layout.fontSize
-	if err := builder.drawText(labelData.Description, descriptionX, descriptionY); err != nil {
-		return &PdfError{Message: "failed to draw description", Wrapped: err}
+
+	return nil
+}
+
+func (builder *PdfBuilder) drawField(field *TemplateField, rectangle *Rectangle, labelData *LabelData) error {
+	switch field.Kind {
+	case BarcodeField:
+		data, err := builder.barcodeContent(labelData)
+		if err != nil {
+			return &PdfError{Message: "failed to determine barcode content", Wrapped: err}
+		}
+		return builder.drawBarcodeField(field, rectangle, data)
+	case IconsField:
+		return builder.drawIconsField(field, rectangle, labelData.Charity, labelData.Donation)
+	case DescriptionField:
+		return builder.drawDescriptionField(field, rectangle, labelData.Description)
 	}
 
-	categoryX := rectangle.Left
-	categoryY := descriptionY + builder.layout.fontSize
-	if err := builder.drawText(labelData.Category, categoryX, categoryY); err != nil {
-		return &PdfError{Message: "failed to draw category", Wrapped: err}
+	text, ok := FieldText(field, labelData)
+	if !ok {
+		return &PdfError{Message: fmt.Sprintf("unknown field kind %q", field.Kind)}
 	}
 
-	itemIdentifierString := fmt.Sprintf("%d", labelData.ItemIdentifier)
-	if err := builder.drawTextInLowerLeftCorner(itemIdentifierString, rectangle); err != nil {
-		return &PdfError{Message: "failed to draw item identifier", Wrapped: err}
+	return builder.drawTextField(field, rectangle, text)
+}
+
+// FieldText determines the text shown by a field consisting of a single line of text.
+// It returns false for fields of other kinds, such as barcodes and descriptions.
+func FieldText(field *TemplateField, labelData *LabelData) (string, bool) {
+	switch field.Kind {
+	case CategoryField:
+		return labelData.Category, true
+	case ItemIdField:
+		return fmt.Sprintf("%d", labelData.ItemIdentifier), true
+	case PriceField:
+		return formatPrice(labelData.PriceInCents), true
+	case SellerIdField:
+		return fmt.Sprintf("%d", labelData.SellerIdentifier), true
+	case PriceAndSellerField:
+		return formatPriceAndSeller(labelData.PriceInCents, labelData.SellerIdentifier), true
+	case ZoneField:
+		return fmt.Sprintf("%d", labelData.SellerZone), true
+	case TextField:
+		return field.Text, true
+	default:
+		return "", false
 	}
+}
 
-	priceAndSellerString := formatPriceAndSeller(labelData.PriceInCents, labelData.SellerIdentifier)
-	if err := builder.drawTextInLowerRightCorner(priceAndSellerString, rectangle); err != nil {
-		return &PdfError{Message: "failed to draw price and seller", Wrapped: err}
+func (builder *PdfBuilder) drawTextField(field *TemplateField, rectangle *Rectangle, text string) error {
+	fontSize := field.FontSize
+	if fontSize == 0 {
+		fontSize = builder.layout.fontSize
 	}
 
-	if labelData.Charity {
-		if err := builder.drawCharityImage(rectangle); err != nil {
-			return &PdfError{Message: "failed to draw charity image", Wrapped: err}
-		}
+	builder.pdf.SetFontUnitSize(fontSize)
+	defer builder.pdf.SetFontUnitSize(builder.layout.fontSize)
+
+	if field.Bold {
+		defer builder.emboldenText(fontSize)()
 	}
 
-	if labelData.Donation {
-		if err := builder.drawDonationImage(rectangle); err != nil {
-			return &PdfError{Message: "failed to draw donation image", Wrapped: err}
-		}
+	x, y := builder.placeField(field, rectangle, builder.pdf.GetStringWidth(text), fontSize)
+	if err := builder.drawText(text, x, y+fontSize); err != nil {
+		return &PdfError{Message: "failed to draw text field", Wrapped: err}
 	}
 
 	return nil
 }
 
-func (builder *PdfBuilder) drawCharityImage(rectangle *Rectangle) error {
-	imageWidth, _, err := builder.determineImageSize(charityImageName)
+// emboldenText makes subsequent text bold until the returned function is called.
+// The font is only available in a regular style, so bold text is emulated by also stroking the outlines.
+func (builder *PdfBuilder) emboldenText(fontSize float64) func() {
+	lineWidth := builder.pdf.GetLineWidth()
+
+	builder.pdf.SetLineWidth(fontSize / 30)
+	builder.pdf.SetTextRenderingMode(2)
+
+	return func() {
+		builder.pdf.SetTextRenderingMode(0)
+		builder.pdf.SetLineWidth(lineWidth)
+	}
+}
+
+func (builder *PdfBuilder) drawBarcodeField(field *TemplateField, rectangle *Rectangle, data string) error {
+	if builder.configuration.BarcodeRenderer == VectorBarcodes {
+		return builder.drawVectorBarcodeField(field, rectangle, data)
+	}
+
+	return builder.drawRasterBarcodeField(field, rectangle, data)
+}
+
+func (builder *PdfBuilder) drawRasterBarcodeField(field *TemplateField, rectangle *Rectangle, data string) error {
+	imageName, err := builder.generateBarcode(data)
 	if err != nil {
-		return &PdfError{Message: "failed to determine charity image size", Wrapped: err}
+		return &PdfError{Message: fmt.Sprintf("failed to generate barcode for data %s", data), Wrapped: err}
 	}
 
-	x := rectangle.Right() - imageWidth
-	y := rectangle.Top
+	width, height, err := builder.determineImageSize(imageName)
+	if err != nil {
+		return &PdfError{Message: "failed to determine barcode size", Wrapped: err}
+	}
 
-	if err := builder.drawImage(charityImageName, x, y); err != nil {
-		return &PdfError{Message: "failed to draw charity image", Wrapped: err}
+	// Square codes are generated BarcodeWidth pixels wide, but are drawn as high as other barcodes
+	scaledHeight := height
+	if builder.symbology.IsTwoDimensional() {
+		scaledHeight = height * float64(builder.configuration.BarcodeHeight) / float64(builder.configuration.BarcodeWidth)
+	}
+	if field.Height > 0 {
+		scaledHeight = field.Height * rectangle.Height
+	}
+	width = width * scaledHeight / height
+	height = scaledHeight
+
+	x, y := builder.placeField(field, rectangle, width, height)
+	if err := builder.drawScaledImage(imageName, x, y, width, height); err != nil {
+		return &PdfError{Message: "failed to draw barcode", Wrapped: err}
 	}
 
 	return nil
 }
 
-func (builder *PdfBuilder) drawDonationImage(rectangle *Rectangle) error {
-	charityImageWidth, _, err := builder.determineImageSize(charityImageName)
+// drawIconsField draws the donation and charity icons next to each other.
+// Space is reserved for both icons, so that they always appear at the same place.
+func (builder *PdfBuilder) drawIconsField(field *TemplateField, rectangle *Rectangle, charity bool, donation bool) error {
+	const spacing = 2
+
+	charityWidth, charityHeight, err := builder.determineImageSize(charityImageName)
 	if err != nil {
 		return &PdfError{Message: "failed to determine charity image size", Wrapped: err}
 	}
 
-	donationImageWidth, _, err := builder.determineImageSize(donationImageName)
+	donationWidth, donationHeight, err := builder.determineImageSize(donationImageName)
 	if err != nil {
 		return &PdfError{Message: "failed to determine donation image size", Wrapped: err}
 	}
 
-	x := rectangle.Right() - charityImageWidth - donationImageWidth - 2
-	y := rectangle.Top
+	width := donationWidth + spacing + charityWidth
+	height := max(donationHeight, charityHeight)
+	x, y := builder.placeField(field, rectangle, width, height)
+
+	if donation {
+		if err := builder.drawImage(donationImageName, x, y); err != nil {
+			return &PdfError{Message: "failed to draw donation image", Wrapped: err}
+		}
+	}
 
-	if err := builder.drawImage(donationImageName, x, y); err != nil {
-		return &PdfError{Message: "failed to draw donation image", Wrapped: err}
+	if charity {
+		if err := builder.drawImage(charityImageName, x+width-charityWidth, y); err != nil {
+			return &PdfError{Message: "failed to draw charity image", Wrapped: err}
+		}
 	}
 
 	return nil
 }
 
-func formatPriceAndSeller(priceInCents int, sellerIdentifier int) string {
+func formatPrice(priceInCents int) string {
 	euros := priceInCents / 100
 	cents := priceInCents % 100
 
-	return fmt.Sprintf("€%d.%02d → %d", euros, cents, sellerIdentifier)
+	return fmt.Sprintf("€%d.%02d", euros, cents)
+}
+
+func formatPriceAndSeller(priceInCents int, sellerIdentifier int) string {
+	return fmt.Sprintf("%s → %d", formatPrice(priceInCents), sellerIdentifier)
 }
 
 func (builder *PdfBuilder) setFont() error {
@@ -307,7 +595,13 @@ func (builder *PdfBuilder) generateBarcode(data string) (string, error) {
 	}
 
 	// Generate barcode image in memory
-	barcode, err := barcode.GenerateBarcode(data, builder.configuration.BarcodeWidth, builder.configuration.BarcodeHeight)
+	width := builder.configuration.BarcodeWidth
+	height := builder.configuration.BarcodeHeight
+	if builder.symbology.IsTwoDimensional() {
+		height = width
+	}
+
+	barcode, err := barcode.GenerateBarcode(builder.symbology, data, width, height)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate barcode: %w", err)
 	}
@@ -335,13 +629,17 @@ func (builder *PdfBuilder) generateBarcode(data string) (string, error) {
 }
 
 func (builder *PdfBuilder) drawImage(imageName string, x float64, y float64) error {
+	return builder.drawScaledImage(imageName, x, y, -1, -1)
+}
+
+func (builder *PdfBuilder) drawScaledImage(imageName string, x float64, y float64, width float64, height float64) error {
 	imageOptions := fpdf.ImageOptions{
 		ImageType:             "png",
 		ReadDpi:               true,
 		AllowNegativePosition: false,
 	}
 
-	builder.pdf.ImageOptions(imageName, x, y, -1, -1, false, imageOptions, 0, "")
+	builder.pdf.ImageOptions(imageName, x, y, width, height, false, imageOptions, 0, "")
 	if err := builder.pdf.Error(); err != nil {
 		return &PdfError{
 			Message: fmt.Sprintf("failed to draw image %s", imageName),
@@ -367,25 +665,6 @@ func (builder *PdfBuilder) determineImageSize(imageName string) (float64, float6
 	return imageWidth, imageHeight, nil
 }
 
-func (builder *PdfBuilder) drawBarcode(data string, x float64, y float64) (string, error) {
-	imageName, err := builder.generateBarcode(data)
-	if err != nil {
-		return "", &PdfError{
-			Message: fmt.Sprintf("failed to generate barcode for data %s", data),
-			Wrapped: err,
-		}
-	}
-
-	if err := builder.drawImage(imageName, x, y); err != nil {
-		return "", &PdfError{
-			Message: "failed to draw barcode",
-			Wrapped: err,
-		}
-	}
-
-	return imageName, nil
-}
-
 func (builder *PdfBuilder) drawText(text string, x float64, y float64) error {
 	builder.pdf.Text(x, y, text)
 	if err := builder.pdf.Error(); err != nil {
@@ -395,45 +674,6 @@ func (builder *PdfBuilder) drawText(text string, x float64, y float64) error {
 	return nil
 }
 
-func (builder *PdfBuilder) drawTextInLowerLeftCorner(text string, rectangle *Rectangle) error {
-	x := rectangle.Left
-	y := rectangle.Bottom()
-
-	builder.pdf.Text(x, y, text)
-	if err := builder.pdf.Error(); err != nil {
-		return &PdfError{Message: "failed to draw text in lower left corner", Wrapped: err}
-	}
-
-	return nil
-}
-
-func (builder *PdfBuilder) drawTextInLowerRightCorner(text string, rectangle *Rectangle) error {
-	stringLength := builder.pdf.GetStringWidth(text)
-	x := rectangle.Right() - stringLength
-	y := rectangle.Bottom()
-
-	builder.pdf.Text(x, y, text)
-	if err := builder.pdf.Error(); err != nil {
-		return &PdfError{Message: "failed to draw text in lower right corner", Wrapped: err}
-	}
-
-	return nil
-}
-
-func (builder *PdfBuilder) drawTextInTopRightCorner(text string, rectangle *Rectangle) error {
-	stringLength := builder.pdf.GetStringWidth(text)
-	stringHeight := builder.layout.fontSize
-	x := rectangle.Right() - stringLength
-	y := rectangle.Top + stringHeight
-
-	builder.pdf.Text(x, y, text)
-	if err := builder.pdf.Error(); err != nil {
-		return &PdfError{Message: "failed to draw text in top right corner", Wrapped: err}
-	}
-
-	return nil
-}
-
 func (builder *PdfBuilder) drawGrid(rectangle *Rectangle, cellSize float64) error {
 	r, g, b := builder.pdf.GetDrawColor()
 	defer builder.pdf.SetDrawColor(r, g, b)
@@ -5,6 +5,9 @@ type GridWalker struct {
 	RowCount      int
 	CurrentColumn int
 	CurrentRow    int
+
+	// Index of the page the walker is on, starting at 0
+	CurrentPage int
 }
 
 func NewGridWalker(columnCount int, rowCount int) *GridWalker {
@@ -13,6 +16,7 @@ func NewGridWalker(columnCount int, rowCount int) *GridWalker {
 		RowCount:      rowCount,
 		CurrentColumn: 0,
 		CurrentRow:    0,
+		CurrentPage:   0,
 	}
 }
 
@@ -26,11 +30,24 @@ func (gw *GridWalker) Next() {
 
 		if gw.CurrentRow == gw.RowCount {
 			gw.CurrentRow = 0
+			gw.CurrentPage++
 			return
 		}
 	}
 }
 
+// NextPage moves the walker to the first cell of the next page.
+func (gw *GridWalker) NextPage() {
+	gw.CurrentColumn = 0
+	gw.CurrentRow = 0
+	gw.CurrentPage++
+}
+
 func (gw *GridWalker) IsAtStart() bool {
 	return gw.CurrentColumn == 0 && gw.CurrentRow == 0
-}
\ No newline at end of file
+}
+
+// CurrentCell returns the cell the walker is on.
+func (gw *GridWalker) CurrentCell() Cell {
+	return Cell{Column: gw.CurrentColumn, Row: gw.CurrentRow}
+}
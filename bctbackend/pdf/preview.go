@@ -0,0 +1,106 @@
+package pdf
+
+import (
+	"math"
+)
+
+// PreviewWatermark distinguishes previews from labels that are meant to be used
+const PreviewWatermark = "PREVIEW"
+
+// Angle in degrees at which watermarks are drawn
+const watermarkAngle = 45
+
+// GenerateCalibrationPdf generates a single page showing where the labels of the layout will be printed.
+// Each cell is outlined and shows its coordinates; the area inside the label padding is outlined with dashes.
+// Printing this page on an empty label sheet reveals whether the layout matches the sheet.
+func GenerateCalibrationPdf(configuration *Configuration, layout *LayoutSettings) (*PdfBuilder, error) {
+	builder, err := newPdfBuilder(configuration, layout, nil)
+	if err != nil {
+		return nil, &PdfError{Message: "failed to create pdf builder", Wrapped: err}
+	}
+
+	if err := builder.drawCalibrationPage(); err != nil {
+		return nil, &PdfError{Message: "failed to draw calibration page", Wrapped: err}
+	}
+
+	return builder, nil
+}
+
+func (builder *PdfBuilder) drawCalibrationPage() error {
+	if err := builder.addPage(); err != nil {
+		return &PdfError{Message: "failed to add page", Wrapped: err}
+	}
+
+	for row := 0; row < builder.layout.rows; row++ {
+		for column := 0; column < builder.layout.columns; column++ {
+			if err := builder.drawCalibrationCell(Cell{Column: column, Row: row}); err != nil {
+				return &PdfError{Message: "failed to draw calibration cell", Wrapped: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (builder *PdfBuilder) drawCalibrationCell(cell Cell) error {
+	rectangle := builder.layout.GetRectangle(cell.Column, cell.Row)
+
+	if err := builder.drawLabelBorder(rectangle); err != nil {
+		return &PdfError{Message: "failed to draw label border", Wrapped: err}
+	}
+
+	contentRectangle := rectangle.Shrink(builder.layout.labelPadding)
+	builder.pdf.SetDashPattern([]float64{1, 1}, 0)
+	builder.pdf.Rect(contentRectangle.Left, contentRectangle.Top, contentRectangle.Width, contentRectangle.Height, "D")
+	builder.pdf.SetDashPattern([]float64{}, 0)
+	if err := builder.pdf.Error(); err != nil {
+		return &PdfError{Message: "failed to draw padding rectangle", Wrapped: err}
+	}
+
+	text := cell.String()
+	x := rectangle.Left + (rectangle.Width-builder.pdf.GetStringWidth(text))/2
+	y := rectangle.Top + (rectangle.Height+builder.layout.fontSize)/2
+	if err := builder.drawText(text, x, y); err != nil {
+		return &PdfError{Message: "failed to draw cell coordinates", Wrapped: err}
+	}
+
+	return nil
+}
+
+// drawWatermark draws large translucent text diagonally across the current page.
+func (builder *PdfBuilder) drawWatermark(text string) error {
+	paperWidth := builder.layout.paperWidth
+	paperHeight := builder.layout.paperHeight
+
+	// Size the text so that it spans about two thirds of the diagonal
+	diagonal := math.Hypot(paperWidth, paperHeight)
+	referenceWidth := builder.pdf.GetStringWidth(text)
+	if referenceWidth == 0 {
+		return nil
+	}
+	fontSize := builder.layout.fontSize * diagonal * 2 / 3 / referenceWidth
+
+	r, g, b := builder.pdf.GetTextColor()
+	defer builder.pdf.SetTextColor(r, g, b)
+	defer builder.pdf.SetFontUnitSize(builder.layout.fontSize)
+	defer builder.pdf.SetAlpha(1, "Normal")
+
+	builder.pdf.SetTextColor(128, 128, 128)
+	builder.pdf.SetAlpha(0.3, "Normal")
+	builder.pdf.SetFontUnitSize(fontSize)
+
+	centerX := paperWidth / 2
+	centerY := paperHeight / 2
+	textWidth := builder.pdf.GetStringWidth(text)
+
+	builder.pdf.TransformBegin()
+	builder.pdf.TransformRotate(watermarkAngle, centerX, centerY)
+	builder.pdf.Text(centerX-textWidth/2, centerY+fontSize/3, text)
+	builder.pdf.TransformEnd()
+
+	if err := builder.pdf.Error(); err != nil {
+		return &PdfError{Message: "failed to draw watermark text", Wrapped: err}
+	}
+
+	return nil
+}
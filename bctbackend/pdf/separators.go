@@ -0,0 +1,64 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+// Size of the text on separator pages relative to the font size of the layout
+const separatorFontScale = 4
+
+// SeparatingSellers starts the labels of each seller on a new sheet, preceded by a page showing the seller's id
+// and number of labels, so that a print job combining many sellers can easily be split up.
+// The labels must be ordered by seller.
+func SeparatingSellers() GenerationOption {
+	return func(settings *generationSettings) {
+		settings.separateSellers = true
+	}
+}
+
+// ReportingProgress calls report with the number of labels drawn so far after each label.
+// If report returns an error, generation is aborted and the error is returned.
+func ReportingProgress(report func(labelCount int) error) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.reportProgress = report
+	}
+}
+
+// countSellerLabels counts the labels of the seller of the label at the given index, which are consecutive.
+func (builder *PdfBuilder) countSellerLabels(index int) int {
+	sellerIdentifier := builder.labels[index].SellerIdentifier
+
+	count := 0
+	for index+count < len(builder.labels) && builder.labels[index+count].SellerIdentifier == sellerIdentifier {
+		count++
+	}
+
+	return count
+}
+
+func (builder *PdfBuilder) drawSeparatorPage(sellerIdentifier int, labelCount int) error {
+	if err := builder.addPage(); err != nil {
+		return &PdfError{Message: "failed to add page", Wrapped: err}
+	}
+
+	fontSize := builder.layout.fontSize * separatorFontScale
+	builder.pdf.SetFontUnitSize(fontSize)
+	defer builder.pdf.SetFontUnitSize(builder.layout.fontSize)
+
+	lines := []string{
+		fmt.Sprintf("Seller %d", sellerIdentifier),
+		fmt.Sprintf("%d labels", labelCount),
+	}
+
+	top := (builder.layout.paperHeight - float64(len(lines))*fontSize) / 2
+	for index, line := range lines {
+		x := (builder.layout.paperWidth - builder.pdf.GetStringWidth(line)) / 2
+		y := top + float64(index+1)*fontSize
+
+		if err := builder.drawText(line, x, y); err != nil {
+			return &PdfError{Message: "failed to draw separator text", Wrapped: err}
+		}
+	}
+
+	return nil
+}
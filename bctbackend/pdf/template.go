@@ -0,0 +1,188 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// FieldKind determines what a template field shows.
+type FieldKind string
+
+const (
+	BarcodeField        FieldKind = "barcode"
+	DescriptionField    FieldKind = "description"
+	CategoryField       FieldKind = "category"
+	ItemIdField         FieldKind = "item_id"
+	PriceField          FieldKind = "price"
+	SellerIdField       FieldKind = "seller_id"
+	PriceAndSellerField FieldKind = "price_and_seller"
+	ZoneField           FieldKind = "zone"
+	IconsField          FieldKind = "icons"
+	TextField           FieldKind = "text"
+)
+
+var fieldKinds = []FieldKind{
+	BarcodeField,
+	DescriptionField,
+	CategoryField,
+	ItemIdField,
+	PriceField,
+	SellerIdField,
+	PriceAndSellerField,
+	ZoneField,
+	IconsField,
+	TextField,
+}
+
+type HorizontalAlignment string
+
+const (
+	AlignLeft   HorizontalAlignment = "left"
+	AlignCenter HorizontalAlignment = "center"
+	AlignRight  HorizontalAlignment = "right"
+)
+
+type VerticalAlignment string
+
+const (
+	AlignTop    VerticalAlignment = "top"
+	AlignBottom VerticalAlignment = "bottom"
+)
+
+// TemplateField places a piece of information on a label.
+//
+// X and Y are relative coordinates within the label's padding, (0, 0) being the top left and (1, 1) the bottom right.
+// The alignments determine which side of the field is placed at these coordinates.
+// For text, the bottom is the baseline.
+//
+// The mapstructure tags determine the keys used in the configuration file.
+type TemplateField struct {
+	Kind FieldKind `mapstructure:"kind"`
+
+	X float64 `mapstructure:"x"`
+	Y float64 `mapstructure:"y"`
+
+	// Defaults to left
+	HorizontalAlignment HorizontalAlignment `mapstructure:"align"`
+
+	// Defaults to top
+	VerticalAlignment VerticalAlignment `mapstructure:"vertical-align"`
+
+	// Font size in mm. Zero means the font size of the layout.
+	FontSize float64 `mapstructure:"font-size"`
+
+	Bold bool `mapstructure:"bold"`
+
+	// Text shown by fields of kind TextField, e.g., a footer with the name of the event
+	Text string `mapstructure:"text"`
+
+	// Height relative to the height inside the label's padding.
+	// For barcodes, zero means the barcode is drawn at its configured size.
+	// For descriptions, it is the height over which the description may wrap; zero means a single line.
+	Height float64 `mapstructure:"height"`
+
+	// Measures Y from the bottom of the preceding field instead of from the top of the label,
+	// so that, e.g., text is not covered by a barcode whose height depends on the symbology.
+	Below bool `mapstructure:"below"`
+}
+
+// LabelTemplate describes the contents of a label declaratively.
+type LabelTemplate struct {
+	Fields []TemplateField `mapstructure:"fields"`
+}
+
+// DefaultLabelTemplate reproduces the original label design:
+// the barcode at the top left, directly followed by the description and the category,
+// the charity and donation icons at the top right,
+// the item id at the bottom left and the price and seller at the bottom right.
+func DefaultLabelTemplate() *LabelTemplate {
+	return &LabelTemplate{
+		Fields: []TemplateField{
+			{Kind: BarcodeField, X: 0, Y: 0},
+			{Kind: DescriptionField, X: 0, Y: 0, Height: 0.2, Below: true},
+			{Kind: CategoryField, X: 0, Y: 0, Below: true},
+			{Kind: IconsField, X: 1, Y: 0, HorizontalAlignment: AlignRight},
+			{Kind: ItemIdField, X: 0, Y: 1, VerticalAlignment: AlignBottom},
+			{Kind: PriceAndSellerField, X: 1, Y: 1, HorizontalAlignment: AlignRight, VerticalAlignment: AlignBottom},
+		},
+	}
+}
+
+// ValidateTemplate checks that all fields are of a known kind and lie on the label.
+func ValidateTemplate(template *LabelTemplate) error {
+	var errs []error
+
+	for index, field := range template.Fields {
+		errorIf := func(condition bool, format string, arguments ...any) {
+			if condition {
+				errs = append(errs, fmt.Errorf("field %d: %s", index, fmt.Sprintf(format, arguments...)))
+			}
+		}
+
+		errorIf(!slices.Contains(fieldKinds, field.Kind), "unknown kind %q", field.Kind)
+		errorIf(field.X < 0 || field.X > 1, "x must lie between 0 and 1")
+		errorIf(field.Y < 0 || field.Y > 1, "y must lie between 0 and 1")
+		errorIf(field.Height < 0 || field.Height > 1, "height must lie between 0 and 1")
+		errorIf(field.FontSize < 0, "font size must be greater than or equal to 0")
+
+		switch field.HorizontalAlignment {
+		case "", AlignLeft, AlignCenter, AlignRight:
+		default:
+			errorIf(true, "unknown horizontal alignment %q", field.HorizontalAlignment)
+		}
+
+		switch field.VerticalAlignment {
+		case "", AlignTop, AlignBottom:
+		default:
+			errorIf(true, "unknown vertical alignment %q", field.VerticalAlignment)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// UsingTemplate determines the contents of the labels. Without this option, the default template is used.
+func UsingTemplate(template *LabelTemplate) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.template = template
+	}
+}
+
+// fieldArea determines the area relative to which the field is positioned:
+// the label itself or, for fields placed below the preceding field, the label moved down to the given bottom.
+// The height is left unchanged, so that relative sizes mean the same for all fields.
+func fieldArea(field *TemplateField, rectangle *Rectangle, previousBottom float64) *Rectangle {
+	if !field.Below {
+		return rectangle
+	}
+
+	return &Rectangle{Left: rectangle.Left, Top: previousBottom, Width: rectangle.Width, Height: rectangle.Height}
+}
+
+// placeField determines the top left corner of a field of the given size
+// and remembers its bottom, so that the next field can be placed below it.
+func (builder *PdfBuilder) placeField(field *TemplateField, rectangle *Rectangle, width float64, height float64) (float64, float64) {
+	x, y := fieldPosition(field, rectangle, width, height)
+	builder.fieldBottom = y + height
+
+	return x, y
+}
+
+// fieldPosition determines the top left corner of a field of the given size.
+func fieldPosition(field *TemplateField, rectangle *Rectangle, width float64, height float64) (float64, float64) {
+	x := rectangle.Left + field.X*rectangle.Width
+	switch field.HorizontalAlignment {
+	case AlignCenter:
+		x -= width / 2
+	case AlignRight:
+		x -= width
+	}
+
+	y := rectangle.Top + field.Y*rectangle.Height
+	if field.VerticalAlignment == AlignBottom {
+		y -= height
+	}
+
+	return x, y
+}
@@ -0,0 +1,234 @@
+package pdf
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ellipsis = "…"
+
+	// Amount in mm by which the font size is reduced in each attempt to make a text fit
+	fontSizeStep = 0.25
+)
+
+// TextFit describes how a text was made to fit into its box.
+type TextFit struct {
+	Lines    []string
+	FontSize float64
+
+	// Shrunk indicates that the font size had to be reduced
+	Shrunk bool
+
+	// Truncated indicates that the text did not fit even at the minimum font size and was cut off
+	Truncated bool
+}
+
+// fitText wraps the text over the lines that fit in the box, shrinking the font down to minimumFontSize if necessary.
+// If the text still does not fit, it is cut off and ends with an ellipsis.
+// At least one line is always used, even if the box is not high enough.
+// The font size is left unchanged.
+func (builder *PdfBuilder) fitText(text string, fontSize float64, minimumFontSize float64, width float64, height float64) *TextFit {
+	currentFontSize, _ := builder.pdf.GetFontSize()
+	defer builder.pdf.SetFontSize(currentFontSize)
+
+	minimumFontSize = math.Min(minimumFontSize, fontSize)
+	if minimumFontSize <= 0 {
+		minimumFontSize = fontSize
+	}
+
+	for size := fontSize; ; size = math.Max(size-fontSizeStep, minimumFontSize) {
+		builder.pdf.SetFontUnitSize(size)
+		lines := builder.wrapText(text, width)
+
+		if len(lines) <= maximumLineCount(size, height) {
+			return &TextFit{Lines: lines, FontSize: size, Shrunk: size < fontSize}
+		}
+
+		if size == minimumFontSize {
+			return &TextFit{
+				Lines:     builder.truncateLines(lines, maximumLineCount(size, height), width),
+				FontSize:  size,
+				Shrunk:    size < fontSize,
+				Truncated: true,
+			}
+		}
+	}
+}
+
+func maximumLineCount(fontSize float64, height float64) int {
+	return max(1, int(math.Floor(height/fontSize)))
+}
+
+// wrapText breaks the text into lines no wider than width using the current font.
+// Words that are too long to fit on a line by themselves are broken up.
+func (builder *PdfBuilder) wrapText(text string, width float64) []string {
+	lines := []string{}
+	currentLine := ""
+
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if currentLine != "" {
+			candidate = currentLine + " " + word
+		}
+
+		if builder.pdf.GetStringWidth(candidate) <= width {
+			currentLine = candidate
+			continue
+		}
+
+		if currentLine != "" {
+			lines = append(lines, currentLine)
+		}
+
+		pieces := builder.breakWord(word, width)
+		lines = append(lines, pieces[:len(pieces)-1]...)
+		currentLine = pieces[len(pieces)-1]
+	}
+
+	if currentLine != "" || len(lines) == 0 {
+		lines = append(lines, currentLine)
+	}
+
+	return lines
+}
+
+// breakWord splits the word into pieces no wider than width, each containing at least one character.
+func (builder *PdfBuilder) breakWord(word string, width float64) []string {
+	pieces := []string{}
+	currentPiece := ""
+
+	for _, character := range word {
+		candidate := currentPiece + string(character)
+		if currentPiece != "" && builder.pdf.GetStringWidth(candidate) > width {
+			pieces = append(pieces, currentPiece)
+			candidate = string(character)
+		}
+		currentPiece = candidate
+	}
+
+	return append(pieces, currentPiece)
+}
+
+// truncateLines keeps the first lineCount lines and ends the last one with an ellipsis.
+func (builder *PdfBuilder) truncateLines(lines []string, lineCount int, width float64) []string {
+	result := append([]string{}, lines[:lineCount]...)
+
+	lastLine := result[lineCount-1]
+	for lastLine != "" && builder.pdf.GetStringWidth(lastLine+ellipsis) > width {
+		_, size := utf8.DecodeLastRuneInString(lastLine)
+		lastLine = lastLine[:len(lastLine)-size]
+	}
+	result[lineCount-1] = strings.TrimRight(lastLine, " ") + ellipsis
+
+	return result
+}
+
+// drawDescriptionField draws the description wrapped over the height of the field.
+func (builder *PdfBuilder) drawDescriptionField(field *TemplateField, rectangle *Rectangle, description string) error {
+	fit := builder.fitDescription(field, rectangle, description)
+
+	builder.pdf.SetFontUnitSize(fit.FontSize)
+	defer builder.pdf.SetFontUnitSize(builder.layout.fontSize)
+
+	if field.Bold {
+		defer builder.emboldenText(fit.FontSize)()
+	}
+
+	// The block of lines is aligned as a whole, each line is aligned individually
+	blockHeight := float64(len(fit.Lines)) * fit.FontSize
+	_, top := builder.placeField(field, rectangle, 0, blockHeight)
+
+	for index, line := range fit.Lines {
+		lineField := *field
+		lineField.VerticalAlignment = AlignTop
+		x, _ := fieldPosition(&lineField, rectangle, builder.pdf.GetStringWidth(line), fit.FontSize)
+		y := top + float64(index+1)*fit.FontSize
+
+		if err := builder.drawText(line, x, y); err != nil {
+			return &PdfError{Message: "failed to draw description line", Wrapped: err}
+		}
+	}
+
+	return nil
+}
+
+func (builder *PdfBuilder) fitDescription(field *TemplateField, rectangle *Rectangle, description string) *TextFit {
+	fontSize := field.FontSize
+	if fontSize == 0 {
+		fontSize = builder.layout.fontSize
+	}
+
+	width, height := descriptionBox(field, rectangle, fontSize)
+
+	return builder.fitText(description, fontSize, builder.configuration.MinimumFontSize, width, height)
+}
+
+// descriptionBox determines the space available to a description field.
+// The width is limited by the side of the label the text extends towards.
+func descriptionBox(field *TemplateField, rectangle *Rectangle, fontSize float64) (float64, float64) {
+	x := rectangle.Left + field.X*rectangle.Width
+
+	var width float64
+	switch field.HorizontalAlignment {
+	case AlignCenter:
+		width = 2 * min(x-rectangle.Left, rectangle.Right()-x)
+	case AlignRight:
+		width = x - rectangle.Left
+	default:
+		width = rectangle.Right() - x
+	}
+
+	height := field.Height * rectangle.Height
+	if height == 0 {
+		height = fontSize
+	}
+
+	return width, height
+}
+
+// CheckDescriptions determines how the description of each label would be fitted onto it, without generating any labels.
+// The result contains one entry per label, which is nil if the template does not show descriptions.
+// Only the first description field of the template is taken into account.
+func CheckDescriptions(configuration *Configuration, layout *LayoutSettings, labels []*LabelData, options ...GenerationOption) ([]*TextFit, error) {
+	var settings generationSettings
+	for _, option := range options {
+		option(&settings)
+	}
+
+	builder, err := newPdfBuilder(configuration, layout, labels)
+	if err != nil {
+		return nil, &PdfError{Message: "failed to create pdf builder", Wrapped: err}
+	}
+	if settings.template != nil {
+		if err := ValidateTemplate(settings.template); err != nil {
+			return nil, &PdfError{Message: "invalid label template", Wrapped: err}
+		}
+
+		builder.template = settings.template
+	}
+	if err := builder.applySymbology(settings.symbology); err != nil {
+		return nil, err
+	}
+
+	var descriptionField *TemplateField
+	for index := range builder.template.Fields {
+		if builder.template.Fields[index].Kind == DescriptionField {
+			descriptionField = &builder.template.Fields[index]
+			break
+		}
+	}
+
+	// All labels have the same size, so the first cell is representative
+	rectangle := layout.GetRectangle(0, 0).Shrink(layout.labelPadding)
+
+	fits := make([]*TextFit, len(labels))
+	if descriptionField != nil {
+		for index, label := range labels {
+			fits[index] = builder.fitDescription(descriptionField, rectangle, label.Description)
+		}
+	}
+
+	return fits, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bctbackend/database/models"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
 )
@@ -15,6 +16,24 @@ const (
 	Minute                   = 60 * Second
 	Hour                     = 60 * Minute
 	SessionDurationInSeconds = 24 * Hour
+
+	// A session expires once it has been idle for this long.
+	// Every authenticated request pushes the expiration time forward,
+	// but never beyond SessionDurationInSeconds after the session was created.
+	SessionIdleTimeoutInSeconds = 2 * Hour
+
+	// Interval at which expired sessions are purged from the database.
+	SessionCleanupIntervalInSeconds = 15 * Minute
+
+	ApiTokenByteLength = 32
+
+	// Prefix of all API tokens, which makes them easy to recognize, e.g., when they are accidentally committed.
+	ApiTokenPrefix = "bct_"
+
+	// The CSRF token is handed to the browser in a cookie that scripts can read,
+	// and has to be sent back in a header with every mutating request.
+	CSRFCookieName = "bct_csrf_token"
+	CSRFHeaderName = "X-CSRF-Token"
 )
 
 func HashPassword(password string, salt string) string {
@@ -33,3 +52,43 @@ func GenerateUniqueSessionId() models.SessionId {
 	// Note: base64 leads to trouble
 	return models.SessionId(hex.EncodeToString(bytes))
 }
+
+// GenerateApiToken generates a new random API token.
+// Only its hash (see HashApiToken) should ever be stored.
+func GenerateApiToken() string {
+	bytes := make([]byte, ApiTokenByteLength)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(err)
+	}
+
+	return ApiTokenPrefix + hex.EncodeToString(bytes)
+}
+
+// HashApiToken hashes an API token for storage.
+// API tokens are long and random, so no salt or key stretching is needed.
+func HashApiToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// DeriveCSRFToken computes the CSRF token belonging to a session.
+// Since the session id itself is kept out of reach of scripts, a malicious page cannot compute the token,
+// while there is no need to store it separately.
+func DeriveCSRFToken(sessionId models.SessionId) string {
+	hash := sha256.Sum256([]byte("csrf:" + string(sessionId)))
+	return hex.EncodeToString(hash[:])
+}
+
+// DeriveSessionHandle computes the public handle of a session, by which it can be listed and revoked.
+// The session id itself grants access to the session and must therefore never leave the cookie,
+// whereas the handle cannot be turned back into the session id.
+func DeriveSessionHandle(sessionId models.SessionId) string {
+	hash := sha256.Sum256([]byte("handle:" + string(sessionId)))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsValidCSRFToken checks whether the token belongs to the session.
+func IsValidCSRFToken(sessionId models.SessionId, token string) bool {
+	expected := DeriveCSRFToken(sessionId)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
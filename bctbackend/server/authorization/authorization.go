@@ -0,0 +1,178 @@
+package authorization
+
+import (
+	"bctbackend/database/models"
+	"fmt"
+	"maps"
+)
+
+// Action identifies something a user can do through the REST API.
+// Every authenticated endpoint is associated with exactly one action.
+type Action string
+
+const (
+	ListItems          Action = "list_items"
+	ViewItem           Action = "view_item"
+	UpdateItem         Action = "update_item"
+	FreezeItem         Action = "freeze_item"
+	UnfreezeItem       Action = "unfreeze_item"
+	ListUsers          Action = "list_users"
+	ViewUser           Action = "view_user"
+	ListSessions       Action = "list_sessions"
+	RevokeSessions     Action = "revoke_sessions"
+	ListCategories     Action = "list_categories"
+	ListCategoryCounts Action = "list_category_counts"
+	ListSellerItems    Action = "list_seller_items"
+	AddSellerItem      Action = "add_seller_item"
+	GenerateLabels     Action = "generate_labels"
+	GenerateLabelJobs  Action = "generate_label_jobs"
+	CalibrateLabels    Action = "calibrate_labels"
+	ListSales          Action = "list_sales"
+	ViewSale           Action = "view_sale"
+	AddSale            Action = "add_sale"
+	VoidSale           Action = "void_sale"
+	ListCashierSales   Action = "list_cashier_sales"
+	ViewPermissions    Action = "view_permissions"
+	ManageApiTokens    Action = "manage_api_tokens"
+	ReceiveEvents      Action = "receive_events"
+	ViewConnections    Action = "view_connections"
+	ViewMetrics        Action = "view_metrics"
+	ListLabelLayouts   Action = "list_label_layouts"
+	ManageLabelLayouts Action = "manage_label_layouts"
+)
+
+// Scope determines on which resources a role is allowed to perform an action.
+type Scope int
+
+const (
+	// The action is not allowed
+	NoScope Scope = iota
+
+	// The action is only allowed on resources owned by the user,
+	// e.g., a seller's own items or a cashier's own sales
+	OwnScope
+
+	// The action is allowed on all resources
+	AnyScope
+)
+
+func (scope Scope) String() string {
+	switch scope {
+	case OwnScope:
+		return "own"
+	case AnyScope:
+		return "any"
+	default:
+		return "none"
+	}
+}
+
+// Grants maps roles to the scope with which they are allowed to perform an action.
+// Roles that are missing are not allowed to perform the action.
+type Grants map[models.Id]Scope
+
+var (
+	admin      = models.AdminRoleId
+	seller     = models.SellerRoleId
+	cashier    = models.CashierRoleId
+	volunteer  = models.VolunteerRoleId
+	supervisor = models.SupervisorRoleId
+)
+
+// permissions is the single source of truth for who is allowed to do what.
+//
+// Supervisors oversee the sale at the venue: they can look at everything that is needed for reporting,
+// unfreeze items and void sales, but cannot manage users.
+// Volunteers help with the intake of items: they can look items up and freeze them once checked,
+// but they cannot sell.
+var permissions = map[Action]Grants{
+	ListItems:          {admin: AnyScope, supervisor: AnyScope},
+	ViewItem:           {admin: AnyScope, seller: OwnScope, cashier: AnyScope, volunteer: AnyScope, supervisor: AnyScope},
+	UpdateItem:         {admin: AnyScope, seller: OwnScope},
+	FreezeItem:         {admin: AnyScope, volunteer: AnyScope, supervisor: AnyScope},
+	UnfreezeItem:       {admin: AnyScope, supervisor: AnyScope},
+	ListUsers:          {admin: AnyScope},
+	ViewUser:           {admin: AnyScope, seller: OwnScope, cashier: OwnScope, volunteer: OwnScope, supervisor: OwnScope},
+	ListSessions:       {admin: AnyScope, seller: OwnScope, cashier: OwnScope, volunteer: OwnScope, supervisor: OwnScope},
+	RevokeSessions:     {admin: AnyScope, seller: OwnScope, cashier: OwnScope, volunteer: OwnScope, supervisor: OwnScope},
+	ListCategories:     {admin: AnyScope, seller: AnyScope, volunteer: AnyScope, supervisor: AnyScope},
+	ListCategoryCounts: {admin: AnyScope, supervisor: AnyScope},
+	ListSellerItems:    {admin: AnyScope, seller: OwnScope, volunteer: AnyScope, supervisor: AnyScope},
+	AddSellerItem:      {seller: OwnScope},
+	GenerateLabels:     {seller: OwnScope},
+	GenerateLabelJobs:  {admin: AnyScope},
+	CalibrateLabels:    {admin: AnyScope, seller: AnyScope},
+	ListSales:          {admin: AnyScope, supervisor: AnyScope},
+	ViewSale:           {admin: AnyScope, cashier: OwnScope, supervisor: AnyScope},
+	AddSale:            {cashier: AnyScope},
+	VoidSale:           {admin: AnyScope, supervisor: AnyScope},
+	ListCashierSales:   {admin: AnyScope, cashier: OwnScope, supervisor: AnyScope},
+	ViewPermissions:    {admin: AnyScope, seller: AnyScope, cashier: AnyScope, volunteer: AnyScope, supervisor: AnyScope},
+	ManageApiTokens:    {admin: AnyScope},
+	ReceiveEvents:      {admin: AnyScope, seller: OwnScope, cashier: OwnScope, volunteer: AnyScope, supervisor: AnyScope},
+	ViewConnections:    {admin: AnyScope},
+	ViewMetrics:        {admin: AnyScope},
+	ListLabelLayouts:   {admin: AnyScope, seller: AnyScope},
+	ManageLabelLayouts: {admin: AnyScope},
+}
+
+// ScopeOf returns the scope with which the role is allowed to perform the action.
+func ScopeOf(action Action, roleId models.RoleId) Scope {
+	grants, ok := permissions[action]
+	if !ok {
+		return NoScope
+	}
+
+	return grants[roleId.Id]
+}
+
+// ParseAction converts a string to an action.
+// The second return value indicates whether the action exists.
+func ParseAction(action string) (Action, bool) {
+	_, ok := permissions[Action(action)]
+	return Action(action), ok
+}
+
+// IsAllowed checks whether the role is allowed to perform the action on at least some resources.
+func IsAllowed(action Action, roleId models.RoleId) bool {
+	return ScopeOf(action, roleId) != NoScope
+}
+
+// IsAllowedOn checks whether the user is allowed to perform the action on a resource owned by ownerId.
+func IsAllowedOn(action Action, userId models.Id, roleId models.RoleId, ownerId models.Id) bool {
+	switch ScopeOf(action, roleId) {
+	case AnyScope:
+		return true
+	case OwnScope:
+		return userId == ownerId
+	default:
+		return false
+	}
+}
+
+// ValidateTokenActions checks that the actions exist and that the role is allowed to perform them,
+// so that an API token cannot be restricted to actions its owner cannot use anyway.
+func ValidateTokenActions(roleId models.RoleId, actions []string) error {
+	for _, action := range actions {
+		parsedAction, ok := ParseAction(action)
+		if !ok {
+			return fmt.Errorf("unknown action %s", action)
+		}
+
+		if !IsAllowed(parsedAction, roleId) {
+			return fmt.Errorf("role %s is not allowed to perform %s", roleId.Name(), action)
+		}
+	}
+
+	return nil
+}
+
+// Actions returns all actions together with the roles that are allowed to perform them.
+func Actions() map[Action]Grants {
+	result := make(map[Action]Grants, len(permissions))
+	for action, grants := range permissions {
+		result[action] = maps.Clone(grants)
+	}
+
+	return result
+}
@@ -1,12 +1,68 @@
 package configuration
 
+import (
+	"bctbackend/barcode"
+	"bctbackend/pdf"
+)
+
 type Configuration struct {
 	FontDirectory string
 	FontFilename  string
 	FontFamily    string
 	HTMLPath      string
+
+	// Size in mm down to which the font of descriptions is shrunk to make them fit on a label
+	MinimumFontSize float64
+
 	BarcodeWidth  int
 	BarcodeHeight int
-	Port          int
-	GinMode       string // GinMode can be "debug", "release", or "test"
+
+	// How barcodes are rendered, see the corresponding fields of pdf.Configuration
+	BarcodeRenderer    pdf.BarcodeRenderer
+	BarcodeSymbology   barcode.Symbology
+	BarcodeModuleWidth float64
+	BarcodeBarHeight   float64
+	BarcodeShowText    bool
+
+	// Labels for thermal printers, see the corresponding fields of zpl.Configuration.
+	// They share the barcode settings with PDF labels.
+	ZplLabelWidth   int
+	ZplLabelHeight  int
+	ZplLabelPadding int
+	ZplDpi          int
+	ZplFontSize     float64
+
+	BindAddress string
+	Port        int
+	GinMode     string // GinMode can be "debug", "release", or "test"
+
+	// Paths to the TLS certificate and private key. If left empty, the server uses plain HTTP.
+	TLSCertificatePath string
+	TLSKeyPath         string
+
+	// GenerateSelfSignedCertificate causes a self-signed certificate to be generated
+	// at TLSCertificatePath and TLSKeyPath if these files do not exist yet.
+	GenerateSelfSignedCertificate bool
+
+	// Domain of the session cookie. If left empty, the cookie is tied to the host the login request was sent to.
+	CookieDomain string
+
+	// Origins, e.g., "https://bct.example.com", from which browsers are allowed to make cross-origin requests
+	// and open websocket connections. Same-origin requests are always allowed.
+	AllowedOrigins []string
+
+	// AllowAllOrigins disables origin checking altogether. Only meant for development.
+	AllowAllOrigins bool
+
+	// DisableSessionCache makes every authenticated request look up its session in the database
+	// and write its activity immediately instead of batching it.
+	DisableSessionCache bool
+
+	// Label templates by name, selectable per label layout preset.
+	// A template named "default" replaces the built-in default template.
+	LabelTemplates map[string]*pdf.LabelTemplate
+}
+
+func (configuration *Configuration) TLSEnabled() bool {
+	return configuration.TLSCertificatePath != ""
 }
@@ -0,0 +1,45 @@
+package configuration
+
+import (
+	"bctbackend/algorithms"
+	"fmt"
+	"path"
+)
+
+// CheckRequiredFiles verifies that the files the server needs at runtime exist.
+func (configuration *Configuration) CheckRequiredFiles() error {
+	fontPath := path.Join(configuration.FontDirectory, configuration.FontFilename)
+	if err := ensureFileExists(fontPath); err != nil {
+		return fmt.Errorf("failed while checking font file existence: %w", err)
+	}
+
+	if err := ensureFileExists(configuration.HTMLPath); err != nil {
+		return fmt.Errorf("failed while checking for html file existence: %w", err)
+	}
+
+	// A self-signed certificate is generated on first start, so it need not exist yet
+	if configuration.TLSEnabled() && !configuration.GenerateSelfSignedCertificate {
+		if err := ensureFileExists(configuration.TLSCertificatePath); err != nil {
+			return fmt.Errorf("failed while checking for tls certificate existence: %w", err)
+		}
+
+		if err := ensureFileExists(configuration.TLSKeyPath); err != nil {
+			return fmt.Errorf("failed while checking for tls key existence: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func ensureFileExists(path string) error {
+	exists, err := algorithms.FileExists(path)
+	if err != nil {
+		return fmt.Errorf("failed to check if file exists: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("required file does not exist: %s", path)
+	}
+
+	return nil
+}
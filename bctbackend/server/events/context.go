@@ -0,0 +1,29 @@
+package events
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const publisherContextKey = "bct_event_publisher"
+
+// Publisher forwards events to interested clients.
+type Publisher interface {
+	Publish(event *Event)
+}
+
+// StoreInContext makes the publisher available to request handlers
+// so that they can report the changes they made.
+func StoreInContext(context *gin.Context, publisher Publisher) {
+	context.Set(publisherContextKey, publisher)
+}
+
+// Publish sends the event to the publisher associated with the request, if any.
+// Handlers should only publish events after the change has been committed successfully.
+func Publish(context *gin.Context, event *Event) {
+	value, exists := context.Get(publisherContextKey)
+	if !exists {
+		return
+	}
+
+	value.(Publisher).Publish(event)
+}
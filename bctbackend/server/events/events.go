@@ -0,0 +1,197 @@
+package events
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/database/models"
+	"slices"
+)
+
+// Type identifies the kind of change an event reports.
+type Type string
+
+const (
+	ItemAdded       Type = "item.added"
+	ItemUpdated     Type = "item.updated"
+	ItemsFrozen     Type = "items.frozen"
+	ItemsUnfrozen   Type = "items.unfrozen"
+	SaleCreated     Type = "sale.created"
+	SaleVoided      Type = "sale.voided"
+	CategoryChanged Type = "category.changed"
+	JobUpdated      Type = "job.updated"
+)
+
+// Event describes a change to the data, so that clients can update only what is affected.
+// Events are sent to clients as JSON, e.g., {"type":"items.frozen","payload":{"itemIds":[1,2]}}.
+type Event struct {
+	Type    Type `json:"type"`
+	Payload any  `json:"payload"`
+
+	// Users the event is about, e.g., the seller of an item or the cashier of a sale.
+	// Users that are only allowed to see their own data only receive events they are concerned by.
+	owners []models.Id
+
+	// Private events are only sent to their owners, regardless of what else the subscriber is allowed to see
+	private bool
+
+	// Determines the payload as seen by an owner who may only see their own data;
+	// nil if the payload contains nothing about other owners
+	restrict func(userId models.Id) any
+}
+
+// Concerns checks whether the event is about data owned by the user.
+func (event *Event) Concerns(userId models.Id) bool {
+	return slices.Contains(event.owners, userId)
+}
+
+// RestrictedTo returns the event as seen by a user who may only see their own data,
+// e.g., without the ids of items of other sellers.
+func (event *Event) RestrictedTo(userId models.Id) *Event {
+	if event.restrict == nil {
+		return event
+	}
+
+	return &Event{Type: event.Type, Payload: event.restrict(userId), owners: event.owners, private: event.private}
+}
+
+// IsPrivate checks whether the event may only be sent to the users it concerns.
+func (event *Event) IsPrivate() bool {
+	return event.private
+}
+
+type ItemAddedPayload struct {
+	ItemId     models.Id `json:"itemId"`
+	SellerId   models.Id `json:"sellerId"`
+	CategoryId models.Id `json:"categoryId"`
+}
+
+type ItemUpdatedPayload struct {
+	ItemId   models.Id `json:"itemId"`
+	SellerId models.Id `json:"sellerId"`
+}
+
+type ItemsPayload struct {
+	ItemIds []models.Id `json:"itemIds"`
+}
+
+type SalePayload struct {
+	SaleId    models.Id   `json:"saleId"`
+	CashierId models.Id   `json:"cashierId"`
+	ItemIds   []models.Id `json:"itemIds"`
+}
+
+// CategoryChangedPayload lists the categories whose item counts changed.
+type CategoryChangedPayload struct {
+	CategoryIds []models.Id `json:"categoryIds"`
+}
+
+// JobPayload reports the progress of a background job.
+type JobPayload struct {
+	JobId  models.Id `json:"jobId"`
+	Status string    `json:"status"`
+	Done   int       `json:"done"`
+	Total  int       `json:"total"`
+
+	// Reason why the job failed; empty unless it did
+	Error string `json:"error,omitempty"`
+}
+
+func NewItemAdded(itemId models.Id, sellerId models.Id, categoryId models.Id) *Event {
+	return &Event{
+		Type:    ItemAdded,
+		Payload: ItemAddedPayload{ItemId: itemId, SellerId: sellerId, CategoryId: categoryId},
+		owners:  []models.Id{sellerId},
+	}
+}
+
+func NewItemUpdated(itemId models.Id, sellerId models.Id) *Event {
+	return &Event{
+		Type:    ItemUpdated,
+		Payload: ItemUpdatedPayload{ItemId: itemId, SellerId: sellerId},
+		owners:  []models.Id{sellerId},
+	}
+}
+
+func NewItemsFrozen(items []*models.Item) *Event {
+	return &Event{
+		Type:     ItemsFrozen,
+		Payload:  ItemsPayload{ItemIds: itemIds(items)},
+		owners:   sellerIds(items),
+		restrict: restrictItems(items),
+	}
+}
+
+func NewItemsUnfrozen(items []*models.Item) *Event {
+	return &Event{
+		Type:     ItemsUnfrozen,
+		Payload:  ItemsPayload{ItemIds: itemIds(items)},
+		owners:   sellerIds(items),
+		restrict: restrictItems(items),
+	}
+}
+
+func NewSaleCreated(saleId models.Id, cashierId models.Id, items []*models.Item) *Event {
+	return &Event{
+		Type:     SaleCreated,
+		Payload:  SalePayload{SaleId: saleId, CashierId: cashierId, ItemIds: itemIds(items)},
+		owners:   append(sellerIds(items), cashierId),
+		restrict: restrictSale(saleId, cashierId, items),
+	}
+}
+
+func NewSaleVoided(saleId models.Id, cashierId models.Id, items []*models.Item) *Event {
+	return &Event{
+		Type:     SaleVoided,
+		Payload:  SalePayload{SaleId: saleId, CashierId: cashierId, ItemIds: itemIds(items)},
+		owners:   append(sellerIds(items), cashierId),
+		restrict: restrictSale(saleId, cashierId, items),
+	}
+}
+
+func NewCategoryChanged(categoryIds ...models.Id) *Event {
+	return &Event{
+		Type:    CategoryChanged,
+		Payload: CategoryChangedPayload{CategoryIds: categoryIds},
+	}
+}
+
+// NewJobUpdated reports the progress of a job to the user who started it.
+func NewJobUpdated(payload JobPayload, ownerId models.Id) *Event {
+	return &Event{
+		Type:    JobUpdated,
+		Payload: payload,
+		owners:  []models.Id{ownerId},
+		private: true,
+	}
+}
+
+// restrictItems lets sellers only see which of their own items are affected.
+func restrictItems(items []*models.Item) func(userId models.Id) any {
+	return func(userId models.Id) any {
+		return ItemsPayload{ItemIds: itemIds(itemsOf(items, userId))}
+	}
+}
+
+// restrictSale lets sellers only see which of their own items are part of the sale.
+// The cashier sees all items, as they handled all of them.
+func restrictSale(saleId models.Id, cashierId models.Id, items []*models.Item) func(userId models.Id) any {
+	return func(userId models.Id) any {
+		if userId == cashierId {
+			return SalePayload{SaleId: saleId, CashierId: cashierId, ItemIds: itemIds(items)}
+		}
+
+		return SalePayload{SaleId: saleId, CashierId: cashierId, ItemIds: itemIds(itemsOf(items, userId))}
+	}
+}
+
+// itemsOf selects the items of the seller.
+func itemsOf(items []*models.Item, sellerId models.Id) []*models.Item {
+	return algorithms.Filter(items, func(item *models.Item) bool { return item.SellerID == sellerId })
+}
+
+func itemIds(items []*models.Item) []models.Id {
+	return algorithms.Map(items, func(item *models.Item) models.Id { return item.ItemID })
+}
+
+func sellerIds(items []*models.Item) []models.Id {
+	return algorithms.Map(items, func(item *models.Item) models.Id { return item.SellerID })
+}
@@ -0,0 +1,24 @@
+package events
+
+import (
+	"bctbackend/database/models"
+)
+
+// Subscription describes who is listening and which events they are allowed to receive.
+type Subscription struct {
+	UserId models.Id
+	RoleId models.RoleId
+
+	// Session with which the connection was opened; empty if an API token was used
+	SessionId models.SessionId
+
+	// Accepts decides whether an event may be sent to the subscriber
+	Accepts func(event *Event) bool
+
+	// View determines what the subscriber gets to see of an accepted event, e.g., only the ids of their own items
+	View func(event *Event) *Event
+
+	// IsValid checks whether the credentials with which the connection was opened are still valid.
+	// It is called periodically so that connections of expired or revoked credentials get closed.
+	IsValid func() bool
+}
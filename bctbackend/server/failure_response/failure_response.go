@@ -1,6 +1,7 @@
 package failure_response
 
 import (
+	"bctbackend/server/logging"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,33 +10,62 @@ import (
 type FailureResponse struct {
 	Type    string `json:"type"`
 	Details string `json:"details"`
+
+	// Id of the request, which can be used to find the corresponding lines in the server logs
+	RequestId string `json:"requestId,omitempty"`
+}
+
+const contextKey = "bct_failure_response"
+
+func respond(context *gin.Context, status int, errorType string, message string) {
+	response := &FailureResponse{Type: errorType, Details: message, RequestId: logging.RequestId(context)}
+	context.Set(contextKey, response)
+	context.JSON(status, response)
+}
+
+// FromContext returns the failure response that was sent for the request, if any.
+func FromContext(context *gin.Context) (*FailureResponse, bool) {
+	value, exists := context.Get(contextKey)
+	if !exists {
+		return nil, false
+	}
+
+	response, ok := value.(*FailureResponse)
+	return response, ok
 }
 
 func BadRequest(context *gin.Context, errorType string, message string) {
-	response := &FailureResponse{Type: errorType, Details: message}
-	context.JSON(http.StatusBadRequest, response)
+	respond(context, http.StatusBadRequest, errorType, message)
 }
 
 // User was not authenticated
 func Unauthorized(context *gin.Context, errorType string, message string) {
-	response := &FailureResponse{Type: errorType, Details: message}
-	context.JSON(http.StatusUnauthorized, response)
+	respond(context, http.StatusUnauthorized, errorType, message)
 }
 
 // User was authenticated, but is not authorized to perform the action
 func Forbidden(context *gin.Context, errorType string, message string) {
-	response := &FailureResponse{Type: errorType, Details: message}
-	context.JSON(http.StatusForbidden, response)
+	respond(context, http.StatusForbidden, errorType, message)
 }
 
 func NotFound(context *gin.Context, errorType string, message string) {
-	response := &FailureResponse{Type: errorType, Details: message}
-	context.JSON(http.StatusNotFound, response)
+	respond(context, http.StatusNotFound, errorType, message)
+}
+
+// The server is temporarily unable to handle the request, e.g., because it is shutting down
+func ServiceUnavailable(context *gin.Context, errorType string, message string) {
+	respond(context, http.StatusServiceUnavailable, errorType, message)
 }
 
 func Unknown(context *gin.Context, message string) {
-	response := &FailureResponse{Type: "unknown", Details: message}
-	context.JSON(http.StatusInternalServerError, response)
+	respond(context, http.StatusInternalServerError, "unknown", message)
+}
+
+// UnknownError is like Unknown, but also attaches the error to the request
+// so that middleware, e.g., metrics, can find out what caused it.
+func UnknownError(context *gin.Context, err error) {
+	context.Error(err)
+	Unknown(context, err.Error())
 }
 
 // Could not parse request
@@ -93,6 +123,10 @@ func CannotUpdateFrozenItem(context *gin.Context, message string) {
 	Forbidden(context, "item_frozen", message)
 }
 
+func ItemHidden(context *gin.Context, message string) {
+	Forbidden(context, "item_hidden", message)
+}
+
 func InvalidPrice(context *gin.Context, message string) {
 	Forbidden(context, "invalid_price", message)
 }
@@ -122,6 +156,34 @@ func NoSuchSession(context *gin.Context, message string) {
 	Unauthorized(context, "no_such_session", message)
 }
 
+// There is no session with the given ID
+func UnknownSession(context *gin.Context, message string) {
+	NotFound(context, "unknown_session", message)
+}
+
+func InvalidApiToken(context *gin.Context, message string) {
+	Unauthorized(context, "invalid_api_token", message)
+}
+
+// API token is restricted to other actions
+func ApiTokenOutOfScope(context *gin.Context, message string) {
+	Forbidden(context, "api_token_out_of_scope", message)
+}
+
+// Cookie-authenticated mutating request lacks a valid X-CSRF-Token header
+func InvalidCSRFToken(context *gin.Context, message string) {
+	Forbidden(context, "invalid_csrf_token", message)
+}
+
+// There is no API token with the given ID
+func UnknownApiToken(context *gin.Context, message string) {
+	NotFound(context, "unknown_api_token", message)
+}
+
+func InvalidAction(context *gin.Context, message string) {
+	BadRequest(context, "invalid_action", message)
+}
+
 func MissingItems(context *gin.Context, message string) {
 	Forbidden(context, "missing_items", message)
 }
@@ -129,3 +191,71 @@ func MissingItems(context *gin.Context, message string) {
 func InvalidLayout(context *gin.Context, message string) {
 	Forbidden(context, "invalid_layout", message)
 }
+
+func ShuttingDown(context *gin.Context, message string) {
+	ServiceUnavailable(context, "shutting_down", message)
+}
+
+// One of the readiness checks failed
+func NotReady(context *gin.Context, message string) {
+	ServiceUnavailable(context, "not_ready", message)
+}
+
+// A cell lies outside of the label sheet
+func InvalidCell(context *gin.Context, message string) {
+	BadRequest(context, "invalid_cell", message)
+}
+
+// There is no label layout preset with the given ID
+func UnknownLabelLayout(context *gin.Context, message string) {
+	NotFound(context, "unknown_label_layout", message)
+}
+
+func InvalidLabelLayoutName(context *gin.Context, message string) {
+	BadRequest(context, "invalid_label_layout_name", message)
+}
+
+// Another label layout preset already has the same name
+func LabelLayoutNameInUse(context *gin.Context, message string) {
+	BadRequest(context, "label_layout_name_in_use", message)
+}
+
+// The configuration defines no label template with the given name
+func UnknownLabelTemplate(context *gin.Context, message string) {
+	NotFound(context, "unknown_label_template", message)
+}
+
+// Labels can only be generated in the formats listed by the endpoint
+func UnknownLabelFormat(context *gin.Context, message string) {
+	BadRequest(context, "unknown_label_format", message)
+}
+
+// A scanned barcode does not follow any of the known formats
+func MalformedBarcode(context *gin.Context, message string) {
+	BadRequest(context, "malformed_barcode", message)
+}
+
+// The check digit of a scanned barcode does not match, e.g., because it was misread
+func CorruptedBarcode(context *gin.Context, message string) {
+	BadRequest(context, "corrupted_barcode", message)
+}
+
+// A scanned barcode was generated for another event, e.g., a label left over from last year
+func ForeignBarcode(context *gin.Context, message string) {
+	BadRequest(context, "foreign_barcode", message)
+}
+
+// There is no label job with the given ID, or it finished so long ago that it has been forgotten
+func UnknownLabelJob(context *gin.Context, message string) {
+	NotFound(context, "unknown_label_job", message)
+}
+
+// The result of a label job was requested before the job finished
+func LabelJobNotFinished(context *gin.Context, message string) {
+	BadRequest(context, "label_job_not_finished", message)
+}
+
+// The result of a label job was requested, but the job failed to produce one
+func LabelJobFailed(context *gin.Context, message string) {
+	BadRequest(context, "label_job_failed", message)
+}
@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const registryContextKey = "bct_job_registry"
+
+// StoreInContext makes the registry available to request handlers.
+func StoreInContext(context *gin.Context, registry *Registry) {
+	context.Set(registryContextKey, registry)
+}
+
+// FromContext returns the registry associated with the request, or nil if there is none.
+func FromContext(context *gin.Context) *Registry {
+	value, exists := context.Get(registryContextKey)
+	if !exists {
+		return nil
+	}
+
+	return value.(*Registry)
+}
@@ -0,0 +1,259 @@
+// Package jobs runs long tasks, such as generating thousands of labels, in the background.
+// Progress is published as events, and the result is kept in memory until it is downloaded or expires.
+package jobs
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/server/events"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	Running   Status = "running"
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+)
+
+// Result is the file produced by a job.
+type Result struct {
+	Data        []byte
+	ContentType string
+	Filename    string
+}
+
+// Work performs the job, reporting its progress through the tracker.
+// It should stop as soon as ctx is cancelled, which happens when the server shuts down.
+type Work func(ctx context.Context, tracker *Tracker) (*Result, error)
+
+// Snapshot describes the state of a job at some point in time.
+type Snapshot struct {
+	JobId   models.Id
+	OwnerId models.Id
+	Status  Status
+	Done    int
+	Total   int
+
+	// Reason why the job failed; empty unless it did
+	Error string
+
+	CreatedAt models.Timestamp
+
+	// Zero while the job is running
+	FinishedAt models.Timestamp
+}
+
+type job struct {
+	snapshot Snapshot
+	result   *Result
+
+	// Last progress that was published, in percent
+	publishedPercentage int
+}
+
+// Registry keeps track of the jobs. It is safe for concurrent use.
+type Registry struct {
+	mutex     sync.Mutex
+	jobs      map[models.Id]*job
+	lastJobId models.Id
+	publisher events.Publisher
+	retention time.Duration
+	running   sync.WaitGroup
+
+	// Context passed to all work; cancelled on shutdown
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRegistry creates a registry that publishes progress to publisher
+// and forgets finished jobs after retention.
+func NewRegistry(publisher events.Publisher, retention time.Duration) *Registry {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Registry{
+		jobs:      make(map[models.Id]*job),
+		publisher: publisher,
+		retention: retention,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start runs the work in the background on behalf of the owner.
+func (registry *Registry) Start(ownerId models.Id, work Work) Snapshot {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.removeExpiredJobs()
+
+	registry.lastJobId++
+	job := &job{
+		snapshot: Snapshot{
+			JobId:     registry.lastJobId,
+			OwnerId:   ownerId,
+			Status:    Running,
+			CreatedAt: models.Now(),
+		},
+	}
+	registry.jobs[job.snapshot.JobId] = job
+
+	registry.running.Add(1)
+	go func() {
+		defer registry.running.Done()
+		registry.run(job, work)
+	}()
+
+	return job.snapshot
+}
+
+func (registry *Registry) run(job *job, work Work) {
+	tracker := &Tracker{registry: registry, job: job}
+
+	result, err := work(registry.ctx, tracker)
+
+	registry.mutex.Lock()
+	job.snapshot.FinishedAt = models.Now()
+	if err != nil {
+		slog.Error("Job failed", slog.Int64("job_id", job.snapshot.JobId.Int64()), slog.String("error", err.Error()))
+		job.snapshot.Status = Failed
+		job.snapshot.Error = err.Error()
+	} else {
+		job.snapshot.Status = Succeeded
+		job.result = result
+	}
+	snapshot := job.snapshot
+	registry.mutex.Unlock()
+
+	registry.publish(snapshot)
+}
+
+// Get returns the state of the job and, if it succeeded, its result.
+// The second return value indicates whether the job exists.
+func (registry *Registry) Get(jobId models.Id) (Snapshot, *Result, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.removeExpiredJobs()
+
+	job, ok := registry.jobs[jobId]
+	if !ok {
+		return Snapshot{}, nil, false
+	}
+
+	return job.snapshot, job.result, true
+}
+
+// List returns the state of all jobs, ordered by id.
+func (registry *Registry) List() []Snapshot {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.removeExpiredJobs()
+
+	snapshots := []Snapshot{}
+	for jobId := models.Id(1); jobId <= registry.lastJobId; jobId++ {
+		if job, ok := registry.jobs[jobId]; ok {
+			snapshots = append(snapshots, job.snapshot)
+		}
+	}
+
+	return snapshots
+}
+
+// Wait blocks until all running jobs have finished.
+func (registry *Registry) Wait() {
+	registry.running.Wait()
+}
+
+// Shutdown cancels all running jobs and waits at most timeout for them to stop.
+// It returns false if some jobs were still running when the timeout expired.
+func (registry *Registry) Shutdown(timeout time.Duration) bool {
+	registry.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		registry.running.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// removeExpiredJobs forgets the jobs that finished longer than the retention period ago.
+// Must be called with the mutex locked.
+func (registry *Registry) removeExpiredJobs() {
+	cutoff := models.Now() - models.Timestamp(registry.retention.Seconds())
+
+	for jobId, job := range registry.jobs {
+		if job.snapshot.Status != Running && job.snapshot.FinishedAt < cutoff {
+			delete(registry.jobs, jobId)
+		}
+	}
+}
+
+// publish reports the state of the job to its owner.
+// It is called without holding the mutex, as publishing may have to wait for the subscribers.
+func (registry *Registry) publish(snapshot Snapshot) {
+	if registry.publisher == nil {
+		return
+	}
+
+	payload := events.JobPayload{
+		JobId:  snapshot.JobId,
+		Status: string(snapshot.Status),
+		Done:   snapshot.Done,
+		Total:  snapshot.Total,
+		Error:  snapshot.Error,
+	}
+
+	registry.publisher.Publish(events.NewJobUpdated(payload, snapshot.OwnerId))
+}
+
+// Tracker lets work report its progress.
+type Tracker struct {
+	registry *Registry
+	job      *job
+}
+
+// SetProgress records that done out of total steps have been completed.
+// To keep the number of events in check, progress is only published when it has increased by at least a percent.
+func (tracker *Tracker) SetProgress(done int, total int) {
+	registry := tracker.registry
+	job := tracker.job
+
+	registry.mutex.Lock()
+	job.snapshot.Done = done
+	job.snapshot.Total = total
+
+	percentage := 0
+	if total > 0 {
+		percentage = done * 100 / total
+	}
+
+	shouldPublish := percentage > job.publishedPercentage || done == 0
+	if shouldPublish {
+		job.publishedPercentage = percentage
+	}
+	snapshot := job.snapshot
+	registry.mutex.Unlock()
+
+	if shouldPublish {
+		registry.publish(snapshot)
+	}
+}
+
+// Publish sends an event on behalf of the job, e.g., to report changes it made to the data.
+func (tracker *Tracker) Publish(event *events.Event) {
+	if tracker.registry.publisher != nil {
+		tracker.registry.publisher.Publish(event)
+	}
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+type Options struct {
+	// Either TextFormat or JSONFormat
+	Format string
+
+	// Minimum level of messages that are written, e.g., "debug" or "info"
+	Level string
+
+	// File to write to. If empty, messages are written to stdout.
+	File string
+
+	// Size in megabytes at which the file is rotated
+	MaxSizeInMegabytes int
+
+	// Number of rotated files kept around, next to the current one
+	MaxBackups int
+}
+
+// NewLogger creates a logger as described by the options.
+// The returned closer must be called once the logger is no longer used.
+func NewLogger(options *Options) (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(options.Level)); err != nil {
+		return nil, nil, fmt.Errorf("invalid log level %s: %w", options.Level, err)
+	}
+
+	var writer io.WriteCloser
+	if options.File == "" {
+		writer = nopCloser{os.Stdout}
+	} else {
+		file, err := OpenRotatingFile(options.File, int64(options.MaxSizeInMegabytes)*1024*1024, options.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		writer = file
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(options.Format) {
+	case TextFormat:
+		handler = slog.NewTextHandler(writer, handlerOptions)
+	case JSONFormat:
+		handler = slog.NewJSONHandler(writer, handlerOptions)
+	default:
+		writer.Close()
+		return nil, nil, fmt.Errorf("invalid log format %s; expected %s or %s", options.Format, TextFormat, JSONFormat)
+	}
+
+	return slog.New(handler), writer, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bctbackend/database/models"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIdHeader = "X-Request-Id"
+
+	requestIdContextKey = "bct_request_id"
+	loggerContextKey    = "bct_logger"
+)
+
+// Request ids passed along by a proxy are reused as long as they cannot mess up the logs
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Middleware gives every request an id, which is echoed in the X-Request-Id header,
+// and a logger that adds this id to every message, so that all lines belonging to one request can be found.
+// Once the request has been handled, a summary line is logged.
+func Middleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+
+		requestId := context.GetHeader(RequestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId = generateRequestId()
+		}
+
+		context.Set(requestIdContextKey, requestId)
+		context.Set(loggerContextKey, slog.Default().With(slog.String("request_id", requestId)))
+		context.Header(RequestIdHeader, requestId)
+
+		context.Next()
+
+		FromContext(context).Info(
+			"Handled request",
+			slog.String("method", context.Request.Method),
+			slog.String("path", context.Request.URL.Path),
+			slog.Int("status", context.Writer.Status()),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", context.ClientIP()),
+		)
+	}
+}
+
+// WithUser adds the authenticated user to the logger of the request.
+func WithUser(context *gin.Context, userId models.Id, roleId models.RoleId) {
+	logger := FromContext(context).With(slog.Int64("user_id", userId.Int64()), slog.String("role", roleId.Name()))
+	context.Set(loggerContextKey, logger)
+}
+
+// FromContext returns the logger of the request.
+// Falls back on the default logger for requests that did not pass through the middleware.
+func FromContext(context *gin.Context) *slog.Logger {
+	if value, exists := context.Get(loggerContextKey); exists {
+		if logger, ok := value.(*slog.Logger); ok {
+			return logger
+		}
+	}
+
+	return slog.Default()
+}
+
+// RequestId returns the id of the request, or the empty string for requests that did not pass through the middleware.
+func RequestId(context *gin.Context) string {
+	return context.GetString(requestIdContextKey)
+}
+
+func generateRequestId() string {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// RotatingFile is a log file that is moved aside once it grows too large.
+// Rotated files get a numbered suffix, e.g., bct.log.1 being the most recent one,
+// and only the most recent ones are kept.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("maximum log file size must be positive")
+	}
+
+	rotatingFile := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := rotatingFile.open(); err != nil {
+		return nil, err
+	}
+
+	return rotatingFile, nil
+}
+
+func (rotatingFile *RotatingFile) Write(bytes []byte) (int, error) {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+
+	// If rotation fails, the log keeps growing rather than losing messages
+	var rotationErr error
+	if rotatingFile.size > 0 && rotatingFile.size+int64(len(bytes)) > rotatingFile.maxSize {
+		rotationErr = rotatingFile.rotate()
+	}
+
+	if rotatingFile.file == nil {
+		return 0, rotationErr
+	}
+
+	written, err := rotatingFile.file.Write(bytes)
+	rotatingFile.size += int64(written)
+	return written, errors.Join(rotationErr, err)
+}
+
+func (rotatingFile *RotatingFile) Close() error {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+
+	if rotatingFile.file == nil {
+		return nil
+	}
+
+	return rotatingFile.file.Close()
+}
+
+func (rotatingFile *RotatingFile) open() error {
+	file, err := os.OpenFile(rotatingFile.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	rotatingFile.file = file
+	rotatingFile.size = info.Size()
+	return nil
+}
+
+// rotate must be called with the mutex held.
+// The log file is reopened even if moving it aside fails, so that later messages are not lost.
+func (rotatingFile *RotatingFile) rotate() error {
+	closeErr := rotatingFile.file.Close()
+	rotatingFile.file = nil
+	if closeErr != nil {
+		closeErr = fmt.Errorf("failed to close log file: %w", closeErr)
+	}
+
+	moveErr := rotatingFile.moveAside()
+	openErr := rotatingFile.open()
+
+	return errors.Join(closeErr, moveErr, openErr)
+}
+
+// moveAside turns the log file into the most recent backup, or removes it if no backups are kept.
+func (rotatingFile *RotatingFile) moveAside() error {
+	if rotatingFile.maxBackups == 0 {
+		if err := os.Remove(rotatingFile.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove log file: %w", err)
+		}
+	} else {
+		// Shift the backups, dropping the oldest one
+		for index := rotatingFile.maxBackups - 1; index >= 1; index-- {
+			if err := os.Rename(rotatingFile.backupPath(index), rotatingFile.backupPath(index+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to rotate log file: %w", err)
+			}
+		}
+
+		if err := os.Rename(rotatingFile.path, rotatingFile.backupPath(1)); err != nil {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (rotatingFile *RotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", rotatingFile.path, index)
+}
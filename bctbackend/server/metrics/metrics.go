@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Upper bounds, in seconds, of the buckets in which request latencies are counted
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry collects the metrics of the server and writes them in the Prometheus text format.
+// Counters are kept in memory and start from zero when the server restarts.
+type Registry struct {
+	mutex              sync.Mutex
+	requests           map[requestKey]uint64
+	latencies          map[routeKey]*histogram
+	failures           map[string]uint64
+	salesCreated       uint64
+	databaseBusyErrors uint64
+	gauges             []*gauge
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type requestKey struct {
+	routeKey
+	status int
+}
+
+type histogram struct {
+	// counts[i] is the number of observations that fell in bucket i, the last bucket being +Inf
+	counts []uint64
+	sum    float64
+	total  uint64
+}
+
+// gauge is a value that is determined at the time the metrics are requested.
+type gauge struct {
+	name  string
+	help  string
+	value func() (float64, error)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		requests:  make(map[requestKey]uint64),
+		latencies: make(map[routeKey]*histogram),
+		failures:  make(map[string]uint64),
+	}
+}
+
+// Gauge registers a value that is computed every time the metrics are requested.
+// Must not be called once the registry is in use.
+func (registry *Registry) Gauge(name string, help string, value func() (float64, error)) {
+	registry.gauges = append(registry.gauges, &gauge{name: name, help: help, value: value})
+}
+
+// ObserveRequest counts a handled request and records how long it took.
+func (registry *Registry) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	key := routeKey{method: method, route: route}
+	registry.requests[requestKey{routeKey: key, status: status}]++
+
+	latencies, ok := registry.latencies[key]
+	if !ok {
+		latencies = &histogram{counts: make([]uint64, len(latencyBuckets)+1)}
+		registry.latencies[key] = latencies
+	}
+
+	seconds := duration.Seconds()
+	bucket, _ := slices.BinarySearch(latencyBuckets, seconds)
+	latencies.counts[bucket]++
+	latencies.sum += seconds
+	latencies.total++
+}
+
+// ObserveFailure counts a failure response of the given type.
+func (registry *Registry) ObserveFailure(failureType string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.failures[failureType]++
+}
+
+func (registry *Registry) ObserveSaleCreated() {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.salesCreated++
+}
+
+// ObserveDatabaseBusy counts a request that failed because the database was locked by another connection.
+func (registry *Registry) ObserveDatabaseBusy() {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.databaseBusyErrors++
+}
+
+// Write writes all metrics in the Prometheus text exposition format.
+// Gauges whose value cannot be determined are left out.
+func (registry *Registry) Write(writer io.Writer) error {
+	// Gauges can query the database, which should not happen while holding the lock
+	gaugeValues := make([]float64, len(registry.gauges))
+	gaugeErrors := make([]error, len(registry.gauges))
+	for index, gauge := range registry.gauges {
+		gaugeValues[index], gaugeErrors[index] = gauge.value()
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	var builder strings.Builder
+
+	writeHeader(&builder, "bct_http_requests_total", "counter", "Number of handled HTTP requests.")
+	for _, key := range sortedKeys(registry.requests, compareRequestKeys) {
+		writeSample(&builder, "bct_http_requests_total", labels("method", key.method, "route", key.route, "status", strconv.Itoa(key.status)), float64(registry.requests[key]))
+	}
+
+	writeHeader(&builder, "bct_http_request_duration_seconds", "histogram", "Time taken to handle HTTP requests.")
+	for _, key := range sortedKeys(registry.latencies, compareRouteKeys) {
+		latencies := registry.latencies[key]
+
+		cumulative := uint64(0)
+		for index, bound := range latencyBuckets {
+			cumulative += latencies.counts[index]
+			writeSample(&builder, "bct_http_request_duration_seconds_bucket", labels("method", key.method, "route", key.route, "le", formatFloat(bound)), float64(cumulative))
+		}
+		writeSample(&builder, "bct_http_request_duration_seconds_bucket", labels("method", key.method, "route", key.route, "le", "+Inf"), float64(latencies.total))
+		writeSample(&builder, "bct_http_request_duration_seconds_sum", labels("method", key.method, "route", key.route), latencies.sum)
+		writeSample(&builder, "bct_http_request_duration_seconds_count", labels("method", key.method, "route", key.route), float64(latencies.total))
+	}
+
+	writeHeader(&builder, "bct_failure_responses_total", "counter", "Number of failure responses by type.")
+	for _, failureType := range sortedKeys(registry.failures, strings.Compare) {
+		writeSample(&builder, "bct_failure_responses_total", labels("type", failureType), float64(registry.failures[failureType]))
+	}
+
+	writeHeader(&builder, "bct_sales_created_total", "counter", "Number of sales created since the server started.")
+	writeSample(&builder, "bct_sales_created_total", "", float64(registry.salesCreated))
+
+	writeHeader(&builder, "bct_sqlite_busy_errors_total", "counter", "Number of requests that failed because the database was locked.")
+	writeSample(&builder, "bct_sqlite_busy_errors_total", "", float64(registry.databaseBusyErrors))
+
+	for index, gauge := range registry.gauges {
+		if gaugeErrors[index] != nil {
+			continue
+		}
+
+		writeHeader(&builder, gauge.name, "gauge", gauge.help)
+		writeSample(&builder, gauge.name, "", gaugeValues[index])
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func writeHeader(builder *strings.Builder, name string, kind string, help string) {
+	fmt.Fprintf(builder, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(builder, "# TYPE %s %s\n", name, kind)
+}
+
+func writeSample(builder *strings.Builder, name string, labels string, value float64) {
+	fmt.Fprintf(builder, "%s%s %s\n", name, labels, formatFloat(value))
+}
+
+// labels formats alternating names and values as a Prometheus label set.
+func labels(namesAndValues ...string) string {
+	parts := make([]string, 0, len(namesAndValues)/2)
+	for index := 0; index+1 < len(namesAndValues); index += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, namesAndValues[index], escapeLabelValue(namesAndValues[index+1])))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sortedKeys makes the output deterministic, which keeps it diffable.
+func sortedKeys[K comparable, V any](m map[K]V, compare func(K, K) int) []K {
+	return slices.SortedFunc(maps.Keys(m), compare)
+}
+
+func compareRouteKeys(x routeKey, y routeKey) int {
+	if result := strings.Compare(x.route, y.route); result != 0 {
+		return result
+	}
+
+	return strings.Compare(x.method, y.method)
+}
+
+func compareRequestKeys(x requestKey, y requestKey) int {
+	if result := compareRouteKeys(x.routeKey, y.routeKey); result != 0 {
+		return result
+	}
+
+	return x.status - y.status
+}
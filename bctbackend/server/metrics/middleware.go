@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/server/failure_response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const registryContextKey = "bct_metrics_registry"
+
+// Middleware records every request passing through the router.
+// Requests are grouped by route template, e.g., /api/v1/items/:id, so that ids do not lead to an unbounded number of series.
+func (registry *Registry) Middleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+
+		context.Next()
+
+		route := context.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		registry.ObserveRequest(context.Request.Method, route, context.Writer.Status(), time.Since(start))
+
+		if failure, ok := failure_response.FromContext(context); ok {
+			registry.ObserveFailure(failure.Type)
+		}
+
+		for _, ginError := range context.Errors {
+			if dberr.IsDatabaseBusy(ginError.Err) {
+				registry.ObserveDatabaseBusy()
+				break
+			}
+		}
+	}
+}
+
+// StoreInContext makes the registry available to request handlers.
+func StoreInContext(context *gin.Context, registry *Registry) {
+	context.Set(registryContextKey, registry)
+}
+
+// FromContext returns the registry associated with the request, if any.
+func FromContext(context *gin.Context) (*Registry, bool) {
+	value, exists := context.Get(registryContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	registry, ok := value.(*Registry)
+	return registry, ok
+}
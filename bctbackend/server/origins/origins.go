@@ -0,0 +1,63 @@
+package origins
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Policy decides which origins are allowed to make cross-origin requests
+// and to open websocket connections.
+type Policy struct {
+	allowed  map[string]bool
+	allowAll bool
+}
+
+// NewPolicy creates a policy that accepts the given origins.
+// If allowAll is set, every origin is accepted; this is meant for development only.
+func NewPolicy(allowedOrigins []string, allowAll bool) *Policy {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[normalize(origin)] = true
+	}
+
+	return &Policy{
+		allowed:  allowed,
+		allowAll: allowAll,
+	}
+}
+
+// AllowsAll returns true if the policy is permissive.
+func (policy *Policy) AllowsAll() bool {
+	return policy.allowAll
+}
+
+// Allows checks whether the origin is on the allowlist. Rejected origins are logged.
+func (policy *Policy) Allows(origin string) bool {
+	if policy.allowAll || policy.allowed[normalize(origin)] {
+		return true
+	}
+
+	slog.Warn("Rejected request from disallowed origin", slog.String("origin", origin))
+	return false
+}
+
+// AllowsRequest checks whether the request is either same-origin or comes from an allowed origin.
+// Requests without an Origin header do not originate from a browser and are accepted.
+func (policy *Policy) AllowsRequest(request *http.Request) bool {
+	origin := request.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	if parsedOrigin, err := url.Parse(origin); err == nil && strings.EqualFold(parsedOrigin.Host, request.Host) {
+		return true
+	}
+
+	return policy.Allows(origin)
+}
+
+func normalize(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
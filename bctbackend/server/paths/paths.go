@@ -92,6 +92,19 @@ func Root() *URL {
 	return NewURL()
 }
 
+// Health and metrics endpoints live outside the REST API, at the locations monitoring tools expect them
+func Healthz() *URL {
+	return Root().AddPathSegment("healthz")
+}
+
+func Readyz() *URL {
+	return Root().AddPathSegment("readyz")
+}
+
+func Metrics() *URL {
+	return Root().AddPathSegment("metrics")
+}
+
 func RESTRoot() *URL {
 	return Root().AddPathSegment("api").AddPathSegment("v1")
 }
@@ -108,6 +121,34 @@ func Labels() *URL {
 	return RESTRoot().AddPathSegment("labels")
 }
 
+func LabelCalibration() *URL {
+	return Labels().AddPathSegment("calibration")
+}
+
+func LabelCheck() *URL {
+	return Labels().AddPathSegment("check")
+}
+
+func LabelJobs() *URL {
+	return Labels().AddPathSegment("jobs")
+}
+
+func LabelJobStr(jobId string) *URL {
+	return LabelJobs().AddPathSegment(jobId)
+}
+
+func LabelJob(id models.Id) *URL {
+	return LabelJobStr(id.String())
+}
+
+func LabelJobResultStr(jobId string) *URL {
+	return LabelJobStr(jobId).AddPathSegment("result")
+}
+
+func LabelJobResult(id models.Id) *URL {
+	return LabelJobResultStr(id.String())
+}
+
 func Users() *URL {
 	return RESTRoot().AddPathSegment("users")
 }
@@ -120,6 +161,22 @@ func User(id models.Id) *URL {
 	return UserStr(id.String())
 }
 
+func UserSessionsStr(userId string) *URL {
+	return UserStr(userId).AddPathSegment("sessions")
+}
+
+func UserSessions(id models.Id) *URL {
+	return UserSessionsStr(id.String())
+}
+
+func Sessions() *URL {
+	return RESTRoot().AddPathSegment("sessions")
+}
+
+func SessionStr(sessionHandle string) *URL {
+	return Sessions().AddPathSegment(sessionHandle)
+}
+
 func Sales() *URL {
 	return RESTRoot().AddPathSegment("sales")
 }
@@ -140,10 +197,34 @@ func ItemStr(itemId string) *URL {
 	return Items().AddPathSegment(itemId)
 }
 
+func Barcodes() *URL {
+	return RESTRoot().AddPathSegment("barcodes")
+}
+
+func BarcodeStr(payload string) *URL {
+	return Barcodes().AddPathSegment(payload)
+}
+
 func Item(id models.Id) *URL {
 	return ItemStr(id.String())
 }
 
+func ItemFreezeStr(itemId string) *URL {
+	return ItemStr(itemId).AddPathSegment("freeze")
+}
+
+func ItemFreeze(id models.Id) *URL {
+	return ItemFreezeStr(id.String())
+}
+
+func ItemUnfreezeStr(itemId string) *URL {
+	return ItemStr(itemId).AddPathSegment("unfreeze")
+}
+
+func ItemUnfreeze(id models.Id) *URL {
+	return ItemUnfreezeStr(id.String())
+}
+
 func Categories() *URL {
 	return RESTRoot().AddPathSegment("categories")
 }
@@ -177,6 +258,42 @@ func CashierSales(cashierId models.Id) *URL {
 	return CashierSalesStr(cashierId.String())
 }
 
+func Permissions() *URL {
+	return RESTRoot().AddPathSegment("permissions")
+}
+
+func ApiTokens() *URL {
+	return RESTRoot().AddPathSegment("tokens")
+}
+
+func ApiTokenStr(tokenId string) *URL {
+	return ApiTokens().AddPathSegment(tokenId)
+}
+
+func ApiToken(id models.Id) *URL {
+	return ApiTokenStr(id.String())
+}
+
+func LabelLayouts() *URL {
+	return RESTRoot().AddPathSegment("label-layouts")
+}
+
+func LabelLayoutStr(layoutId string) *URL {
+	return LabelLayouts().AddPathSegment(layoutId)
+}
+
+func LabelLayout(id models.Id) *URL {
+	return LabelLayoutStr(id.String())
+}
+
 func Websocket() *URL {
 	return RESTRoot().AddPathSegment("websocket")
 }
+
+func Events() *URL {
+	return RESTRoot().AddPathSegment("events")
+}
+
+func WebsocketClients() *URL {
+	return Websocket().AddPathSegment("clients")
+}
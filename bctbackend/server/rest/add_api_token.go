@@ -0,0 +1,97 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddApiTokenPayload struct {
+	UserId           models.Id `json:"userId" binding:"required"`
+	Description      string    `json:"description"`
+	Actions          []string  `json:"actions"`
+	ExpiresInSeconds *int64    `json:"expiresInSeconds"`
+}
+
+type AddApiTokenSuccessResponse struct {
+	TokenId models.Id `json:"tokenId"`
+	Token   string    `json:"token"`
+}
+
+// @Summary Create an API token.
+// @Description Creates an API token for non-browser clients, which pass it as "Authorization: Bearer <token>".
+// @Description The token acts on behalf of the given user. It can be further restricted to a list of actions
+// @Description and can be given an expiration time. The token is only returned once.
+// @Description Only accessible to admins.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param AddApiTokenPayload body AddApiTokenPayload true "Token owner, restrictions and expiry"
+// @Success 201 {object} AddApiTokenSuccessResponse "Token successfully created"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload or invalid action"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Failure 404 {object} failure_response.FailureResponse "User does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /tokens [post]
+func AddApiToken(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var payload AddApiTokenPayload
+	if err := context.ShouldBindJSON(&payload); err != nil {
+		failure_response.InvalidRequest(context, "Failed to parse payload: "+err.Error())
+		return
+	}
+
+	owner, err := queries.GetUserWithId(db, payload.UserId)
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchUser) {
+			failure_response.UnknownUser(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	if err := authorization.ValidateTokenActions(owner.RoleId, payload.Actions); err != nil {
+		failure_response.InvalidAction(context, err.Error())
+		return
+	}
+
+	var expirationTime *models.Timestamp
+	if payload.ExpiresInSeconds != nil {
+		if *payload.ExpiresInSeconds <= 0 {
+			failure_response.InvalidRequest(context, "Expiration time must lie in the future")
+			return
+		}
+
+		timestamp := models.Now() + models.Timestamp(*payload.ExpiresInSeconds)
+		expirationTime = &timestamp
+	}
+
+	tokenId, token, err := queries.AddApiToken(db, owner.UserId, payload.Description, payload.Actions, expirationTime)
+	if err != nil {
+		failure_response.UnknownError(context, fmt.Errorf("Failed to add API token: %w", err))
+		return
+	}
+
+	logging.FromContext(context).Info("API token created", slog.Int64("token_id", tokenId.Int64()), slog.Int64("owner_id", owner.UserId.Int64()), slog.Int64("created_by", userId.Int64()))
+
+	response := AddApiTokenSuccessResponse{
+		TokenId: tokenId,
+		Token:   token,
+	}
+	context.JSON(http.StatusCreated, response)
+}
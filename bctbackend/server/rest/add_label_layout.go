@@ -0,0 +1,104 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/labels"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabelLayoutPayload struct {
+	Name   string `json:"name" binding:"required"`
+	Layout Layout `json:"layout"`
+}
+
+type AddLabelLayoutSuccessResponse struct {
+	LayoutId models.Id `json:"layoutId"`
+}
+
+// @Summary Add a label layout preset.
+// @Description Stores a named layout, e.g., of a commercially available label sheet, so that sellers can pick it
+// @Description instead of entering all measurements themselves. Only accessible to admins.
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param LabelLayoutPayload body LabelLayoutPayload true "Name and layout"
+// @Success 201 {object} AddLabelLayoutSuccessResponse "Layout successfully added"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload, invalid name or name already in use"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins, or invalid layout"
+// @Failure 404 {object} failure_response.FailureResponse "Label template does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /label-layouts [post]
+func AddLabelLayout(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var payload LabelLayoutPayload
+	if err := context.ShouldBindJSON(&payload); err != nil {
+		failure_response.InvalidRequest(context, "Failed to parse payload: "+err.Error())
+		return
+	}
+
+	layout := payload.Layout.toLabelLayout(payload.Name)
+	if !validateLabelLayout(context, configuration, layout) {
+		return
+	}
+
+	layoutId, err := queries.AddLabelLayout(db, layout)
+	if err != nil {
+		respondToLabelLayoutError(context, err)
+		return
+	}
+
+	logging.FromContext(context).Info("Label layout added", slog.Int64("layout_id", layoutId.Int64()), slog.String("name", layout.Name))
+
+	response := AddLabelLayoutSuccessResponse{
+		LayoutId: layoutId,
+	}
+	context.JSON(http.StatusCreated, response)
+}
+
+// validateLabelLayout checks that labels can be generated using the layout, that its template exists and that its symbology is known.
+// If not, a failure response is written and false is returned.
+func validateLabelLayout(context *gin.Context, configuration *configuration.Configuration, layout *models.LabelLayout) bool {
+	if _, err := labels.LayoutSettings(layout); err != nil {
+		failure_response.InvalidLayout(context, "Invalid layout: "+err.Error())
+		return false
+	}
+
+	if _, err := labels.FindTemplate(configuration.LabelTemplates, layout.Template); err != nil {
+		failure_response.UnknownLabelTemplate(context, err.Error())
+		return false
+	}
+
+	if _, err := labels.LayoutSymbology(layout); err != nil {
+		failure_response.InvalidLayout(context, "Invalid layout: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+// respondToLabelLayoutError writes the failure response corresponding to an error
+// returned by one of the label layout queries.
+func respondToLabelLayoutError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, dberr.ErrNoSuchLabelLayout):
+		failure_response.UnknownLabelLayout(context, err.Error())
+	case errors.Is(err, dberr.ErrInvalidLabelLayoutName):
+		failure_response.InvalidLabelLayoutName(context, err.Error())
+	case errors.Is(err, dberr.ErrLabelLayoutNameInUse):
+		failure_response.LabelLayoutNameInUse(context, err.Error())
+	default:
+		failure_response.UnknownError(context, err)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
 	"bctbackend/server/configuration"
+	"bctbackend/server/events"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -36,14 +39,9 @@ type AddSaleSuccessResponse struct {
 // @Failure 500 {object} failure_response.FailureResponse "Internal server error"
 // @Router /sales [post]
 func AddSale(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if !roleId.IsCashier() {
-		failure_response.WrongRole(context, "Adding sale is only accessible to cashiers")
-		return
-	}
-
 	var payload AddSalePayload
 	if err := context.ShouldBindJSON(&payload); err != nil {
-		slog.Error("Failed to parse AddSale payload", "error", err, "payload", payload)
+		logging.FromContext(context).Error("Failed to parse AddSale payload", "error", err, "payload", payload)
 		failure_response.InvalidRequest(context, "Failed to parse payload:"+err.Error())
 		return
 	}
@@ -73,16 +71,23 @@ func AddSale(context *gin.Context, configuration *configuration.Configuration, d
 		}
 
 		if errors.Is(err, dberr.ErrSaleRequiresCashier) {
-			slog.Error("[BUG] AddSale failed with ErrSaleRequiresCashier, but this should never occur as the role is checked before", "error", err)
+			logging.FromContext(context).Error("[BUG] AddSale failed with ErrSaleRequiresCashier, but this should never occur as the role is checked before", "error", err)
 			failure_response.Unknown(context, "Bug: should never occur as this is checked before")
 			return
 		}
 
-		slog.Error("Failed to add sale", "error", err)
-		failure_response.Unknown(context, "Failed to add sale: "+err.Error())
+		logging.FromContext(context).Error("Failed to add sale", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to add sale: %w", err))
 		return
 	}
 
+	if saleItems, err := queries.GetSaleItems(db, saleId); err != nil {
+		// The sale itself succeeded, so we only fail to notify clients
+		logging.FromContext(context).Error("Failed to look up items of new sale", slog.Int64("sale_id", saleId.Int64()), slog.String("error", err.Error()))
+	} else {
+		events.Publish(context, events.NewSaleCreated(saleId, userId, saleItems))
+	}
+
 	response := AddSaleSuccessResponse{SaleId: saleId}
 	context.JSON(http.StatusCreated, response)
 }
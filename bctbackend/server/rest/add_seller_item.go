@@ -4,11 +4,13 @@ import (
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
+	"bctbackend/server/events"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	"database/sql"
 	"errors"
-	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -38,11 +40,6 @@ type AddSellerItemResponse struct {
 // @Failure 500 {object} failure_response.FailureResponse "Failed to add item"
 // @Router /seller/{seller_id}/items [put]
 func AddSellerItem(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if !roleId.IsSeller() {
-		failure_response.WrongRole(context, "Must be seller to add item")
-		return
-	}
-
 	var uriParameters struct {
 		SellerId string `uri:"id" binding:"required"`
 	}
@@ -60,7 +57,7 @@ func AddSellerItem(context *gin.Context, configuration *configuration.Configurat
 	{
 		sellerExists, err := queries.UserWithIdExists(db, uriSellerId)
 		if err != nil {
-			failure_response.Unknown(context, err.Error())
+			failure_response.UnknownError(context, err)
 			return
 		}
 		if !sellerExists {
@@ -69,7 +66,7 @@ func AddSellerItem(context *gin.Context, configuration *configuration.Configurat
 		}
 	}
 
-	if uriSellerId != userId {
+	if !authorization.IsAllowedOn(authorization.AddSellerItem, userId, roleId, uriSellerId) {
 		failure_response.WrongSeller(context, "Logged in user does not match URI seller ID")
 		return
 	}
@@ -107,7 +104,7 @@ func AddSellerItem(context *gin.Context, configuration *configuration.Configurat
 		}
 
 		if errors.Is(err, dberr.ErrWrongRole) {
-			slog.Error("[BUG] Failed to add item to non-seller; this error should have been caught earlier")
+			logging.FromContext(context).Error("[BUG] Failed to add item to non-seller; this error should have been caught earlier")
 			failure_response.Unknown(context, "Bug: this error should not happen")
 			return
 		}
@@ -122,11 +119,14 @@ func AddSellerItem(context *gin.Context, configuration *configuration.Configurat
 			return
 		}
 
-		slog.Error("Failed to add seller item", "error", err)
-		failure_response.Unknown(context, err.Error())
+		logging.FromContext(context).Error("Failed to add seller item", "error", err)
+		failure_response.UnknownError(context, err)
 		return
 	}
 
+	events.Publish(context, events.NewItemAdded(itemId, uriSellerId, payload.CategoryId))
+	events.Publish(context, events.NewCategoryChanged(payload.CategoryId))
+
 	response := AddSellerItemResponse{ItemId: itemId}
 	context.JSON(http.StatusCreated, response)
 }
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/pdf"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// The font of the description had to be shrunk, but the description fits entirely
+	DescriptionShrunk = "shrunk"
+
+	// The description does not fit even at the minimum font size and will be cut off
+	DescriptionTruncated = "truncated"
+)
+
+type CheckLabelsPayload struct {
+	// Exactly one of Layout and LayoutId must be given
+	Layout   *Layout     `json:"layout,omitempty"`
+	LayoutId *models.Id  `json:"layoutId,omitempty"`
+	ItemIds  []models.Id `json:"itemIds"`
+}
+
+type LabelWarning struct {
+	ItemId models.Id `json:"itemId"`
+
+	// Either DescriptionShrunk or DescriptionTruncated
+	Kind string `json:"kind"`
+
+	// Font size in mm at which the description will be printed
+	FontSize float64 `json:"fontSize"`
+}
+
+type CheckLabelsSuccessResponse struct {
+	Warnings []*LabelWarning `json:"warnings"`
+}
+
+// @Summary Check whether the descriptions of items fit on their labels.
+// @Description Determines for each item whether its description will have to be printed in a smaller font or be cut off
+// @Description with the given layout. No labels are generated and the items are left untouched.
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param CheckLabelsPayload body CheckLabelsPayload true "Inline layout or layout preset id, and the items"
+// @Success 200 {object} CheckLabelsSuccessResponse "Warnings for the items whose descriptions do not fit"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload or no items given"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Items belong to another seller, or invalid layout"
+// @Failure 404 {object} failure_response.FailureResponse "Item or layout preset does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /labels/check [post]
+func CheckLabels(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var payload CheckLabelsPayload
+	if err := context.ShouldBindJSON(&payload); err != nil {
+		failure_response.InvalidRequest(context, "Failed to parse payload: "+err.Error())
+		return
+	}
+
+	if len(payload.ItemIds) == 0 {
+		failure_response.MissingItems(context, "No items provided")
+		return
+	}
+
+	_, labelData, ok := collectLabelData(context, db, userId, roleId, payload.ItemIds)
+	if !ok {
+		return
+	}
+
+	settings, layoutOptions, ok := determineLayoutSettings(context, configuration, db, payload.Layout, payload.LayoutId)
+	if !ok {
+		return
+	}
+
+	fits, err := pdf.CheckDescriptions(createPdfConfiguration(configuration), settings, labelData, layoutOptions...)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to check descriptions", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to check descriptions: %w", err))
+		return
+	}
+
+	warnings := []*LabelWarning{}
+	for index, fit := range fits {
+		if fit == nil || !fit.Shrunk && !fit.Truncated {
+			continue
+		}
+
+		kind := DescriptionShrunk
+		if fit.Truncated {
+			kind = DescriptionTruncated
+		}
+
+		warnings = append(warnings, &LabelWarning{
+			ItemId:   payload.ItemIds[index],
+			Kind:     kind,
+			FontSize: fit.FontSize,
+		})
+	}
+
+	context.JSON(http.StatusOK, CheckLabelsSuccessResponse{Warnings: warnings})
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/security"
+	"bctbackend/server/configuration"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setSessionCookies hands the session id and its CSRF token to the browser.
+// When served over TLS, the cookies are only sent over HTTPS and not along with cross-site requests.
+// Unlike the session id, the CSRF token must be readable by scripts.
+func setSessionCookies(context *gin.Context, configuration *configuration.Configuration, sessionId models.SessionId) {
+	writeCookie(context, configuration, security.SessionCookieName, string(sessionId), security.SessionDurationInSeconds, true)
+	writeCookie(context, configuration, security.CSRFCookieName, security.DeriveCSRFToken(sessionId), security.SessionDurationInSeconds, false)
+}
+
+// clearSessionCookies asks the browser to forget the session id and CSRF token.
+func clearSessionCookies(context *gin.Context, configuration *configuration.Configuration) {
+	writeCookie(context, configuration, security.SessionCookieName, "", -1, true)
+	writeCookie(context, configuration, security.CSRFCookieName, "", -1, false)
+}
+
+func writeCookie(context *gin.Context, configuration *configuration.Configuration, name string, value string, maxAge int, httpOnly bool) {
+	secure := configuration.TLSEnabled()
+	if secure {
+		context.SetSameSite(http.SameSiteStrictMode)
+	} else {
+		context.SetSameSite(http.SameSiteLaxMode)
+	}
+
+	context.SetCookie(name, value, maxAge, "/", configuration.CookieDomain, secure, httpOnly)
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/events"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FreezeItemSuccessResponse struct {
+}
+
+// @Summary Freeze an item.
+// @Description Freezes an item, preventing the seller from making further changes to it.
+// @Description This is done during intake, once the item has been checked.
+// @Tags items
+// @Param id path int true "Item ID"
+// @Success 204 {object} FreezeItemSuccessResponse "Item successfully frozen"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins, supervisors and volunteers, or item is hidden"
+// @Failure 404 {object} failure_response.FailureResponse "Item not found"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /items/{id}/freeze [post]
+func FreezeItem(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	updateFreezeStatusOfItem(context, db, true)
+}
+
+// @Summary Unfreeze an item.
+// @Description Unfreezes an item, allowing the seller to make changes to it again.
+// @Tags items
+// @Param id path int true "Item ID"
+// @Success 204 {object} FreezeItemSuccessResponse "Item successfully unfrozen"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins and supervisors, or item is hidden"
+// @Failure 404 {object} failure_response.FailureResponse "Item not found"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /items/{id}/unfreeze [post]
+func UnfreezeItem(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	updateFreezeStatusOfItem(context, db, false)
+}
+
+func updateFreezeStatusOfItem(context *gin.Context, db *sql.DB, frozen bool) {
+	var uriParameters struct {
+		ItemId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	itemId, err := models.ParseId(uriParameters.ItemId)
+	if err != nil {
+		failure_response.InvalidItemId(context, err.Error())
+		return
+	}
+
+	if err := queries.UpdateFreezeStatusOfItems(db, []models.Id{itemId}, frozen); err != nil {
+		if errors.Is(err, dberr.ErrNoSuchItem) {
+			failure_response.UnknownItem(context, err.Error())
+			return
+		}
+
+		if errors.Is(err, dberr.ErrItemHidden) {
+			failure_response.ItemHidden(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	if item, err := queries.GetItemWithId(db, itemId); err != nil {
+		// The item itself was updated, so we only fail to notify clients
+		logging.FromContext(context).Error("Failed to look up frozen item", slog.Int64("item_id", itemId.Int64()), slog.String("error", err.Error()))
+	} else if frozen {
+		events.Publish(context, events.NewItemsFrozen([]*models.Item{item}))
+	} else {
+		events.Publish(context, events.NewItemsUnfrozen([]*models.Item{item}))
+	}
+
+	context.Status(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/pdf"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GenerateCalibrationPagePayload struct {
+	// Exactly one of Layout and LayoutId must be given
+	Layout   *Layout    `json:"layout,omitempty"`
+	LayoutId *models.Id `json:"layoutId,omitempty"`
+}
+
+// @Summary Generate a calibration page.
+// @Description Generates a PDF page that outlines every cell of the layout and shows its coordinates.
+// @Description Printing it on an empty label sheet shows whether the layout matches the sheet. No items are involved.
+// @Tags labels
+// @Accept json
+// @Produce application/pdf
+// @Param GenerateCalibrationPagePayload body GenerateCalibrationPagePayload true "Inline layout or layout preset id"
+// @Success 200 {file} file "Calibration page"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins and sellers, or invalid layout"
+// @Failure 404 {object} failure_response.FailureResponse "Layout preset does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /labels/calibration [post]
+func GenerateCalibrationPage(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var payload GenerateCalibrationPagePayload
+	if err := context.ShouldBindJSON(&payload); err != nil {
+		failure_response.InvalidRequest(context, "Failed to parse payload: "+err.Error())
+		return
+	}
+
+	settings, _, ok := determineLayoutSettings(context, configuration, db, payload.Layout, payload.LayoutId)
+	if !ok {
+		return
+	}
+
+	builder, err := pdf.GenerateCalibrationPdf(createPdfConfiguration(configuration), settings)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to generate calibration page", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to generate calibration page: %w", err))
+		return
+	}
+
+	buffer, err := builder.WriteToBuffer()
+	if err != nil {
+		logging.FromContext(context).Error("Failed to write PDF to buffer", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to write PDF to buffer: %w", err))
+		return
+	}
+
+	context.DataFromReader(
+		http.StatusOK,
+		int64(buffer.Len()),
+		"application/pdf",
+		buffer,
+		map[string]string{"Content-Disposition": "attachment; filename=calibration.pdf"},
+	)
+}
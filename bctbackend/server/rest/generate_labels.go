@@ -2,19 +2,24 @@ package rest
 
 import (
 	"bctbackend/algorithms"
-	"bctbackend/server/configuration"
+	"bctbackend/barcode"
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/labels"
 	"bctbackend/pdf"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"bctbackend/server/events"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"bctbackend/zpl"
+	"bytes"
 	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 
-	"log/slog"
-
 	"github.com/gin-gonic/gin"
 )
 
@@ -34,168 +39,392 @@ type Layout struct {
 	LabelMargins Insets  `json:"labelMargins"`
 	LabelPadding Insets  `json:"labelPadding"`
 	FontSize     float64 `json:"fontSize"`
+
+	// Name of one of the label templates defined in the configuration; empty for the default template
+	Template string `json:"template,omitempty"`
+
+	// Barcode symbology, one of "code128", "qr", "datamatrix" and "ean13"; empty for the configured symbology
+	Symbology string `json:"symbology,omitempty"`
+}
+
+// Cell identifies a label on a sheet. Columns and rows are counted from 0, starting at the top left.
+type Cell struct {
+	Column int `json:"column"`
+	Row    int `json:"row"`
+}
+
+// toLabelLayout converts the layout to a label layout with the given name and no id.
+func (layout *Layout) toLabelLayout(name string) *models.LabelLayout {
+	return &models.LabelLayout{
+		Name:         name,
+		PaperWidth:   layout.PaperWidth,
+		PaperHeight:  layout.PaperHeight,
+		PaperMargins: models.Insets(layout.PaperMargins),
+		Columns:      layout.Columns,
+		Rows:         layout.Rows,
+		LabelMargins: models.Insets(layout.LabelMargins),
+		LabelPadding: models.Insets(layout.LabelPadding),
+		FontSize:     layout.FontSize,
+		Template:     layout.Template,
+		Symbology:    layout.Symbology,
+	}
+}
+
+func convertLabelLayoutToLayout(layout *models.LabelLayout) Layout {
+	return Layout{
+		PaperWidth:   layout.PaperWidth,
+		PaperHeight:  layout.PaperHeight,
+		PaperMargins: Insets(layout.PaperMargins),
+		Columns:      layout.Columns,
+		Rows:         layout.Rows,
+		LabelMargins: Insets(layout.LabelMargins),
+		LabelPadding: Insets(layout.LabelPadding),
+		FontSize:     layout.FontSize,
+		Template:     layout.Template,
+		Symbology:    layout.Symbology,
+	}
 }
 
+// Formats in which labels can be generated, selected with the format query parameter
+const (
+	// Sheets of labels as described by the layout
+	PdfLabelFormat = "pdf"
+
+	// Separate labels for thermal printers
+	ZplLabelFormat = "zpl"
+)
+
 type GenerateLabelsPayload struct {
-	Layout  Layout      `json:"layout"`
-	ItemIds []models.Id `json:"itemIds"`
+	// Exactly one of Layout and LayoutId must be given, except for ZPL labels, for which both are optional.
+	// LayoutId refers to a layout preset managed by the admins.
+	Layout   *Layout     `json:"layout,omitempty"`
+	LayoutId *models.Id  `json:"layoutId,omitempty"`
+	ItemIds  []models.Id `json:"itemIds"`
+
+	// Cell of the first sheet at which to start printing, so that partially used sheets can be reused.
+	// Defaults to the top left cell.
+	StartCell *Cell `json:"startCell,omitempty"`
+
+	// Cells of the first sheet that must be left empty
+	SkippedCells []Cell `json:"skippedCells,omitempty"`
+
+	// A preview is marked as such and leaves the items unfrozen, so that sellers can check the alignment
+	// of the labels without losing the ability to edit their items.
+	Preview bool `json:"preview,omitempty"`
+
+	// Draws a grid over each label
+	ShowGrid bool `json:"showGrid,omitempty"`
 }
 
 func GenerateLabels(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if !roleId.IsSeller() {
-		slog.Error("GenerateLabels called with wrong role", "userId", userId, "roleId", roleId)
-		failure_response.WrongRole(context, "Only sellers can generate labels")
+	format := context.DefaultQuery("format", PdfLabelFormat)
+	if format != PdfLabelFormat && format != ZplLabelFormat {
+		failure_response.UnknownLabelFormat(context, fmt.Sprintf("Unknown label format %q; expected %q or %q", format, PdfLabelFormat, ZplLabelFormat))
 		return
 	}
 
 	var payload GenerateLabelsPayload
 	if err := context.ShouldBindJSON(&payload); err != nil {
-		slog.Error("Failed to parse payload for GenerateLabels endpoint", "error", err)
+		logging.FromContext(context).Error("Failed to parse payload for GenerateLabels endpoint", "error", err)
 		failure_response.InvalidRequest(context, "Failed to parse payload:"+err.Error())
 		return
 	}
 
 	if len(payload.ItemIds) == 0 {
-		slog.Error("GenerateLabels called with no items", "userId", userId)
+		logging.FromContext(context).Error("GenerateLabels called with no items", "userId", userId)
 		failure_response.MissingItems(context, "No items provided")
 		return
 	}
 
-	itemTable, err := queries.GetItemsWithIds(db, payload.ItemIds)
-	if err != nil {
-		if errors.Is(err, dberr.ErrNoSuchItem) {
-			failure_response.UnknownItem(context, err.Error())
-			return
-		}
+	itemTable, labelData, ok := collectLabelData(context, db, userId, roleId, payload.ItemIds)
+	if !ok {
+		return
+	}
 
-		failure_response.Unknown(context, "Failed to fetch items: "+err.Error())
+	var buffer *bytes.Buffer
+	var contentType, filename string
+	switch format {
+	case ZplLabelFormat:
+		buffer, ok = generateZplLabels(context, configuration, db, &payload, labelData)
+		contentType, filename = "text/plain; charset=utf-8", "labels.zpl"
+	default:
+		buffer, ok = generatePdfLabels(context, configuration, db, &payload, labelData)
+		contentType, filename = "application/pdf", "labels.pdf"
+	}
+	if !ok {
+		return
 	}
 
-	// Verify that all items belong to the active seller
-	for _, item := range itemTable {
-		if item.SellerID != userId {
-			slog.Error("GenerateLabels called with items not owned by the seller", "itemId", item.ItemID, "userId", userId)
-			failure_response.WrongSeller(context, "labels can only be generated by the owning seller")
+	if !payload.Preview {
+		if err := queries.UpdateFreezeStatusOfItems(db, payload.ItemIds, true); err != nil {
+			logging.FromContext(context).Error("Failed to freeze items", "error", err)
+			failure_response.UnknownError(context, fmt.Errorf("Failed to freeze items: %w", err))
 			return
 		}
-	}
 
-	labelData, err := collectLabelData(db, itemTable, payload.ItemIds)
-	if err != nil {
-		slog.Error("GenerateLabels failed to collect label data", "error", err)
-		failure_response.Unknown(context, "Failed to collect label data: "+err.Error())
-		return
+		frozenItems := algorithms.Map(payload.ItemIds, func(itemId models.Id) *models.Item { return itemTable[itemId] })
+		events.Publish(context, events.NewItemsFrozen(frozenItems))
 	}
 
-	settings, err := pdf.NewLayoutSettings(
-		pdf.WithPaperSize(
-			payload.Layout.PaperWidth,
-			payload.Layout.PaperHeight,
-		),
-		pdf.WithPaperMargins(
-			payload.Layout.PaperMargins.Top,
-			payload.Layout.PaperMargins.Right,
-			payload.Layout.PaperMargins.Bottom,
-			payload.Layout.PaperMargins.Left,
-		),
-		pdf.WithGridSize(payload.Layout.Columns, payload.Layout.Rows),
-		pdf.WithLabelMargins(
-			payload.Layout.LabelMargins.Top,
-			payload.Layout.LabelMargins.Right,
-			payload.Layout.LabelMargins.Bottom,
-			payload.Layout.LabelMargins.Left,
-		),
-		pdf.WithLabelPadding(
-			payload.Layout.LabelPadding.Top,
-			payload.Layout.LabelPadding.Right,
-			payload.Layout.LabelPadding.Bottom,
-			payload.Layout.LabelPadding.Left,
-		),
-		pdf.WithFontSize(payload.Layout.FontSize),
+	context.Header("Content-Disposition", "attachment; filename="+filename)
+	context.DataFromReader(
+		http.StatusOK,
+		int64(buffer.Len()),
+		contentType,
+		buffer,
+		map[string]string{"Content-Disposition": "attachment; filename=" + filename},
 	)
-	if err != nil {
-		failure_response.InvalidLayout(context, "Failed to parse layout: "+err.Error())
-		return
+}
+
+// generatePdfLabels lays out the labels on sheets.
+// If this fails, a failure response is written and false is returned.
+func generatePdfLabels(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, payload *GenerateLabelsPayload, labelData []*pdf.LabelData) (*bytes.Buffer, bool) {
+	settings, layoutOptions, ok := determineLayoutSettings(context, configuration, db, payload.Layout, payload.LayoutId)
+	if !ok {
+		return nil, false
 	}
 
-	pdfConfiguration := pdf.Configuration{
-		FontDirectory: configuration.FontDirectory,
-		FontFilename:  configuration.FontFilename,
-		FontFamily:    configuration.FontFamily,
-		BarcodeWidth:  configuration.BarcodeWidth,
-		BarcodeHeight: configuration.BarcodeHeight,
+	generationOptions, ok := determineGenerationOptions(context, settings, payload)
+	if !ok {
+		return nil, false
 	}
-	builder, err := pdf.GeneratePdf(&pdfConfiguration, settings, labelData)
+	generationOptions = append(generationOptions, layoutOptions...)
+
+	builder, err := pdf.GeneratePdf(createPdfConfiguration(configuration), settings, labelData, generationOptions...)
 	if err != nil {
-		slog.Error("Failed to generate PDF", "error", err)
+		logging.FromContext(context).Error("Failed to generate PDF", "error", err)
 		failure_response.InvalidRequest(context, "Failed to generate PDF: "+err.Error())
-		return
+		return nil, false
 	}
 
 	buffer, err := builder.WriteToBuffer()
 	if err != nil {
-		slog.Error("Failed to write PDF to buffer", "error", err)
+		logging.FromContext(context).Error("Failed to write PDF to buffer", "error", err)
 		failure_response.InvalidRequest(context, "Failed to write PDF to buffer: "+err.Error())
-		return
+		return nil, false
 	}
 
-	if err := queries.UpdateFreezeStatusOfItems(db, payload.ItemIds, true); err != nil {
-		slog.Error("Failed to freeze items", "error", err)
-		failure_response.Unknown(context, "Failed to freeze items: "+err.Error())
-		return
+	return buffer, true
+}
+
+// generateZplLabels renders each label separately for thermal printers.
+// The layout is optional and only determines the template and barcode symbology, as there are no sheets.
+// For the same reason, start cells, skipped cells and grids are rejected.
+// If this fails, a failure response is written and false is returned.
+func generateZplLabels(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, payload *GenerateLabelsPayload, labelData []*pdf.LabelData) (*bytes.Buffer, bool) {
+	if payload.StartCell != nil || len(payload.SkippedCells) > 0 || payload.ShowGrid {
+		failure_response.InvalidRequest(context, "Start cell, skipped cells and grid only apply to PDF labels")
+		return nil, false
 	}
 
-	context.Header("Content-Disposition", "attachment; filename=labels.pdf")
-	context.DataFromReader(
-		http.StatusOK,
-		int64(buffer.Len()),
-		"application/pdf",
-		buffer,
-		map[string]string{"Content-Disposition": "attachment; filename=labels.pdf"},
-	)
+	var layout *models.LabelLayout
+	if payload.Layout != nil || payload.LayoutId != nil {
+		determinedLayout, ok := determineLayout(context, db, payload.Layout, payload.LayoutId)
+		if !ok {
+			return nil, false
+		}
+		layout = determinedLayout
+	}
+
+	template, symbology, ok := determineLabelContents(context, configuration, layout)
+	if !ok {
+		return nil, false
+	}
+
+	options := []zpl.GenerationOption{zpl.UsingTemplate(template), zpl.UsingSymbology(symbology)}
+	if payload.Preview {
+		options = append(options, zpl.MarkedAsPreview())
+	}
+
+	buffer, err := zpl.GenerateZpl(createZplConfiguration(configuration), labelData, options...)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to generate ZPL", "error", err)
+		failure_response.InvalidRequest(context, "Failed to generate ZPL: "+err.Error())
+		return nil, false
+	}
+
+	return buffer, true
 }
 
-func collectLabelData(db *sql.DB, itemTable map[models.Id]*models.Item, itemIds []models.Id) ([]*pdf.LabelData, error) {
-	createLabelData := func(itemId models.Id) (*pdf.LabelData, error) {
-		item, ok := itemTable[itemId]
-		if !ok {
-			return nil, fmt.Errorf("bug: item with id %d not found; should never occur: this error should have be caught earlier", itemId)
+func createPdfConfiguration(configuration *configuration.Configuration) *pdf.Configuration {
+	return &pdf.Configuration{
+		FontDirectory:      configuration.FontDirectory,
+		FontFilename:       configuration.FontFilename,
+		FontFamily:         configuration.FontFamily,
+		MinimumFontSize:    configuration.MinimumFontSize,
+		BarcodeRenderer:    configuration.BarcodeRenderer,
+		BarcodeSymbology:   configuration.BarcodeSymbology,
+		BarcodeModuleWidth: configuration.BarcodeModuleWidth,
+		BarcodeBarHeight:   configuration.BarcodeBarHeight,
+		BarcodeShowText:    configuration.BarcodeShowText,
+		BarcodeWidth:       configuration.BarcodeWidth,
+		BarcodeHeight:      configuration.BarcodeHeight,
+	}
+}
+
+func createZplConfiguration(configuration *configuration.Configuration) *zpl.Configuration {
+	return &zpl.Configuration{
+		LabelWidth:         configuration.ZplLabelWidth,
+		LabelHeight:        configuration.ZplLabelHeight,
+		LabelPadding:       configuration.ZplLabelPadding,
+		Dpi:                configuration.ZplDpi,
+		FontSize:           configuration.ZplFontSize,
+		BarcodeSymbology:   configuration.BarcodeSymbology,
+		BarcodeModuleWidth: configuration.BarcodeModuleWidth,
+		BarcodeBarHeight:   configuration.BarcodeBarHeight,
+		BarcodeShowText:    configuration.BarcodeShowText,
+	}
+}
+
+// collectLabelData fetches the items and checks that the user is allowed to generate labels for all of them.
+// If this fails, a failure response is written and false is returned.
+func collectLabelData(context *gin.Context, db *sql.DB, userId models.Id, roleId models.RoleId, itemIds []models.Id) (map[models.Id]*models.Item, []*pdf.LabelData, bool) {
+	itemTable, err := queries.GetItemsWithIds(db, itemIds)
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchItem) {
+			failure_response.UnknownItem(context, err.Error())
+			return nil, nil, false
+		}
+
+		failure_response.UnknownError(context, fmt.Errorf("Failed to fetch items: %w", err))
+		return nil, nil, false
+	}
+
+	for _, item := range itemTable {
+		if !authorization.IsAllowedOn(authorization.GenerateLabels, userId, roleId, item.SellerID) {
+			logging.FromContext(context).Error("Labels requested for items not owned by the seller", "itemId", item.ItemID, "userId", userId)
+			failure_response.WrongSeller(context, "labels can only be generated by the owning seller")
+			return nil, nil, false
 		}
+	}
+
+	labelData, err := labels.CollectLabelData(db, itemTable, itemIds)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to collect label data", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to collect label data: %w", err))
+		return nil, nil, false
+	}
+
+	return itemTable, labelData, true
+}
+
+// determineLayoutSettings looks up the layout preset or validates the inline layout, exactly one of which must be given,
+// and returns the generation options selecting its label template and barcode symbology.
+// If this fails, a failure response is written and false is returned.
+func determineLayoutSettings(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, inlineLayout *Layout, layoutId *models.Id) (*pdf.LayoutSettings, []pdf.GenerationOption, bool) {
+	layout, ok := determineLayout(context, db, inlineLayout, layoutId)
+	if !ok {
+		return nil, nil, false
+	}
+
+	settings, err := labels.LayoutSettings(layout)
+	if err != nil {
+		failure_response.InvalidLayout(context, "Failed to parse layout: "+err.Error())
+		return nil, nil, false
+	}
+
+	template, symbology, ok := determineLabelContents(context, configuration, layout)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return settings, []pdf.GenerationOption{pdf.UsingTemplate(template), pdf.UsingSymbology(symbology)}, true
+}
+
+// determineLayout looks up the layout preset or converts the inline layout, exactly one of which must be given.
+// If this fails, a failure response is written and false is returned.
+func determineLayout(context *gin.Context, db *sql.DB, inlineLayout *Layout, layoutId *models.Id) (*models.LabelLayout, bool) {
+	switch {
+	case inlineLayout != nil && layoutId != nil:
+		failure_response.InvalidLayout(context, "Layout and layout id cannot be given at the same time")
+		return nil, false
 
-		categoryNameTable, err := queries.GetCategoryNameTable(db)
+	case inlineLayout != nil:
+		return inlineLayout.toLabelLayout(""), true
+
+	case layoutId != nil:
+		layout, err := queries.GetLabelLayoutWithId(db, *layoutId)
 		if err != nil {
-			return nil, err
+			if errors.Is(err, dberr.ErrNoSuchLabelLayout) {
+				failure_response.UnknownLabelLayout(context, err.Error())
+				return nil, false
+			}
+
+			failure_response.UnknownError(context, fmt.Errorf("Failed to fetch label layout: %w", err))
+			return nil, false
 		}
+		return layout, true
 
-		return createLabelDataFromItem(categoryNameTable, item)
+	default:
+		failure_response.InvalidLayout(context, "Either a layout or a layout id must be given")
+		return nil, false
 	}
+}
 
-	labelData, err := algorithms.MapError(itemIds, createLabelData)
+// determineLabelContents returns the label template and barcode symbology of the layout.
+// Without a layout, the default template and the configured symbology are used.
+// If this fails, a failure response is written and false is returned.
+func determineLabelContents(context *gin.Context, configuration *configuration.Configuration, layout *models.LabelLayout) (*pdf.LabelTemplate, barcode.Symbology, bool) {
+	if layout == nil {
+		layout = &models.LabelLayout{}
+	}
+
+	template, err := labels.FindTemplate(configuration.LabelTemplates, layout.Template)
+	if err != nil {
+		failure_response.UnknownLabelTemplate(context, err.Error())
+		return nil, "", false
+	}
+
+	symbology, err := labels.LayoutSymbology(layout)
 	if err != nil {
-		return nil, err
+		failure_response.InvalidLayout(context, err.Error())
+		return nil, "", false
 	}
 
-	return labelData, nil
+	return template, symbology, true
 }
 
-func createLabelDataFromItem(categoryNameTable map[models.Id]string, item *models.Item) (*pdf.LabelData, error) {
-	barcode := fmt.Sprintf("%dx", item.ItemID)
+// determineGenerationOptions translates the payload to generation options and checks that the start and skipped cells lie on the sheet
+// and leave at least one cell of the first sheet available.
+// If not, a failure response is written and false is returned.
+func determineGenerationOptions(context *gin.Context, layout *pdf.LayoutSettings, payload *GenerateLabelsPayload) ([]pdf.GenerationOption, bool) {
+	options := []pdf.GenerationOption{}
 
-	category, ok := categoryNameTable[item.CategoryID]
-	if !ok {
-		return nil, fmt.Errorf("unknown category id: %v", item.CategoryID)
+	if payload.Preview {
+		options = append(options, pdf.WithWatermark(pdf.PreviewWatermark))
+	}
+
+	if payload.ShowGrid {
+		options = append(options, pdf.ShowingGrid())
+	}
+
+	startCell := pdf.Cell{}
+	if payload.StartCell != nil {
+		startCell = pdf.Cell{Column: payload.StartCell.Column, Row: payload.StartCell.Row}
+		if !layout.ContainsCell(startCell) {
+			failure_response.InvalidCell(context, fmt.Sprintf("Start cell %s lies outside of the sheet", startCell))
+			return nil, false
+		}
+
+		options = append(options, pdf.StartingAt(startCell))
+	}
+
+	skippedCells := []pdf.Cell{}
+	for _, cell := range payload.SkippedCells {
+		skippedCell := pdf.Cell{Column: cell.Column, Row: cell.Row}
+		if !layout.ContainsCell(skippedCell) {
+			failure_response.InvalidCell(context, fmt.Sprintf("Skipped cell %s lies outside of the sheet", skippedCell))
+			return nil, false
+		}
+
+		skippedCells = append(skippedCells, skippedCell)
+		options = append(options, pdf.SkippingCells(skippedCell))
 	}
 
-	labelData := &pdf.LabelData{
-		BarcodeData:      barcode,
-		Description:      item.Description,
-		Category:         category,
-		ItemIdentifier:   int(item.ItemID),
-		PriceInCents:     int(item.PriceInCents),
-		SellerIdentifier: int(item.SellerID),
-		Charity:          item.Charity,
-		Donation:         item.Donation,
+	if layout.IsFirstSheetFull(startCell, skippedCells) {
+		failure_response.InvalidCell(context, "No cell of the first sheet is available")
+		return nil, false
 	}
 
-	return labelData, nil
+	return options, true
 }
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"bctbackend/database"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthSuccessResponse struct {
+	Status string `json:"status"`
+}
+
+// @Summary Check whether the server is running.
+// @Description Always succeeds while the process is able to handle requests.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthSuccessResponse "Server is running"
+// @Router /healthz [get]
+func Healthz(context *gin.Context, configuration *configuration.Configuration, db *sql.DB) {
+	context.JSON(http.StatusOK, HealthSuccessResponse{Status: "ok"})
+}
+
+// @Summary Check whether the server is ready to handle requests.
+// @Description Checks that the database is reachable, has the expected schema version and all its tables,
+// @Description and that the font and HTML files are present.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthSuccessResponse "Server is ready"
+// @Failure 503 {object} failure_response.FailureResponse "Server is not ready"
+// @Router /readyz [get]
+func Readyz(context *gin.Context, configuration *configuration.Configuration, db *sql.DB) {
+	if err := db.PingContext(context.Request.Context()); err != nil {
+		failure_response.NotReady(context, fmt.Sprintf("database is unreachable: %s", err.Error()))
+		return
+	}
+
+	if err := database.CheckSchema(db); err != nil {
+		failure_response.NotReady(context, fmt.Sprintf("database schema is not usable: %s", err.Error()))
+		return
+	}
+
+	if err := configuration.CheckRequiredFiles(); err != nil {
+		failure_response.NotReady(context, err.Error())
+		return
+	}
+
+	context.JSON(http.StatusOK, HealthSuccessResponse{Status: "ready"})
+}
@@ -4,6 +4,7 @@ import (
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
 	rest "bctbackend/server/shared"
@@ -57,11 +58,11 @@ func GetItemInformation(context *gin.Context, configuration *configuration.Confi
 			return
 		}
 
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
-	if item.SellerID != userId && roleId.IsSeller() {
+	if !authorization.IsAllowedOn(authorization.ViewItem, userId, roleId, item.SellerID) {
 		failure_response.WrongSeller(context, "Only the owning seller can access this item")
 		return
 	}
@@ -73,7 +74,7 @@ func GetItemInformation(context *gin.Context, configuration *configuration.Confi
 			return
 		}
 
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
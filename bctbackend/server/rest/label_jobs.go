@@ -0,0 +1,437 @@
+package rest
+
+import (
+	"archive/zip"
+	"bctbackend/algorithms"
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/labels"
+	"bctbackend/pdf"
+	"bctbackend/server/configuration"
+	"bctbackend/server/events"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/jobs"
+	"bctbackend/server/logging"
+	rest "bctbackend/server/shared"
+	"bytes"
+	"cmp"
+	gocontext "context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ways in which the labels generated by a label job can be delivered
+const (
+	// A single PDF in which the labels of each seller are preceded by a separator page
+	CombinedLabelJobOutput = "combined"
+
+	// A ZIP archive containing one PDF per seller
+	PerSellerLabelJobOutput = "perSeller"
+)
+
+type AddLabelJobPayload struct {
+	// Exactly one of Layout and LayoutId must be given
+	Layout   *Layout    `json:"layout,omitempty"`
+	LayoutId *models.Id `json:"layoutId,omitempty"`
+
+	// Items to generate labels for; if omitted, all visible items are considered.
+	// Either way, only the items that pass all of the filters below are selected.
+	ItemIds []models.Id `json:"itemIds,omitempty"`
+
+	// Only selects items of sellers in this zone
+	Zone *int `json:"zone,omitempty"`
+
+	// Only selects items of sellers whose id lies within this range, bounds included
+	FirstSellerId *models.Id `json:"firstSellerId,omitempty"`
+	LastSellerId  *models.Id `json:"lastSellerId,omitempty"`
+
+	// Skips items that are already frozen, i.e., whose labels have presumably been printed before
+	UnfrozenOnly bool `json:"unfrozenOnly,omitempty"`
+
+	// Either "combined" or "perSeller"; defaults to "combined"
+	Output string `json:"output,omitempty"`
+
+	// Freezes the selected items once their labels have been generated
+	Freeze bool `json:"freeze,omitempty"`
+}
+
+type LabelJobData struct {
+	JobId  models.Id `json:"jobId"`
+	Status string    `json:"status"`
+
+	// Number of labels generated so far out of the total number of labels
+	Done  int `json:"done"`
+	Total int `json:"total"`
+
+	// Reason why the job failed; empty unless it did
+	Error string `json:"error,omitempty"`
+
+	CreatedAt  rest.DateTime  `json:"createdAt"`
+	FinishedAt *rest.DateTime `json:"finishedAt,omitempty"`
+}
+
+type GetLabelJobsSuccessResponse struct {
+	Jobs []*LabelJobData `json:"jobs"`
+}
+
+// @Summary Start generating labels in the background.
+// @Description Generates labels for items of any number of sellers, selected by item ids, zone, seller range and frozen status.
+// @Description The labels are ordered by seller and delivered either as a single PDF with a separator page per seller
+// @Description or as a ZIP archive with one PDF per seller.
+// @Description Progress is published as job.updated events; the result can be downloaded once the job has succeeded.
+// @Description Only accessible to admins.
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param payload body AddLabelJobPayload true "Item selection, layout and output"
+// @Success 202 {object} LabelJobData "Job started"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins, invalid layout or no items selected"
+// @Failure 404 {object} failure_response.FailureResponse "Item or layout does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /labels/jobs [post]
+func AddLabelJob(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var payload AddLabelJobPayload
+	if err := context.ShouldBindJSON(&payload); err != nil {
+		logging.FromContext(context).Error("Failed to parse payload for AddLabelJob endpoint", "error", err)
+		failure_response.InvalidRequest(context, "Failed to parse payload:"+err.Error())
+		return
+	}
+
+	if payload.Output == "" {
+		payload.Output = CombinedLabelJobOutput
+	}
+	if payload.Output != CombinedLabelJobOutput && payload.Output != PerSellerLabelJobOutput {
+		failure_response.InvalidRequest(context, fmt.Sprintf("Unknown output %q; expected %q or %q", payload.Output, CombinedLabelJobOutput, PerSellerLabelJobOutput))
+		return
+	}
+
+	if payload.FirstSellerId != nil && payload.LastSellerId != nil && *payload.FirstSellerId > *payload.LastSellerId {
+		failure_response.InvalidRequest(context, "First seller id must not exceed last seller id")
+		return
+	}
+
+	settings, layoutOptions, ok := determineLayoutSettings(context, configuration, db, payload.Layout, payload.LayoutId)
+	if !ok {
+		return
+	}
+
+	items, ok := selectLabelJobItems(context, db, &payload)
+	if !ok {
+		return
+	}
+
+	if len(items) == 0 {
+		failure_response.MissingItems(context, "No items match the selection")
+		return
+	}
+
+	itemIds := models.CollectItemIds(items)
+	itemTable := make(map[models.Id]*models.Item, len(items))
+	for _, item := range items {
+		itemTable[item.ItemID] = item
+	}
+
+	labelData, err := labels.CollectLabelData(db, itemTable, itemIds)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to collect label data", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to collect label data: %w", err))
+		return
+	}
+
+	pdfConfiguration := createPdfConfiguration(configuration)
+	output := payload.Output
+	freeze := payload.Freeze
+
+	snapshot := jobs.FromContext(context).Start(userId, func(ctx gocontext.Context, tracker *jobs.Tracker) (*jobs.Result, error) {
+		total := len(labelData)
+		tracker.SetProgress(0, total)
+
+		var result *jobs.Result
+		var err error
+		if output == PerSellerLabelJobOutput {
+			result, err = generatePerSellerLabels(ctx, pdfConfiguration, settings, labelData, layoutOptions, tracker)
+		} else {
+			result, err = generateCombinedLabels(ctx, pdfConfiguration, settings, labelData, layoutOptions, tracker)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Items must not be frozen for labels that will never be downloaded
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if freeze {
+			if err := queries.UpdateFreezeStatusOfItems(db, itemIds, true); err != nil {
+				return nil, fmt.Errorf("failed to freeze items: %w", err)
+			}
+
+			tracker.Publish(events.NewItemsFrozen(items))
+		}
+
+		return result, nil
+	})
+
+	logging.FromContext(context).Info("Label job started", slog.Int64("job_id", snapshot.JobId.Int64()), slog.Int("label_count", len(labelData)))
+	context.JSON(http.StatusAccepted, convertSnapshotToLabelJobData(snapshot))
+}
+
+// selectLabelJobItems fetches the items selected by the payload, ordered by seller and then by id.
+// If this fails, a failure response is written and false is returned.
+func selectLabelJobItems(context *gin.Context, db *sql.DB, payload *AddLabelJobPayload) ([]*models.Item, bool) {
+	items := []*models.Item{}
+	addIfSelected := func(item *models.Item) error {
+		if payload.selects(item) {
+			items = append(items, item)
+		}
+		return nil
+	}
+
+	if payload.ItemIds != nil {
+		itemTable, err := queries.GetItemsWithIds(db, payload.ItemIds)
+		if err != nil {
+			if errors.Is(err, dberr.ErrNoSuchItem) {
+				failure_response.UnknownItem(context, err.Error())
+				return nil, false
+			}
+
+			failure_response.UnknownError(context, fmt.Errorf("Failed to fetch items: %w", err))
+			return nil, false
+		}
+
+		for _, item := range itemTable {
+			addIfSelected(item)
+		}
+	} else {
+		if err := queries.GetItems(db, addIfSelected, queries.OnlyVisibleItems, queries.AllRows()); err != nil {
+			failure_response.UnknownError(context, fmt.Errorf("Failed to fetch items: %w", err))
+			return nil, false
+		}
+	}
+
+	slices.SortFunc(items, func(first *models.Item, second *models.Item) int {
+		return cmp.Or(cmp.Compare(first.SellerID, second.SellerID), cmp.Compare(first.ItemID, second.ItemID))
+	})
+
+	return items, true
+}
+
+// selects checks whether the item passes all filters of the payload.
+func (payload *AddLabelJobPayload) selects(item *models.Item) bool {
+	if payload.Zone != nil && labels.SellerZone(item.SellerID) != *payload.Zone {
+		return false
+	}
+
+	if payload.FirstSellerId != nil && item.SellerID < *payload.FirstSellerId {
+		return false
+	}
+
+	if payload.LastSellerId != nil && item.SellerID > *payload.LastSellerId {
+		return false
+	}
+
+	return !payload.UnfrozenOnly || !item.Frozen
+}
+
+// generateCombinedLabels puts the labels of all sellers in a single PDF, each seller starting with a separator page.
+func generateCombinedLabels(ctx gocontext.Context, configuration *pdf.Configuration, settings *pdf.LayoutSettings, labelData []*pdf.LabelData, layoutOptions []pdf.GenerationOption, tracker *jobs.Tracker) (*jobs.Result, error) {
+	options := append(slices.Clone(layoutOptions),
+		pdf.SeparatingSellers(),
+		pdf.ReportingProgress(func(labelCount int) error {
+			tracker.SetProgress(labelCount, len(labelData))
+			return ctx.Err()
+		}),
+	)
+
+	buffer, err := generatePdfBuffer(configuration, settings, labelData, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jobs.Result{Data: buffer.Bytes(), ContentType: "application/pdf", Filename: "labels.pdf"}, nil
+}
+
+// generatePerSellerLabels creates a separate PDF for each seller and bundles them in a ZIP archive.
+// The labels must be ordered by seller.
+func generatePerSellerLabels(ctx gocontext.Context, configuration *pdf.Configuration, settings *pdf.LayoutSettings, labelData []*pdf.LabelData, layoutOptions []pdf.GenerationOption, tracker *jobs.Tracker) (*jobs.Result, error) {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+
+	for start := 0; start < len(labelData); {
+		end := start
+		for end < len(labelData) && labelData[end].SellerIdentifier == labelData[start].SellerIdentifier {
+			end++
+		}
+
+		labelsDone := start
+		options := append(slices.Clone(layoutOptions),
+			pdf.ReportingProgress(func(labelCount int) error {
+				tracker.SetProgress(labelsDone+labelCount, len(labelData))
+				return ctx.Err()
+			}),
+		)
+
+		buffer, err := generatePdfBuffer(configuration, settings, labelData[start:end], options)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := writer.Create(fmt.Sprintf("seller-%d.pdf", labelData[start].SellerIdentifier))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add PDF to archive: %w", err)
+		}
+		if _, err := file.Write(buffer.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to add PDF to archive: %w", err)
+		}
+
+		start = end
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return &jobs.Result{Data: archive.Bytes(), ContentType: "application/zip", Filename: "labels.zip"}, nil
+}
+
+func generatePdfBuffer(configuration *pdf.Configuration, settings *pdf.LayoutSettings, labelData []*pdf.LabelData, options []pdf.GenerationOption) (*bytes.Buffer, error) {
+	builder, err := pdf.GeneratePdf(configuration, settings, labelData, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	buffer, err := builder.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF to buffer: %w", err)
+	}
+
+	return buffer, nil
+}
+
+// @Summary Get list of label jobs.
+// @Description Returns all label jobs that are running or finished less than an hour ago. Only accessible to admins.
+// @Tags labels
+// @Produce json
+// @Success 200 {object} GetLabelJobsSuccessResponse "Jobs successfully fetched"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Router /labels/jobs [get]
+func GetLabelJobs(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	response := GetLabelJobsSuccessResponse{
+		Jobs: algorithms.Map(jobs.FromContext(context).List(), convertSnapshotToLabelJobData),
+	}
+
+	context.JSON(http.StatusOK, response)
+}
+
+// @Summary Get the progress of a label job.
+// @Description Returns the state of a label job. Only accessible to admins.
+// @Tags labels
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} LabelJobData "Job successfully fetched"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Failure 404 {object} failure_response.FailureResponse "Job does not exist"
+// @Router /labels/jobs/{id} [get]
+func GetLabelJob(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	snapshot, _, ok := findLabelJob(context)
+	if !ok {
+		return
+	}
+
+	context.JSON(http.StatusOK, convertSnapshotToLabelJobData(snapshot))
+}
+
+// @Summary Download the labels generated by a label job.
+// @Description Returns the PDF or ZIP archive produced by a label job that has succeeded. Only accessible to admins.
+// @Tags labels
+// @Produce application/pdf
+// @Produce application/zip
+// @Param id path int true "Job ID"
+// @Success 200 {file} file "Labels"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI, or job has not succeeded"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Failure 404 {object} failure_response.FailureResponse "Job does not exist"
+// @Router /labels/jobs/{id}/result [get]
+func GetLabelJobResult(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	snapshot, result, ok := findLabelJob(context)
+	if !ok {
+		return
+	}
+
+	switch snapshot.Status {
+	case jobs.Running:
+		failure_response.LabelJobNotFinished(context, fmt.Sprintf("Label job %d is still running", snapshot.JobId))
+		return
+	case jobs.Failed:
+		failure_response.LabelJobFailed(context, fmt.Sprintf("Label job %d failed: %s", snapshot.JobId, snapshot.Error))
+		return
+	}
+
+	context.DataFromReader(
+		http.StatusOK,
+		int64(len(result.Data)),
+		result.ContentType,
+		bytes.NewReader(result.Data),
+		map[string]string{"Content-Disposition": "attachment; filename=" + result.Filename},
+	)
+}
+
+// findLabelJob looks up the job identified in the URI.
+// If this fails, a failure response is written and false is returned.
+func findLabelJob(context *gin.Context) (jobs.Snapshot, *jobs.Result, bool) {
+	var uriParameters struct {
+		JobId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return jobs.Snapshot{}, nil, false
+	}
+
+	jobId, err := models.ParseId(uriParameters.JobId)
+	if err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return jobs.Snapshot{}, nil, false
+	}
+
+	snapshot, result, ok := jobs.FromContext(context).Get(jobId)
+	if !ok {
+		failure_response.UnknownLabelJob(context, fmt.Sprintf("No label job with id %d", jobId))
+		return jobs.Snapshot{}, nil, false
+	}
+
+	return snapshot, result, true
+}
+
+func convertSnapshotToLabelJobData(snapshot jobs.Snapshot) *LabelJobData {
+	data := &LabelJobData{
+		JobId:     snapshot.JobId,
+		Status:    string(snapshot.Status),
+		Done:      snapshot.Done,
+		Total:     snapshot.Total,
+		Error:     snapshot.Error,
+		CreatedAt: rest.ConvertTimestampToDateTime(snapshot.CreatedAt),
+	}
+
+	if snapshot.Status != jobs.Running {
+		finishedAt := rest.ConvertTimestampToDateTime(snapshot.FinishedAt)
+		data.FinishedAt = &finishedAt
+	}
+
+	return data
+}
@@ -7,10 +7,11 @@ import (
 	"bctbackend/database/queries"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	rest "bctbackend/server/shared"
 	"bytes"
 	"database/sql"
-	"log/slog"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -47,11 +48,6 @@ type GetItemsSuccessResponse struct {
 // @Failure 500 {object} failure_response.FailureResponse "Failed to fetch items"
 // @Router /items [get]
 func GetAllItems(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if roleId != models.NewAdminRoleId() {
-		failure_response.WrongRole(context, "Only admins can list all items")
-		return
-	}
-
 	var itemSelection queries.ItemSelection
 	switch context.Query("items") {
 	case "all":
@@ -68,7 +64,7 @@ func GetAllItems(context *gin.Context, configuration *configuration.Configuratio
 		parsedLimit, err := strconv.Atoi(limitString)
 
 		if err != nil {
-			slog.Error("Failed to parse limit", "error", err)
+			logging.FromContext(context).Error("Failed to parse limit", "error", err)
 			failure_response.BadRequest(context, "invalid_uri_parameters", "Failed to parse limit: "+err.Error())
 			return
 		}
@@ -84,7 +80,7 @@ func GetAllItems(context *gin.Context, configuration *configuration.Configuratio
 		parsedOffset, err := strconv.Atoi(offsetString)
 
 		if err != nil {
-			slog.Error("Failed to parse offset", "error", err)
+			logging.FromContext(context).Error("Failed to parse offset", "error", err)
 			failure_response.BadRequest(context, "invalid_uri_parameters", "Failed to parse offset: "+err.Error())
 			return
 		}
@@ -98,8 +94,8 @@ func GetAllItems(context *gin.Context, configuration *configuration.Configuratio
 
 	items := []*models.Item{}
 	if err := queries.GetItems(db, queries.CollectTo(&items), itemSelection, rowSelection); err != nil {
-		slog.Error("Failed to get items", "error", err)
-		failure_response.Unknown(context, "Failed to get items: "+err.Error())
+		logging.FromContext(context).Error("Failed to get items", "error", err)
+		failure_response.UnknownError(context, fmt.Errorf("Failed to get items: %w", err))
 		return
 	}
 
@@ -120,8 +116,8 @@ func GetAllItems(context *gin.Context, configuration *configuration.Configuratio
 		})
 		itemCount, err := queries.CountItems(db, itemSelection)
 		if err != nil {
-			slog.Error("Failed to count items", "error", err)
-			failure_response.Unknown(context, "Failed to count items: "+err.Error())
+			logging.FromContext(context).Error("Failed to count items", "error", err)
+			failure_response.UnknownError(context, fmt.Errorf("Failed to count items: %w", err))
 			return
 		}
 
@@ -145,7 +141,7 @@ func GetAllItems(context *gin.Context, configuration *configuration.Configuratio
 	case "csv":
 		categoryNameTable, err := queries.GetCategoryNameTable(db)
 		if err != nil {
-			failure_response.Unknown(context, "Failed to get category map: "+err.Error())
+			failure_response.UnknownError(context, fmt.Errorf("Failed to get category map: %w", err))
 			return
 		}
 
@@ -156,7 +152,7 @@ func GetAllItems(context *gin.Context, configuration *configuration.Configuratio
 
 		buffer := new(bytes.Buffer)
 		if err := csv.FormatItemsAsCSV(items, categoryNameTable, buffer); err != nil {
-			failure_response.Unknown(context, "Failed to format items as CSV: "+err.Error())
+			failure_response.UnknownError(context, fmt.Errorf("Failed to format items as CSV: %w", err))
 			return
 		}
 		string := buffer.String()
@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	rest "bctbackend/server/shared"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetApiTokensTokenData struct {
+	TokenId        models.Id      `json:"tokenId"`
+	UserId         models.Id      `json:"userId"`
+	Description    string         `json:"description"`
+	Actions        []string       `json:"actions"`
+	CreatedAt      rest.DateTime  `json:"createdAt"`
+	ExpirationTime *rest.DateTime `json:"expirationTime,omitempty"`
+}
+
+type GetApiTokensSuccessResponse struct {
+	Tokens []*GetApiTokensTokenData `json:"tokens"`
+}
+
+// @Summary Get list of API tokens.
+// @Description Returns all API tokens, without the tokens themselves. Only accessible to admins.
+// @Tags tokens
+// @Produce json
+// @Success 200 {object} GetApiTokensSuccessResponse "Tokens successfully fetched"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /tokens [get]
+func GetApiTokens(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	tokens, err := queries.GetApiTokens(db)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to fetch API tokens", slog.String("error", err.Error()))
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	response := GetApiTokensSuccessResponse{
+		Tokens: algorithms.Map(tokens, func(token *models.ApiToken) *GetApiTokensTokenData {
+			return &GetApiTokensTokenData{
+				TokenId:        token.TokenId,
+				UserId:         token.UserId,
+				Description:    token.Description,
+				Actions:        token.Actions,
+				CreatedAt:      rest.ConvertTimestampToDateTime(token.CreatedAt),
+				ExpirationTime: algorithms.MapOptional(token.ExpirationTime, rest.ConvertTimestampToDateTime),
+			}
+		}),
+	}
+
+	context.JSON(http.StatusOK, response)
+}
@@ -2,14 +2,14 @@ package rest
 
 import (
 	"bctbackend/algorithms"
-	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
 	rest "bctbackend/server/shared"
 	"database/sql"
-	"errors"
+	"fmt"
 	"net/http"
 
 	_ "bctbackend/docs"
@@ -54,7 +54,7 @@ func (ep *getCashierSalesEndpoint) Execute() {
 
 	var saleSummaries []*models.SaleSummary
 	if err := queries.GetCashierSales(ep.db, uriCashierId, queries.CollectTo(&saleSummaries)); err != nil {
-		failure_response.Unknown(ep.context, "Could not retrieve cashier sales: "+err.Error())
+		failure_response.UnknownError(ep.context, fmt.Errorf("Could not retrieve cashier sales: %w", err))
 		return
 	}
 
@@ -103,32 +103,10 @@ func (endpoint *getCashierSalesEndpoint) extractCashierIdFromUri() (models.Id, b
 }
 
 func (endpoint *getCashierSalesEndpoint) ensureUserHasPermission(queriedUser models.Id) bool {
-	user, err := queries.GetUserWithId(endpoint.db, endpoint.userId)
-	if err != nil {
-		if errors.Is(err, dberr.ErrNoSuchUser) {
-			// This should not happen, as the userId is from the logged-in user
-			failure_response.Unknown(endpoint.context, "Bug: logged in user does not exist")
-			return false
-		}
-		failure_response.Unknown(endpoint.context, "Could not retrieve logged in user: "+err.Error())
+	if !authorization.IsAllowedOn(authorization.ListCashierSales, endpoint.userId, endpoint.roleId, queriedUser) {
+		failure_response.Forbidden(endpoint.context, "wrong_role", "Only accessible to owning cashiers or admins")
 		return false
 	}
 
-	if user.RoleId.IsAdmin() {
-		return true
-	}
-
-	if user.RoleId.IsCashier() {
-		loggedInUser := endpoint.userId
-
-		if loggedInUser != queriedUser {
-			failure_response.Forbidden(endpoint.context, "wrong_role", "Only accessible to owning cashiers or admins")
-			return false
-		}
-
-		return true
-	}
-
-	failure_response.Forbidden(endpoint.context, "wrong_role", "Only accessible to owning cashiers or admins")
-	return false
+	return true
 }
@@ -3,11 +3,12 @@ package rest
 import (
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	"database/sql"
 	"fmt"
-	"log/slog"
 	"maps"
 	"net/http"
 	"slices"
@@ -53,27 +54,27 @@ func ListCategories(context *gin.Context, configuration *configuration.Configura
 		return
 
 	default:
-		listCategoriesWithoutCounts(context, db, userId, roleId)
+		listCategoriesWithoutCounts(context, db)
 		return
 	}
 }
 
 func listCategoriesWithCounts(context *gin.Context, db *sql.DB, userId models.Id, roleId models.RoleId, itemSelection queries.ItemSelection) {
-	if !roleId.IsAdmin() {
-		slog.Error("Unauthorized access to category counts", "userId", userId, "roleId", roleId)
+	if !authorization.IsAllowed(authorization.ListCategoryCounts, roleId) {
+		logging.FromContext(context).Error("Unauthorized access to category counts", "userId", userId, "roleId", roleId)
 		failure_response.WrongRole(context, "Only admins can access category counts")
 		return
 	}
 
 	categoryCounts, err := queries.GetCategoryCounts(db, itemSelection)
 	if err != nil {
-		failure_response.Unknown(context, "Failed to fetch category counts: "+err.Error())
+		failure_response.UnknownError(context, fmt.Errorf("Failed to fetch category counts: %w", err))
 		return
 	}
 
 	categoryNameTable, err := queries.GetCategoryNameTable(db)
 	if err != nil {
-		failure_response.Unknown(context, "Failed to fetch category table: "+err.Error())
+		failure_response.UnknownError(context, fmt.Errorf("Failed to fetch category table: %w", err))
 		return
 	}
 
@@ -104,16 +105,10 @@ func listCategoriesWithCounts(context *gin.Context, db *sql.DB, userId models.Id
 	context.IndentedJSON(http.StatusOK, response)
 }
 
-func listCategoriesWithoutCounts(context *gin.Context, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if !roleId.IsAdmin() && !roleId.IsSeller() {
-		slog.Error("Unauthorized access to category counts", "userId", userId, "roleId", roleId)
-		failure_response.WrongRole(context, "Only admins and sellers can access category names")
-		return
-	}
-
+func listCategoriesWithoutCounts(context *gin.Context, db *sql.DB) {
 	categories, err := queries.GetCategories(db)
 	if err != nil {
-		failure_response.Unknown(context, "Failed to fetch categories: "+err.Error())
+		failure_response.UnknownError(context, fmt.Errorf("Failed to fetch categories: %w", err))
 		return
 	}
 
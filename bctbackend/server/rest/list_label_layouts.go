@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabelLayoutData struct {
+	LayoutId models.Id `json:"layoutId"`
+	Name     string    `json:"name"`
+	Layout   Layout    `json:"layout"`
+}
+
+type GetLabelLayoutsSuccessResponse struct {
+	Layouts []*LabelLayoutData `json:"layouts"`
+}
+
+func convertLabelLayoutToData(layout *models.LabelLayout) *LabelLayoutData {
+	return &LabelLayoutData{
+		LayoutId: layout.LayoutId,
+		Name:     layout.Name,
+		Layout:   convertLabelLayoutToLayout(layout),
+	}
+}
+
+// @Summary Get list of label layout presets.
+// @Description Returns all label layout presets ordered by name.
+// @Description Their ids can be used instead of an inline layout when generating labels.
+// @Tags labels
+// @Produce json
+// @Success 200 {object} GetLabelLayoutsSuccessResponse "Layouts successfully fetched"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins and sellers"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /label-layouts [get]
+func GetLabelLayouts(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	layouts, err := queries.GetLabelLayouts(db)
+	if err != nil {
+		logging.FromContext(context).Error("Failed to fetch label layouts", slog.String("error", err.Error()))
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	response := GetLabelLayoutsSuccessResponse{
+		Layouts: algorithms.Map(layouts, convertLabelLayoutToData),
+	}
+
+	context.JSON(http.StatusOK, response)
+}
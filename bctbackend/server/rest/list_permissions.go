@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"database/sql"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetPermissionsSuccessResponse struct {
+	Role        string            `json:"role"`
+	Permissions map[string]string `json:"permissions"`
+}
+
+// @Summary Get permissions of the logged in user.
+// @Description Returns the actions the logged in user is allowed to perform, together with their scope.
+// @Description A scope of "any" means the action can be performed on all resources,
+// @Description "own" means it is limited to resources owned by the user.
+// @Description Actions that are not allowed are omitted.
+// @Tags permissions
+// @Produce json
+// @Success 200 {object} GetPermissionsSuccessResponse "Permissions successfully fetched"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Router /permissions [get]
+func GetPermissions(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	permissions := make(map[string]string)
+	for action := range authorization.Actions() {
+		if scope := authorization.ScopeOf(action, roleId); scope != authorization.NoScope {
+			permissions[string(action)] = scope.String()
+		}
+	}
+
+	response := GetPermissionsSuccessResponse{
+		Role:        roleId.Name(),
+		Permissions: permissions,
+	}
+
+	context.JSON(http.StatusOK, response)
+}
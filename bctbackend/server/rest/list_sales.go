@@ -5,15 +5,16 @@ import (
 	"bctbackend/database/queries"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	rest "bctbackend/server/shared"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	_ "bctbackend/docs"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/exp/slog"
 )
 
 type ListSalesSaleData struct {
@@ -60,10 +61,6 @@ func GetSales(context *gin.Context, configuration *configuration.Configuration,
 }
 
 func (ep *getSalesEndpoint) execute() {
-	if !ep.ensureUserIsAdmin() {
-		return
-	}
-
 	queryParameters, ok := ep.parseQueryParameters()
 	if !ok {
 		return
@@ -108,8 +105,8 @@ func (ep *getSalesEndpoint) execute() {
 func (ep *getSalesEndpoint) getItemCount() (int, bool) {
 	soldItemCount, err := queries.CountItems(ep.db, queries.OnlyVisibleItems)
 	if err != nil {
-		slog.Error("Failed to get sold item count", "error", err)
-		failure_response.Unknown(ep.context, "Failed to get sold item count: "+err.Error())
+		logging.FromContext(ep.context).Error("Failed to get sold item count", "error", err)
+		failure_response.UnknownError(ep.context, fmt.Errorf("Failed to get sold item count: %w", err))
 		return 0, false
 	}
 	return soldItemCount, true
@@ -119,8 +116,8 @@ func (ep *getSalesEndpoint) getSoldItemCount() (int, bool) {
 	soldItemCount, err := queries.GetSoldItemsCount(ep.db)
 
 	if err != nil {
-		slog.Error("Failed to get sold item count", "error", err)
-		failure_response.Unknown(ep.context, "Failed to get sold item count: "+err.Error())
+		logging.FromContext(ep.context).Error("Failed to get sold item count", "error", err)
+		failure_response.UnknownError(ep.context, fmt.Errorf("Failed to get sold item count: %w", err))
 		return 0, false
 	}
 
@@ -131,8 +128,8 @@ func (ep *getSalesEndpoint) getSaleCount() (int, bool) {
 	saleCount, err := queries.GetSalesCount(ep.db)
 
 	if err != nil {
-		slog.Error("Failed to get sales count", "error", err)
-		failure_response.Unknown(ep.context, "Failed to get sales count: "+err.Error())
+		logging.FromContext(ep.context).Error("Failed to get sales count", "error", err)
+		failure_response.UnknownError(ep.context, fmt.Errorf("Failed to get sales count: %w", err))
 		return 0, false
 	}
 
@@ -143,8 +140,8 @@ func (ep *getSalesEndpoint) getTotalSalesValue() (models.MoneyInCents, bool) {
 	totalValue, err := queries.GetTotalSalesValue(ep.db)
 
 	if err != nil {
-		slog.Error("Failed to get total sales value", "error", err)
-		failure_response.Unknown(ep.context, "Failed to get total sales value: "+err.Error())
+		logging.FromContext(ep.context).Error("Failed to get total sales value", "error", err)
+		failure_response.UnknownError(ep.context, fmt.Errorf("Failed to get total sales value: %w", err))
 		return 0, false
 	}
 
@@ -152,16 +149,6 @@ func (ep *getSalesEndpoint) getTotalSalesValue() (models.MoneyInCents, bool) {
 
 }
 
-func (ep *getSalesEndpoint) ensureUserIsAdmin() bool {
-	if ep.roleId != models.NewAdminRoleId() {
-		slog.Error("Unauthorized access to list all sales", "userId", ep.userId, "roleId", ep.roleId)
-		failure_response.WrongRole(ep.context, "Only admins can list all items")
-		return false
-	}
-
-	return true
-}
-
 func (ep *getSalesEndpoint) getSales(queryParameters *getSalesQueryParameters) ([]*ListSalesSaleData, bool) {
 	sales := make([]*ListSalesSaleData, 0, 25)
 	processSale := func(sale *models.SaleSummary) error {
@@ -180,8 +167,8 @@ func (ep *getSalesEndpoint) getSales(queryParameters *getSalesQueryParameters) (
 	query := ep.buildQuery(queryParameters)
 
 	if err := query.Execute(ep.db, processSale); err != nil {
-		slog.Error("Failed to get sales", "error", err)
-		failure_response.Unknown(ep.context, "Failed to get sales: "+err.Error())
+		logging.FromContext(ep.context).Error("Failed to get sales", "error", err)
+		failure_response.UnknownError(ep.context, fmt.Errorf("Failed to get sales: %w", err))
 		return nil, false
 	}
 
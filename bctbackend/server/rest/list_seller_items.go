@@ -5,11 +5,13 @@ import (
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
 	rest "bctbackend/server/shared"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	_ "bctbackend/docs"
@@ -45,11 +47,6 @@ type GetSellerItemsSuccessResponse struct {
 // @Failure 500 {object} failure_response.FailureResponse "Failed to fetch items"
 // @Router /seller/{seller_id}/items [get]
 func GetSellerItems(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if !roleId.IsSeller() && !roleId.IsAdmin() {
-		failure_response.Forbidden(context, "wrong_role", "Only accessible to sellers and admins")
-		return
-	}
-
 	var uriParameters struct {
 		SellerId string `uri:"id" binding:"required"`
 	}
@@ -75,11 +72,11 @@ func GetSellerItems(context *gin.Context, configuration *configuration.Configura
 			return
 		}
 
-		failure_response.Unknown(context, "Could not check user role: "+err.Error())
+		failure_response.UnknownError(context, fmt.Errorf("Could not check user role: %w", err))
 		return
 	}
 
-	if userId != uriSellerId && !roleId.IsAdmin() {
+	if !authorization.IsAllowedOn(authorization.ListSellerItems, userId, roleId, uriSellerId) {
 		failure_response.WrongSeller(context, "Logged in user does not match URI seller ID")
 		return
 	}
@@ -96,7 +93,7 @@ func GetSellerItems(context *gin.Context, configuration *configuration.Configura
 
 	items, err := queries.GetSellerItems(db, uriSellerId, itemSelection)
 	if err != nil {
-		failure_response.Unknown(context, "Could not retrieve seller items: "+err.Error())
+		failure_response.UnknownError(context, fmt.Errorf("Could not retrieve seller items: %w", err))
 		return
 	}
 
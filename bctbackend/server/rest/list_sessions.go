@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/security"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	rest "bctbackend/server/shared"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetSessionsSessionData struct {
+	// Public handle by which the session can be revoked; the session id itself is never revealed
+	SessionHandle  string        `json:"sessionHandle"`
+	UserId         models.Id     `json:"userId"`
+	CreatedAt      rest.DateTime `json:"createdAt"`
+	ExpirationTime rest.DateTime `json:"expirationTime"`
+	Current        bool          `json:"current"`
+}
+
+type GetSessionsSuccessResponse struct {
+	Sessions []*GetSessionsSessionData `json:"sessions"`
+}
+
+// @Summary Get list of active sessions.
+// @Description Returns all active sessions if the user is an admin.
+// @Description Other users only get to see their own sessions.
+// @Tags sessions
+// @Produce json
+// @Success 200 {object} GetSessionsSuccessResponse "Sessions successfully fetched"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /sessions [get]
+func GetSessions(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var sessions []models.Session
+	var err error
+
+	if authorization.ScopeOf(authorization.ListSessions, roleId) == authorization.AnyScope {
+		sessions, err = queries.GetSessions(db)
+	} else {
+		sessions, err = queries.GetSessionsOfUser(db, userId)
+	}
+
+	if err != nil {
+		logging.FromContext(context).Error("Failed to fetch sessions", slog.String("error", err.Error()))
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	currentSessionId, _ := context.Cookie(security.SessionCookieName)
+	now := models.Now()
+
+	activeSessions := algorithms.Filter(sessions, func(session models.Session) bool {
+		return now < session.ExpirationTime
+	})
+
+	response := GetSessionsSuccessResponse{
+		Sessions: algorithms.Map(activeSessions, func(session models.Session) *GetSessionsSessionData {
+			return &GetSessionsSessionData{
+				SessionHandle:  security.DeriveSessionHandle(session.SessionID),
+				UserId:         session.UserID,
+				CreatedAt:      rest.ConvertTimestampToDateTime(session.CreatedAt),
+				ExpirationTime: rest.ConvertTimestampToDateTime(session.ExpirationTime),
+				Current:        string(session.SessionID) == currentSessionId,
+			}
+		}),
+	}
+
+	context.IndentedJSON(http.StatusOK, response)
+}
@@ -5,6 +5,7 @@ import (
 	"bctbackend/database/queries"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	rest "bctbackend/server/shared"
 	"database/sql"
 	"log/slog"
@@ -40,20 +41,10 @@ type GetUsersSuccessResponse struct {
 // @Failure 500 {object} failure_response.FailureResponse "Internal error"
 // @Router /users [get]
 func GetUsers(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
-	if !roleId.IsAdmin() {
-		slog.Info(
-			"Non-admin attempted to list all items",
-			slog.Int64("user_id", userId.Int64()),
-			slog.Int64("role_id", roleId.Int64()))
-
-		failure_response.WrongRole(context, "Only accessible to admins")
-		return
-	}
-
 	users := []*queries.UserWithItemCount{}
 	if err := queries.GetUsersWithItemCount(db, queries.OnlyVisibleItems, queries.CollectTo(&users)); err != nil {
-		slog.Error("Failed to fetch users", slog.String("error", err.Error()))
-		failure_response.Unknown(context, err.Error())
+		logging.FromContext(context).Error("Failed to fetch users", slog.String("error", err.Error()))
+		failure_response.UnknownError(context, err)
 		return
 	}
 
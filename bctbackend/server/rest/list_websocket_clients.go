@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/database/models"
+	"bctbackend/server/configuration"
+	rest "bctbackend/server/shared"
+	"bctbackend/server/websocket"
+	"database/sql"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetWebsocketClientsClientData struct {
+	UserId          models.Id     `json:"userId"`
+	Role            string        `json:"role"`
+	RemoteAddress   string        `json:"remoteAddress"`
+	ConnectedAt     rest.DateTime `json:"connectedAt"`
+	QueueDepth      int           `json:"queueDepth"`
+	DroppedMessages int           `json:"droppedMessages"`
+}
+
+type GetWebsocketClientsSuccessResponse struct {
+	Clients []*GetWebsocketClientsClientData `json:"clients"`
+}
+
+// @Summary Get list of clients listening for events.
+// @Description Returns the connected websocket clients together with the number of events waiting to be sent to them.
+// @Description Only accessible to admins.
+// @Tags websocket
+// @Produce json
+// @Success 200 {object} GetWebsocketClientsSuccessResponse "Clients successfully fetched"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Router /websocket/clients [get]
+func GetWebsocketClients(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	subscribers := websocket.ListSubscribers(context)
+
+	response := GetWebsocketClientsSuccessResponse{
+		Clients: algorithms.Map(subscribers, func(subscriber *websocket.SubscriberInfo) *GetWebsocketClientsClientData {
+			return &GetWebsocketClientsClientData{
+				UserId:          subscriber.UserId,
+				Role:            subscriber.RoleId.Name(),
+				RemoteAddress:   subscriber.RemoteAddress,
+				ConnectedAt:     rest.ConvertTimestampToDateTime(subscriber.ConnectedAt),
+				QueueDepth:      subscriber.QueueDepth,
+				DroppedMessages: subscriber.DroppedMessages,
+			}
+		}),
+	}
+
+	context.JSON(http.StatusOK, response)
+}
@@ -5,7 +5,9 @@ import (
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
 	"bctbackend/security"
+	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	"database/sql"
 	"errors"
 	"log/slog"
@@ -22,11 +24,13 @@ type LoginRequest struct {
 }
 
 type LoginSuccessResponse struct {
-	Role string `json:"role"`
+	Role      string `json:"role"`
+	CSRFToken string `json:"csrfToken"`
 }
 
 // @Summary Login user.
-// @Description Login user. If successful, returns the role of the user.
+// @Description Login user. If successful, returns the role of the user and the CSRF token
+// @Description that must be passed in the X-CSRF-Token header of every mutating request.
 // @Description If the user is unknown, returns 401 Unauthorized with type "unknown_user".
 // @Description If the password is wrong, returns 401 Unauthorized with type "wrong_password".
 // @Success 200 {object} LoginSuccessResponse
@@ -38,18 +42,18 @@ type LoginSuccessResponse struct {
 // @Param username formData string true "username"
 // @Param password formData string true "password"
 // @Tags authentication
-func Login(context *gin.Context, db *sql.DB) {
+func Login(context *gin.Context, configuration *configuration.Configuration, db *sql.DB) {
 	var loginRequest LoginRequest
 
 	if err := context.ShouldBind(&loginRequest); err != nil {
-		slog.Info("Failed to parse login request", slog.String("error", err.Error()))
+		logging.FromContext(context).Info("Failed to parse login request", slog.String("error", err.Error()))
 		failure_response.InvalidRequest(context, "Failed to parse request")
 		return
 	}
 
 	userId, err := models.ParseId(loginRequest.Username)
 	if err != nil {
-		slog.Info("Someone tried to login with an invalid user ID", slog.String("userId", loginRequest.Username))
+		logging.FromContext(context).Info("Someone tried to login with an invalid user ID", slog.String("userId", loginRequest.Username))
 		failure_response.InvalidUserId(context, err.Error())
 		return
 	}
@@ -59,37 +63,36 @@ func Login(context *gin.Context, db *sql.DB) {
 
 	if err != nil {
 		if errors.Is(err, dberr.ErrNoSuchUser) {
-			slog.Info("Unknown user trying to log in", slog.String("userId", loginRequest.Username))
+			logging.FromContext(context).Info("Unknown user trying to log in", slog.String("userId", loginRequest.Username))
 			failure_response.UnknownUser(context, err.Error())
 			return
 		}
 
 		if errors.Is(err, dberr.ErrWrongPassword) {
-			slog.Info("User entered wrong password", slog.String("userId", loginRequest.Username))
+			logging.FromContext(context).Info("User entered wrong password", slog.String("userId", loginRequest.Username))
 			failure_response.WrongPassword(context, err.Error())
 			return
 		}
 
-		slog.Error("Failed authentication for unknown reasons", slog.String("userId", loginRequest.Username), slog.String("error", err.Error()))
-		failure_response.Unknown(context, err.Error())
+		logging.FromContext(context).Error("Failed authentication for unknown reasons", slog.String("userId", loginRequest.Username), slog.String("error", err.Error()))
+		failure_response.UnknownError(context, err)
 		return
 	}
 
-	expirationTime := models.Now() + security.SessionDurationInSeconds
+	expirationTime := models.Now() + security.SessionIdleTimeoutInSeconds
 	sessionId, err := queries.AddSession(db, userId, expirationTime)
 
 	if err != nil {
-		slog.Error("Failed to create session", slog.String("userId", loginRequest.Username), slog.String("error", err.Error()))
-		failure_response.Unknown(context, err.Error())
+		logging.FromContext(context).Error("Failed to create session", slog.String("userId", loginRequest.Username), slog.String("error", err.Error()))
+		failure_response.UnknownError(context, err)
 		return
 	}
 
-	ensureSecure := false // TODO: set to true when using HTTPS
-	context.SetCookie(security.SessionCookieName, string(sessionId), security.SessionDurationInSeconds, "/", "localhost", ensureSecure, true)
+	setSessionCookies(context, configuration, sessionId)
 	roleName := roleId.Name()
 
-	response := LoginSuccessResponse{Role: roleName}
+	response := LoginSuccessResponse{Role: roleName, CSRFToken: security.DeriveCSRFToken(sessionId)}
 	context.JSON(http.StatusOK, response)
 
-	slog.Info("User logged in successfully", slog.String("userId", loginRequest.Username))
+	logging.FromContext(context).Info("User logged in successfully", slog.String("userId", loginRequest.Username))
 }
@@ -2,12 +2,16 @@ package rest
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
 
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
 	_ "bctbackend/docs"
 	"bctbackend/security"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/sessions"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,10 +19,11 @@ import (
 type LogoutPayload struct{}
 
 // @Summary Logout user.
-// @Description Logs out the user.
+// @Description Logs out the user. Like all mutating requests authenticated by a session cookie, it requires the CSRF token.
+// @Failure 403 {object} failure_response.FailureResponse "Missing or invalid CSRF token"
 // @Tags authentication
 // @Router /logout [post]
-func Logout(context *gin.Context, db *sql.DB) {
+func Logout(context *gin.Context, configuration *configuration.Configuration, db *sql.DB) {
 	sessionIdString, err := context.Cookie(security.SessionCookieName)
 	if err != nil {
 		context.JSON(http.StatusOK, gin.H{"message": "Unauthorized: missing session ID"})
@@ -26,7 +31,17 @@ func Logout(context *gin.Context, db *sql.DB) {
 	}
 
 	sessionId := models.SessionId(sessionIdString)
+
+	// Logging out deletes the session, so a cross-site form must not be able to do it either
+	if !security.IsValidCSRFToken(sessionId, context.GetHeader(security.CSRFHeaderName)) {
+		failure_response.InvalidCSRFToken(context, fmt.Sprintf("Missing or invalid %s header", security.CSRFHeaderName))
+		return
+	}
+
+	clearSessionCookies(context, configuration)
+
 	err = queries.DeleteSession(db, sessionId)
+	sessions.InvalidateSession(context, sessionId)
 
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete session"})
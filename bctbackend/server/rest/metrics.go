@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"bctbackend/server/metrics"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get metrics in the Prometheus text format.
+// @Description Covers request counts and latencies per route, failure responses, sales, revenue,
+// @Description connected event subscribers and database lock contention.
+// @Description Meant to be scraped with an API token restricted to view_metrics.
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "Metrics"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Router /metrics [get]
+func GetMetrics(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	registry, ok := metrics.FromContext(context)
+	if !ok {
+		failure_response.Unknown(context, "Metrics are not being collected")
+		return
+	}
+
+	context.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	context.Status(http.StatusOK)
+	if err := registry.Write(context.Writer); err != nil {
+		logging.FromContext(context).Error("Failed to write metrics", slog.String("error", err.Error()))
+	}
+}
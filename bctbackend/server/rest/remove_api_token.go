@@ -0,0 +1,61 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveApiTokenSuccessResponse struct {
+}
+
+// @Summary Revoke an API token.
+// @Description Revokes an API token. Only accessible to admins.
+// @Tags tokens
+// @Param id path int true "Token ID"
+// @Success 204 {object} RemoveApiTokenSuccessResponse "Token successfully revoked"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Failure 404 {object} failure_response.FailureResponse "Token does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /tokens/{id} [delete]
+func RemoveApiToken(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		TokenId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	tokenId, err := models.ParseId(uriParameters.TokenId)
+	if err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	if err := queries.RemoveApiToken(db, tokenId); err != nil {
+		if errors.Is(err, dberr.ErrNoSuchApiToken) {
+			failure_response.UnknownApiToken(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	logging.FromContext(context).Info("API token revoked", slog.Int64("token_id", tokenId.Int64()), slog.Int64("revoked_by", userId.Int64()))
+	context.Status(http.StatusNoContent)
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveLabelLayoutSuccessResponse struct {
+}
+
+// @Summary Remove a label layout preset.
+// @Description Removes a label layout preset. Only accessible to admins.
+// @Tags labels
+// @Param id path int true "Layout ID"
+// @Success 204 {object} RemoveLabelLayoutSuccessResponse "Layout successfully removed"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
+// @Failure 404 {object} failure_response.FailureResponse "Layout does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /label-layouts/{id} [delete]
+func RemoveLabelLayout(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		LayoutId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	layoutId, err := models.ParseId(uriParameters.LayoutId)
+	if err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	if err := queries.RemoveLabelLayout(db, layoutId); err != nil {
+		respondToLabelLayoutError(context, err)
+		return
+	}
+
+	logging.FromContext(context).Info("Label layout removed", slog.Int64("layout_id", layoutId.Int64()), slog.Int64("removed_by", userId.Int64()))
+	context.Status(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/events"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveSaleSuccessResponse struct {
+}
+
+// @Summary Void a sale.
+// @Description Removes a sale, making its items available for sale again.
+// @Tags sales
+// @Param id path int true "Sale ID"
+// @Success 204 {object} RemoveSaleSuccessResponse "Sale successfully voided"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins and supervisors"
+// @Failure 404 {object} failure_response.FailureResponse "Sale not found"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /sales/{id} [delete]
+func RemoveSale(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		SaleId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	saleId, err := models.ParseId(uriParameters.SaleId)
+	if err != nil {
+		failure_response.InvalidSaleId(context, err.Error())
+		return
+	}
+
+	// Look up the sale before removing it so that clients can be told which items became available again
+	sale, err := queries.GetSaleWithId(db, saleId)
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchSale) {
+			failure_response.UnknownSale(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	saleItems, err := queries.GetSaleItems(db, saleId)
+	if err != nil {
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	if err := queries.RemoveSale(db, saleId); err != nil {
+		if errors.Is(err, dberr.ErrNoSuchSale) {
+			failure_response.UnknownSale(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	events.Publish(context, events.NewSaleVoided(saleId, sale.CashierID, saleItems))
+
+	logging.FromContext(context).Info("Sale voided", slog.Int64("sale_id", saleId.Int64()), slog.Int64("user_id", userId.Int64()))
+	context.Status(http.StatusNoContent)
+}
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"bctbackend/server/sessions"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveSessionSuccessResponse struct {
+}
+
+// @Summary Revoke a session.
+// @Description Revokes a session, logging out whoever is using it.
+// @Description Admins can revoke any session, other users only their own.
+// @Tags sessions
+// @Param id path string true "Session handle, as returned when listing sessions"
+// @Success 204 {object} RemoveSessionSuccessResponse "Session successfully revoked"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Session belongs to another user"
+// @Failure 404 {object} failure_response.FailureResponse "Session does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /sessions/{id} [delete]
+func RemoveSession(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		SessionHandle string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	session, err := queries.GetSessionWithHandle(db, uriParameters.SessionHandle)
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchSession) {
+			failure_response.UnknownSession(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	if !authorization.IsAllowedOn(authorization.RevokeSessions, userId, roleId, session.UserID) {
+		logging.FromContext(context).Info("User attempted to revoke another user's session", "userId", userId, "sessionOwner", session.UserID)
+		failure_response.WrongUser(context, "Only admins can revoke other users' sessions")
+		return
+	}
+
+	sessionId := session.SessionID
+	err = queries.DeleteSession(db, sessionId)
+	sessions.InvalidateSession(context, sessionId)
+
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchSession) {
+			failure_response.UnknownSession(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	context.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"bctbackend/server/sessions"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveUserSessionsSuccessResponse struct {
+	RemovedSessionCount int `json:"removedSessionCount"`
+}
+
+// @Summary Log a user out everywhere.
+// @Description Revokes all sessions of a user, including the one used to make this request.
+// @Description Admins can do this for any user, other users only for themselves.
+// @Tags sessions
+// @Param id path int true "User ID"
+// @Produce json
+// @Success 200 {object} RemoveUserSessionsSuccessResponse "Sessions successfully revoked"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse URI"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Not allowed to revoke other users' sessions"
+// @Failure 404 {object} failure_response.FailureResponse "User does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /users/{id}/sessions [delete]
+func RemoveUserSessions(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		UserId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	queriedUserId, err := models.ParseId(uriParameters.UserId)
+	if err != nil {
+		failure_response.InvalidUserId(context, err.Error())
+		return
+	}
+
+	if !authorization.IsAllowedOn(authorization.RevokeSessions, userId, roleId, queriedUserId) {
+		logging.FromContext(context).Info("User attempted to revoke another user's sessions", "userId", userId, "queriedUserId", queriedUserId)
+		failure_response.WrongUser(context, "Only admins can revoke other users' sessions")
+		return
+	}
+
+	removedSessionCount, err := queries.DeleteSessionsOfUser(db, queriedUserId)
+	sessions.InvalidateUserSessions(context, queriedUserId)
+
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchUser) {
+			failure_response.UnknownUser(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	response := RemoveUserSessionsSuccessResponse{RemovedSessionCount: removedSessionCount}
+	context.JSON(http.StatusOK, response)
+}
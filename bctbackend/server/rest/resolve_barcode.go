@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"bctbackend/barcode"
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResolveBarcodeSuccessResponse struct {
+	ItemId models.Id `json:"itemId"`
+}
+
+// @Summary Resolve a scanned barcode
+// @Description Determines which item a scanned barcode refers to.
+// @Description Barcodes generated for other events and misread barcodes are rejected.
+// @Success 200 {object} ResolveBarcodeSuccessResponse
+// @Failure 400 {object} failure_response.FailureResponse "Malformed, corrupted or foreign barcode"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to cashiers, admins and owner sellers"
+// @Failure 404 {object} failure_response.FailureResponse "Item not found"
+// @Router /barcodes/{payload} [get]
+func ResolveBarcode(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		Payload string `uri:"payload" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, "Invalid URI parameters: "+err.Error())
+		return
+	}
+
+	eventPrefix, err := queries.GetEventPrefix(db)
+	if err != nil {
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	itemId, err := barcode.ParsePayload(uriParameters.Payload, eventPrefix)
+	if err != nil {
+		switch {
+		case errors.Is(err, barcode.ErrForeignPayload):
+			failure_response.ForeignBarcode(context, err.Error())
+		case errors.Is(err, barcode.ErrCorruptedPayload):
+			failure_response.CorruptedBarcode(context, err.Error())
+		default:
+			failure_response.MalformedBarcode(context, err.Error())
+		}
+		return
+	}
+
+	item, err := queries.GetItemWithId(db, models.Id(itemId))
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchItem) {
+			failure_response.UnknownItem(context, err.Error())
+			return
+		}
+
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	if !authorization.IsAllowedOn(authorization.ViewItem, userId, roleId, item.SellerID) {
+		failure_response.WrongSeller(context, "Only the owning seller can access this item")
+		return
+	}
+
+	context.JSON(http.StatusOK, ResolveBarcodeSuccessResponse{ItemId: item.ItemID})
+}
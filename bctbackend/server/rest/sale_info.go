@@ -5,11 +5,13 @@ import (
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
 	rest "bctbackend/server/shared"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -52,10 +54,6 @@ func GetSaleInformation(context *gin.Context, configuration *configuration.Confi
 }
 
 func (endpoint *getSaleInformationEndpoint) execute() {
-	if !endpoint.ensureUserHasRightRole() {
-		return
-	}
-
 	saleId, ok := endpoint.extractSaleIdFromUri()
 	if !ok {
 		return
@@ -68,12 +66,12 @@ func (endpoint *getSaleInformationEndpoint) execute() {
 			return
 		}
 
-		failure_response.Unknown(endpoint.context, "Could not retrieve sale information: "+err.Error())
+		failure_response.UnknownError(endpoint.context, fmt.Errorf("Could not retrieve sale information: %w", err))
 		return
 	}
 
-	if endpoint.roleId.IsCashier() && sale.CashierID != endpoint.userId {
-		failure_response.Forbidden(endpoint.context, "wrong_sale", "Only accessible to cashiers and owning cashiers")
+	if !authorization.IsAllowedOn(authorization.ViewSale, endpoint.userId, endpoint.roleId, sale.CashierID) {
+		failure_response.Forbidden(endpoint.context, "wrong_sale", "Only accessible to admins and owning cashiers")
 		return
 	}
 
@@ -84,7 +82,7 @@ func (endpoint *getSaleInformationEndpoint) execute() {
 			return
 		}
 
-		failure_response.Unknown(endpoint.context, "Could not retrieve sale information: "+err.Error())
+		failure_response.UnknownError(endpoint.context, fmt.Errorf("Could not retrieve sale information: %w", err))
 		return
 	}
 
@@ -111,15 +109,6 @@ func (endpoint *getSaleInformationEndpoint) convertSaleItemToData(saleItem *mode
 	}
 }
 
-func (endpoint *getSaleInformationEndpoint) ensureUserHasRightRole() bool {
-	if !endpoint.roleId.IsAdmin() && !endpoint.roleId.IsCashier() {
-		failure_response.Forbidden(endpoint.context, "wrong_role", "Only accessible to cashiers and owning cashiers")
-		return false
-	}
-
-	return true
-}
-
 func (endpoint *getSaleInformationEndpoint) extractSaleIdFromUri() (models.Id, bool) {
 	var uriParameters struct {
 		SaleId string `uri:"id" binding:"required"`
@@ -4,11 +4,13 @@ import (
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
+	"bctbackend/server/events"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
 	"database/sql"
 	"errors"
-	"log/slog"
 	"net/http"
 
 	_ "bctbackend/docs"
@@ -60,20 +62,15 @@ func UpdateItem(context *gin.Context, configuration *configuration.Configuration
 			failure_response.UnknownItem(context, err.Error())
 			return
 		}
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
-	if roleId.IsSeller() && item.SellerID != userId {
+	if !authorization.IsAllowedOn(authorization.UpdateItem, userId, roleId, item.SellerID) {
 		failure_response.WrongSeller(context, "Only the owner of the item can update it")
 		return
 	}
 
-	if !roleId.IsAdmin() && !roleId.IsSeller() {
-		failure_response.WrongRole(context, "Must be seller or admin to update item")
-		return
-	}
-
 	var payload UpdateItemData
 	if err := context.ShouldBindJSON(&payload); err != nil {
 		failure_response.InvalidRequest(context, err.Error())
@@ -90,7 +87,7 @@ func UpdateItem(context *gin.Context, configuration *configuration.Configuration
 	}
 	if err := queries.UpdateItem(db, itemId, &itemUpdate); err != nil {
 		if errors.Is(err, dberr.ErrNoSuchItem) {
-			slog.Error(
+			logging.FromContext(context).Error(
 				"Failed to update item",
 				"itemId", itemId,
 				"description", payload.Description,
@@ -112,7 +109,13 @@ func UpdateItem(context *gin.Context, configuration *configuration.Configuration
 			return
 		}
 
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
+		return
+	}
+
+	events.Publish(context, events.NewItemUpdated(itemId, item.SellerID))
+	if payload.CategoryId != nil && *payload.CategoryId != item.CategoryID {
+		events.Publish(context, events.NewCategoryChanged(item.CategoryID, *payload.CategoryId))
 	}
 
 	context.JSON(http.StatusNoContent, nil)
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	_ "bctbackend/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateLabelLayoutSuccessResponse struct {
+}
+
+// @Summary Update a label layout preset.
+// @Description Replaces the name and layout of a preset. Only accessible to admins.
+// @Tags labels
+// @Accept json
+// @Param id path int true "Layout ID"
+// @Param LabelLayoutPayload body LabelLayoutPayload true "Name and layout"
+// @Success 204 {object} UpdateLabelLayoutSuccessResponse "Layout successfully updated"
+// @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload or URI, invalid name or name already in use"
+// @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
+// @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins, or invalid layout"
+// @Failure 404 {object} failure_response.FailureResponse "Layout or label template does not exist"
+// @Failure 500 {object} failure_response.FailureResponse "Internal error"
+// @Router /label-layouts/{id} [put]
+func UpdateLabelLayout(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId) {
+	var uriParameters struct {
+		LayoutId string `uri:"id" binding:"required"`
+	}
+	if err := context.ShouldBindUri(&uriParameters); err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	layoutId, err := models.ParseId(uriParameters.LayoutId)
+	if err != nil {
+		failure_response.InvalidUriParameters(context, err.Error())
+		return
+	}
+
+	var payload LabelLayoutPayload
+	if err := context.ShouldBindJSON(&payload); err != nil {
+		failure_response.InvalidRequest(context, "Failed to parse payload: "+err.Error())
+		return
+	}
+
+	layout := payload.Layout.toLabelLayout(payload.Name)
+	layout.LayoutId = layoutId
+	if !validateLabelLayout(context, configuration, layout) {
+		return
+	}
+
+	if err := queries.UpdateLabelLayout(db, layout); err != nil {
+		respondToLabelLayoutError(context, err)
+		return
+	}
+
+	logging.FromContext(context).Info("Label layout updated", slog.Int64("layout_id", layoutId.Int64()), slog.String("name", layout.Name))
+	context.Status(http.StatusNoContent)
+}
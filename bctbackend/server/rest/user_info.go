@@ -5,6 +5,7 @@ import (
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
 	"bctbackend/server/failure_response"
 	rest "bctbackend/server/shared"
@@ -88,6 +89,7 @@ func convertSaleToGetUserInformationSale(sale *models.Sale) *GetUserInformationS
 // @Summary Get information about a user
 // @Description Get information about a user.
 // @Success 200 {object} GetSellerSummarySuccessResponse
+// @Success 200 {object} GetUserInformationSuccessResponse "Information about users of other roles"
 // @Failure 400 {object} failure_response.FailureResponse "Failed to parse payload or URI"
 // @Failure 401 {object} failure_response.FailureResponse "Not authenticated"
 // @Failure 403 {object} failure_response.FailureResponse "Only accessible to admins"
@@ -111,34 +113,42 @@ func GetUserInformation(context *gin.Context, configuration *configuration.Confi
 		return
 	}
 
-	if roleId.IsAdmin() {
-		// If the user is an admin, they can access any user's information
-		getUserInformationAsAdmin(context, db, queriedUserId)
+	if !authorization.IsAllowedOn(authorization.ViewUser, userId, roleId, queriedUserId) {
+		failure_response.WrongRole(context, "Only admins can access other users' information")
 		return
-	} else if roleId.IsSeller() {
-		getUserInformationAsSeller(context, db, userId, queriedUserId)
+	}
+
+	if authorization.ScopeOf(authorization.ViewUser, roleId) == authorization.AnyScope {
+		getFullUserInformation(context, db, queriedUserId)
 		return
-	} else if roleId.IsCashier() {
-		getUserInformationAsCashier(context, db, userId, queriedUserId)
+	}
+
+	// Users that can only access their own information get a summary tailored to their role
+	if roleId.IsSeller() {
+		getSellerSummary(context, db, queriedUserId)
 		return
-	} else {
-		failure_response.Unknown(context, fmt.Sprintf("Bug: unhandled role %d", roleId))
+	}
+
+	// Other roles have no data of their own beyond their account
+	_, basicInformation, ok := lookUpBasicUserInformation(context, db, queriedUserId)
+	if !ok {
 		return
 	}
+	context.JSON(http.StatusOK, basicInformation)
 }
 
-func getUserInformationAsAdmin(context *gin.Context, db *sql.DB, queriedUserId models.Id) {
-
-	// Look up user in database
+// lookUpBasicUserInformation fetches the information all users have in common.
+// If this fails, a failure response is written and false is returned.
+func lookUpBasicUserInformation(context *gin.Context, db *sql.DB, queriedUserId models.Id) (*models.User, GetUserInformationSuccessResponse, bool) {
 	user, err := queries.GetUserWithId(db, queriedUserId)
 	if err != nil {
 		if errors.Is(err, dberr.ErrNoSuchUser) {
 			failure_response.UnknownUser(context, err.Error())
-			return
+			return nil, GetUserInformationSuccessResponse{}, false
 		}
 
-		failure_response.Unknown(context, err.Error())
-		return
+		failure_response.UnknownError(context, err)
+		return nil, GetUserInformationSuccessResponse{}, false
 	}
 
 	basicInformation := GetUserInformationSuccessResponse{
@@ -149,6 +159,15 @@ func getUserInformationAsAdmin(context *gin.Context, db *sql.DB, queriedUserId m
 		LastActivity: algorithms.MapOptional(user.LastActivity, rest.ConvertTimestampToDateTime),
 	}
 
+	return user, basicInformation, true
+}
+
+func getFullUserInformation(context *gin.Context, db *sql.DB, queriedUserId models.Id) {
+	user, basicInformation, ok := lookUpBasicUserInformation(context, db, queriedUserId)
+	if !ok {
+		return
+	}
+
 	if user.RoleId.IsAdmin() {
 		response := GetAdminInformationSuccessResponse{
 			GetUserInformationSuccessResponse: basicInformation,
@@ -160,15 +179,15 @@ func getUserInformationAsAdmin(context *gin.Context, db *sql.DB, queriedUserId m
 		if err != nil {
 			{
 				if errors.Is(err, dberr.ErrNoSuchUser) {
-					failure_response.Unknown(context, "Bug: should have been caught earlier. "+err.Error())
+					failure_response.UnknownError(context, fmt.Errorf("Bug: should have been caught earlier. %w", err))
 					return
 				}
 			}
 			if errors.Is(err, dberr.ErrWrongRole) {
-				failure_response.Unknown(context, "Bug: should have been caught earlier. "+err.Error())
+				failure_response.UnknownError(context, fmt.Errorf("Bug: should have been caught earlier. %w", err))
 				return
 			}
-			failure_response.Unknown(context, fmt.Errorf("failed to find information about seller: %w", err).Error())
+			failure_response.UnknownError(context, fmt.Errorf("failed to find information about seller: %w", err))
 			return
 		}
 
@@ -185,14 +204,14 @@ func getUserInformationAsAdmin(context *gin.Context, db *sql.DB, queriedUserId m
 		sales, err := queries.GetSalesWithCashier(db, user.UserId)
 		if err != nil {
 			if errors.Is(err, dberr.ErrNoSuchUser) {
-				failure_response.Unknown(context, "Bug: should have been caught earlier. "+err.Error())
+				failure_response.UnknownError(context, fmt.Errorf("Bug: should have been caught earlier. %w", err))
 				return
 			}
 			if errors.Is(err, dberr.ErrWrongRole) {
-				failure_response.Unknown(context, "Bug: should have been caught earlier. "+err.Error())
+				failure_response.UnknownError(context, fmt.Errorf("Bug: should have been caught earlier. %w", err))
 				return
 			}
-			failure_response.Unknown(context, err.Error())
+			failure_response.UnknownError(context, err)
 			return
 		}
 
@@ -205,42 +224,38 @@ func getUserInformationAsAdmin(context *gin.Context, db *sql.DB, queriedUserId m
 		context.JSON(http.StatusOK, response)
 		return
 	} else {
-		failure_response.Unknown(context, fmt.Sprintf("Bug: unhandled role %d", user.RoleId.Int64()))
+		// Roles without data of their own, such as volunteers and supervisors
+		context.JSON(http.StatusOK, basicInformation)
 		return
 	}
 }
 
-func getUserInformationAsSeller(context *gin.Context, db *sql.DB, userId models.Id, queriedUserId models.Id) {
-	if userId != queriedUserId {
-		failure_response.WrongRole(context, "Only admins can access other users' information")
-		return
-	}
-
+func getSellerSummary(context *gin.Context, db *sql.DB, queriedUserId models.Id) {
 	itemCount, err := queries.GetSellerItemCount(db, queriedUserId, queries.Include, queries.Exclude)
 	if err != nil {
 		// At this point, we know that the user exists and is a seller, so no errors should ever occur
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
 	frozenItemCount, err := queries.GetSellerItemCount(db, queriedUserId, queries.Exclusive, queries.Include)
 	if err != nil {
 		// At this point, we know that the user exists and is a seller, so no errors should ever occur
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
 	hiddenItemCount, err := queries.GetSellerItemCount(db, queriedUserId, queries.Include, queries.Exclusive)
 	if err != nil {
 		// At this point, we know that the user exists and is a seller, so no errors should ever occur
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
 	totalPrice, err := queries.GetSellerTotalPriceOfAllItems(db, queriedUserId, queries.OnlyVisibleItems)
 	if err != nil {
 		// At this point, we know that the user exists and is a seller, so no errors should ever occur
-		failure_response.Unknown(context, err.Error())
+		failure_response.UnknownError(context, err)
 		return
 	}
 
@@ -253,12 +268,3 @@ func getUserInformationAsSeller(context *gin.Context, db *sql.DB, userId models.
 
 	context.JSON(http.StatusOK, response)
 }
-
-func getUserInformationAsCashier(context *gin.Context, db *sql.DB, userId models.Id, queriedUserId models.Id) {
-	if userId != queriedUserId {
-		failure_response.WrongRole(context, "Only admins can access users' information")
-		return
-	}
-
-	failure_response.Forbidden(context, "not_yet_implemented", "Cashiers have no information (as of yet)")
-}
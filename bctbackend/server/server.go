@@ -1,20 +1,39 @@
 package server
 
 import (
+	"bctbackend/database"
 	dberr "bctbackend/database/errors"
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
 	"bctbackend/security"
+	"bctbackend/server/authorization"
 	"bctbackend/server/configuration"
+	"bctbackend/server/events"
 	"bctbackend/server/failure_response"
+	"bctbackend/server/jobs"
+	"bctbackend/server/logging"
+	"bctbackend/server/metrics"
+	"bctbackend/server/origins"
 	"bctbackend/server/paths"
 	"bctbackend/server/rest"
+	"bctbackend/server/sessions"
+	"bctbackend/server/sse"
 	"bctbackend/server/websocket"
+	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	_ "bctbackend/docs"
 
@@ -44,35 +63,116 @@ import (
 func StartServer(db *sql.DB, configuration *configuration.Configuration) error {
 	server := NewServer(db, configuration)
 
-	if err := server.run(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := server.run(ctx); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+const (
+	// How long a cached session is trusted before it is checked against the database again
+	sessionCacheTimeToLive = 30 * time.Second
+
+	// How often session renewals and last activity timestamps are written to the database
+	activityFlushInterval = 5 * time.Second
+
+	// How often websocket connections are checked for expired or revoked credentials
+	subscriptionRevalidationInterval = 30 * time.Second
+
+	// How long in-flight requests are given to finish when the server shuts down
+	shutdownTimeout = 10 * time.Second
+
+	// How long the result of a label job remains available for download after it finished
+	labelJobRetention = time.Hour
+)
+
+var eventStreamOptions = sse.Options{
+	HistorySize:          256,
+	QueueSize:            64,
+	HeartbeatInterval:    15 * time.Second,
+	RevalidationInterval: subscriptionRevalidationInterval,
+}
+
+var websocketOptions = websocket.Options{
+	QueueSize:            64,
+	OverflowPolicy:       websocket.DisconnectOnOverflow,
+	PingInterval:         30 * time.Second,
+	PongTimeout:          10 * time.Second,
+	WriteTimeout:         5 * time.Second,
+	RevalidationInterval: subscriptionRevalidationInterval,
+}
+
 type Server struct {
 	database      *sql.DB
 	configuration *configuration.Configuration
 	broadcaster   *websocket.WebsocketBroadcaster
+	eventStream   *sse.Broadcaster
+	sessionCache  *sessions.Cache
+	metrics       *metrics.Registry
+	origins       *origins.Policy
+	labelJobs     *jobs.Registry
 	router        *gin.Engine
 }
 
 func NewServer(db *sql.DB, configuration *configuration.Configuration) *Server {
+	var sessionCache *sessions.Cache
+	if !configuration.DisableSessionCache {
+		sessionCache = sessions.NewCache(db, sessionCacheTimeToLive)
+	}
+
+	originPolicy := origins.NewPolicy(configuration.AllowedOrigins, configuration.AllowAllOrigins)
+	registry := metrics.NewRegistry()
+
 	server := Server{
 		database:      db,
 		configuration: configuration,
-		broadcaster:   websocket.NewWebsocketBroadcaster(),
-		router:        createGinRouter(configuration.GinMode),
+		broadcaster:   websocket.NewWebsocketBroadcaster(originPolicy.AllowsRequest, websocketOptions),
+		eventStream:   sse.NewBroadcaster(eventStreamOptions),
+		sessionCache:  sessionCache,
+		metrics:       registry,
+		origins:       originPolicy,
+		router:        createGinRouter(configuration.GinMode, originPolicy, registry),
 	}
 
+	// Job progress is published to the same clients as all other events
+	server.labelJobs = jobs.NewRegistry(&server, labelJobRetention)
+
+	server.defineGauges()
+
+	server.defineHealthEndpoints()
 	server.defineRESTEndpoints()
-	server.defineWebsocketEndpoint()
+	server.defineEventEndpoints()
 	server.defineStaticFilesRoutes(configuration.HTMLPath)
 
 	return &server
 }
 
+// defineGauges registers the metrics whose values are looked up when metrics are requested.
+func (server *Server) defineGauges() {
+	server.metrics.Gauge("bct_revenue_cents", "Total price of all items sold.", func() (float64, error) {
+		total, err := queries.GetTotalSalesValue(server.database)
+		return float64(total), err
+	})
+
+	server.metrics.Gauge("bct_websocket_subscribers", "Number of clients listening for events over a websocket.", func() (float64, error) {
+		return float64(server.WebsocketSubscriberCount()), nil
+	})
+
+	server.metrics.Gauge("bct_event_stream_subscribers", "Number of clients listening for events over Server-Sent Events.", func() (float64, error) {
+		return float64(server.EventStreamSubscriberCount()), nil
+	})
+}
+
+func (server *Server) defineHealthEndpoints() {
+	server.RawGET(paths.Healthz(), rest.Healthz)
+	server.RawGET(paths.Readyz(), rest.Readyz)
+	server.GET(paths.Metrics(), authorization.ViewMetrics, rest.GetMetrics)
+}
+
 func (server *Server) defineRESTEndpoints() {
 	router := server.router
 
@@ -81,28 +181,163 @@ func (server *Server) defineRESTEndpoints() {
 	server.RawPOST(paths.Login(), rest.Login)
 	server.RawPOST(paths.Logout(), rest.Logout)
 
-	server.GET(paths.Items(), rest.GetAllItems)
-	server.GET(paths.ItemStr(":id"), rest.GetItemInformation)
-	server.PUT(paths.ItemStr(":id"), rest.UpdateItem)
+	server.GET(paths.Items(), authorization.ListItems, rest.GetAllItems)
+	server.GET(paths.ItemStr(":id"), authorization.ViewItem, rest.GetItemInformation)
+	server.GET(paths.BarcodeStr(":payload"), authorization.ViewItem, rest.ResolveBarcode)
+	server.PUT(paths.ItemStr(":id"), authorization.UpdateItem, rest.UpdateItem)
+	server.POST(paths.ItemFreezeStr(":id"), authorization.FreezeItem, rest.FreezeItem)
+	server.POST(paths.ItemUnfreezeStr(":id"), authorization.UnfreezeItem, rest.UnfreezeItem)
+
+	server.GET(paths.Users(), authorization.ListUsers, rest.GetUsers)
+	server.GET(paths.UserStr(":id"), authorization.ViewUser, rest.GetUserInformation)
+	server.DELETE(paths.UserSessionsStr(":id"), authorization.RevokeSessions, rest.RemoveUserSessions)
+
+	server.GET(paths.Sessions(), authorization.ListSessions, rest.GetSessions)
+	server.DELETE(paths.SessionStr(":id"), authorization.RevokeSessions, rest.RemoveSession)
+
+	server.GET(paths.Categories(), authorization.ListCategories, rest.ListCategories)
+
+	server.GET(paths.SellerItemsStr(":id"), authorization.ListSellerItems, rest.GetSellerItems)
+	server.POST(paths.SellerItemsStr(":id"), authorization.AddSellerItem, rest.AddSellerItem)
+
+	server.POST(paths.Labels(), authorization.GenerateLabels, rest.GenerateLabels)
+	server.POST(paths.LabelCalibration(), authorization.CalibrateLabels, rest.GenerateCalibrationPage)
+	server.POST(paths.LabelCheck(), authorization.GenerateLabels, rest.CheckLabels)
+	server.GET(paths.LabelJobs(), authorization.GenerateLabelJobs, rest.GetLabelJobs)
+	server.POST(paths.LabelJobs(), authorization.GenerateLabelJobs, rest.AddLabelJob)
+	server.GET(paths.LabelJobStr(":id"), authorization.GenerateLabelJobs, rest.GetLabelJob)
+	server.GET(paths.LabelJobResultStr(":id"), authorization.GenerateLabelJobs, rest.GetLabelJobResult)
+
+	server.GET(paths.Sales(), authorization.ListSales, rest.GetSales)
+	server.GET(paths.SaleStr(":id"), authorization.ViewSale, rest.GetSaleInformation)
+	server.POST(paths.Sales(), authorization.AddSale, rest.AddSale)
+	server.DELETE(paths.SaleStr(":id"), authorization.VoidSale, rest.RemoveSale)
+	server.GET(paths.CashierSalesStr(":id"), authorization.ListCashierSales, rest.GetCashierSales)
+
+	server.GET(paths.Permissions(), authorization.ViewPermissions, rest.GetPermissions)
+
+	server.GET(paths.ApiTokens(), authorization.ManageApiTokens, rest.GetApiTokens)
+	server.POST(paths.ApiTokens(), authorization.ManageApiTokens, rest.AddApiToken)
+	server.DELETE(paths.ApiTokenStr(":id"), authorization.ManageApiTokens, rest.RemoveApiToken)
+	server.GET(paths.LabelLayouts(), authorization.ListLabelLayouts, rest.GetLabelLayouts)
+	server.POST(paths.LabelLayouts(), authorization.ManageLabelLayouts, rest.AddLabelLayout)
+	server.PUT(paths.LabelLayoutStr(":id"), authorization.ManageLabelLayouts, rest.UpdateLabelLayout)
+	server.DELETE(paths.LabelLayoutStr(":id"), authorization.ManageLabelLayouts, rest.RemoveLabelLayout)
+
+	server.GET(paths.WebsocketClients(), authorization.ViewConnections, rest.GetWebsocketClients)
+}
 
-	server.GET(paths.Users(), rest.GetUsers)
-	server.GET(paths.UserStr(":id"), rest.GetUserInformation)
+// defineEventEndpoints sets up the endpoints over which clients are notified of changes.
+// Websockets are preferred, Server-Sent Events are there for clients behind proxies that do not support them.
+func (server *Server) defineEventEndpoints() {
+	server.router.GET(paths.Websocket().String(), func(context *gin.Context) {
+		if subscription, ok := server.createSubscription(context); ok {
+			server.broadcaster.Subscribe(context, subscription)
+		}
+	})
 
-	server.GET(paths.Categories(), rest.ListCategories)
+	server.router.GET(paths.Events().String(), func(context *gin.Context) {
+		if subscription, ok := server.createSubscription(context); ok {
+			server.eventStream.Subscribe(context, subscription)
+		}
+	})
+}
 
-	server.GET(paths.SellerItemsStr(":id"), rest.GetSellerItems)
-	server.POST(paths.SellerItemsStr(":id"), rest.AddSellerItem)
+// createSubscription determines which events the user is allowed to see:
+// users with access to all data receive all events, others only those concerning their own items or sales.
+// If the user is not allowed to receive events, a failure response is written and false is returned.
+func (server *Server) createSubscription(context *gin.Context) (*events.Subscription, bool) {
+	credentials, ok := server.authenticate(context)
+	if !ok {
+		return nil, false
+	}
 
-	server.POST(paths.Labels(), rest.GenerateLabels)
+	userId := credentials.userId
+	roleId := credentials.roleId
+	action := authorization.ReceiveEvents
+
+	if !credentials.permits(action) {
+		failure_response.ApiTokenOutOfScope(context, fmt.Sprintf("API token is not allowed to perform %s", action))
+		return nil, false
+	}
+
+	scope := authorization.ScopeOf(action, roleId)
+	if scope == authorization.NoScope {
+		failure_response.WrongRole(context, fmt.Sprintf("Role %s is not allowed to perform %s", roleId.Name(), action))
+		return nil, false
+	}
+
+	// Revalidation happens long after the request was handled, but is still logged as part of it
+	logger := logging.FromContext(context)
+
+	subscription := events.Subscription{
+		UserId:    userId,
+		RoleId:    roleId,
+		SessionId: credentials.sessionId,
+		Accepts: func(event *events.Event) bool {
+			if event.IsPrivate() {
+				return event.Concerns(userId)
+			}
+
+			return scope == authorization.AnyScope || event.Concerns(userId)
+		},
+		View: func(event *events.Event) *events.Event {
+			if scope == authorization.AnyScope {
+				return event
+			}
+
+			return event.RestrictedTo(userId)
+		},
+		IsValid: func() bool {
+			return server.areStillValid(logger, credentials)
+		},
+	}
 
-	server.GET(paths.Sales(), rest.GetSales)
-	server.GET(paths.SaleStr(":id"), rest.GetSaleInformation)
-	server.POST(paths.Sales(), rest.AddSale)
-	server.GET(paths.CashierSalesStr(":id"), rest.GetCashierSales)
+	return &subscription, true
 }
 
-func (server *Server) defineWebsocketEndpoint() {
-	server.router.GET(paths.Websocket().String(), server.broadcaster.CreateHandler())
+// Publish sends the event to all clients, regardless of how they are connected.
+func (server *Server) Publish(event *events.Event) {
+	if event.Type == events.SaleCreated {
+		server.metrics.ObserveSaleCreated()
+	}
+
+	server.broadcaster.Publish(event)
+	server.eventStream.Publish(event)
+}
+
+// DisconnectSession closes all event connections opened with the session.
+func (server *Server) DisconnectSession(sessionId models.SessionId) {
+	server.broadcaster.DisconnectSession(sessionId)
+	server.eventStream.DisconnectSession(sessionId)
+}
+
+// DisconnectUserSessions closes all event connections the user opened with a session.
+func (server *Server) DisconnectUserSessions(userId models.Id) {
+	server.broadcaster.DisconnectUserSessions(userId)
+	server.eventStream.DisconnectUserSessions(userId)
+}
+
+// areStillValid checks whether the credentials have expired or have been revoked in the meantime.
+// Unlike authenticate, this does not count as activity, so that an open connection does not keep a session alive.
+func (server *Server) areStillValid(logger *slog.Logger, credentials *credentials) bool {
+	var err error
+	if credentials.sessionId != "" {
+		_, err = queries.GetSessionData(server.database, credentials.sessionId)
+	} else {
+		_, err = queries.GetApiTokenData(server.database, credentials.apiToken)
+	}
+
+	if errors.Is(err, dberr.ErrNoSuchSession) || errors.Is(err, dberr.ErrNoSuchApiToken) {
+		return false
+	}
+
+	if err != nil {
+		// Give the benefit of the doubt, the next check might succeed
+		logger.Error("Failed to check validity of credentials", slog.String("error", err.Error()))
+	}
+
+	return true
 }
 
 func (server *Server) defineStaticFilesRoutes(htmlPath string) {
@@ -111,41 +346,147 @@ func (server *Server) defineStaticFilesRoutes(htmlPath string) {
 	})
 }
 
-func (server *Server) RawPOST(path *paths.URL, handler func(context *gin.Context, database *sql.DB)) {
-	server.router.POST(path.String(), func(context *gin.Context) { handler(context, server.database) })
+func (server *Server) RawGET(path *paths.URL, handler func(context *gin.Context, configuration *configuration.Configuration, database *sql.DB)) {
+	server.router.GET(path.String(), func(context *gin.Context) {
+		handler(context, server.configuration, server.database)
+	})
 }
 
-func (server *Server) GET(path *paths.URL, handler HandlerFunction) {
-	server.router.GET(path.String(), server.withUserAndRole(handler, false))
+func (server *Server) RawPOST(path *paths.URL, handler func(context *gin.Context, configuration *configuration.Configuration, database *sql.DB)) {
+	server.router.POST(path.String(), func(context *gin.Context) {
+		if server.sessionCache != nil {
+			sessions.StoreInContext(context, server.sessionCache)
+		}
+		sessions.StoreConnectionsInContext(context, server)
+
+		handler(context, server.configuration, server.database)
+	})
 }
 
-func (server *Server) POST(path *paths.URL, handler HandlerFunction) {
-	server.router.POST(path.String(), server.withUserAndRole(handler, true))
+func (server *Server) GET(path *paths.URL, action authorization.Action, handler HandlerFunction) {
+	server.router.GET(path.String(), server.withUserAndRole(action, handler, false))
 }
 
-func (server *Server) PUT(path *paths.URL, handler HandlerFunction) {
-	server.router.PUT(path.String(), server.withUserAndRole(handler, true))
+func (server *Server) POST(path *paths.URL, action authorization.Action, handler HandlerFunction) {
+	server.router.POST(path.String(), server.withUserAndRole(action, handler, true))
 }
 
-func (server *Server) run() error {
-	address := fmt.Sprintf("localhost:%d", server.configuration.Port)
+func (server *Server) PUT(path *paths.URL, action authorization.Action, handler HandlerFunction) {
+	server.router.PUT(path.String(), server.withUserAndRole(action, handler, true))
+}
 
-	if err := server.router.Run(address); err != nil {
+func (server *Server) DELETE(path *paths.URL, action authorization.Action, handler HandlerFunction) {
+	server.router.DELETE(path.String(), server.withUserAndRole(action, handler, true))
+}
+
+func (server *Server) run(ctx context.Context) error {
+	address := net.JoinHostPort(server.configuration.BindAddress, strconv.Itoa(server.configuration.Port))
+
+	if server.configuration.TLSEnabled() {
+		if err := ensureCertificateExists(server.configuration); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if server.configuration.TLSEnabled() {
+		certificate, err := tls.LoadX509KeyPair(server.configuration.TLSCertificatePath, server.configuration.TLSKeyPath)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{certificate}})
+		slog.Info("Listening for HTTPS requests", slog.String("address", address))
+	} else {
+		slog.Info("Listening for HTTP requests", slog.String("address", address))
+	}
+
+	return server.Serve(ctx, listener)
 }
 
-func createGinRouter(ginMode string) *gin.Engine {
+// Serve handles requests arriving on the listener until ctx is cancelled, after which the server shuts down gracefully:
+// no new connections are accepted, in-flight requests are given shutdownTimeout to finish,
+// event subscribers are disconnected, label jobs are cancelled and given shutdownTimeout to stop,
+// pending session activity is written to the database and the write-ahead log is checkpointed.
+// Closing the database is left to the caller.
+func (server *Server) Serve(ctx context.Context, listener net.Listener) error {
+	if server.sessionCache != nil {
+		server.sessionCache.Start(activityFlushInterval)
+	}
+
+	janitor := sessions.NewJanitor(server.database, server.sessionCache, security.SessionCleanupIntervalInSeconds*time.Second)
+	janitor.Start()
+
+	httpServer := &http.Server{Handler: server.router}
+	serveResult := make(chan error, 1)
+	go func() {
+		serveResult <- httpServer.Serve(listener)
+	}()
+
+	var serveError error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	case serveError = <-serveResult:
+		slog.Error("Server stopped unexpectedly", slog.String("error", serveError.Error()))
+	}
+
+	// Event streams never end by themselves, so they must be closed before waiting for in-flight requests
+	server.eventStream.Close()
+
+	shutdownContext, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownContext); err != nil {
+		slog.Error("Not all requests finished in time", slog.String("error", err.Error()))
+	}
+
+	// Websocket connections are hijacked and therefore not tracked by httpServer
+	server.broadcaster.Close()
+
+	janitor.Stop()
+
+	// Jobs may still be writing to the database, e.g., to freeze the items they generated labels for
+	if !server.labelJobs.Shutdown(shutdownTimeout) {
+		slog.Error("Not all label jobs stopped in time")
+	}
+
+	if server.sessionCache != nil {
+		if err := server.sessionCache.Stop(); err != nil {
+			slog.Error("Failed to flush session activity", slog.String("error", err.Error()))
+		}
+	}
+
+	if err := database.Checkpoint(server.database); err != nil {
+		slog.Error("Failed to checkpoint database", slog.String("error", err.Error()))
+	}
+
+	return serveError
+}
+
+func createGinRouter(ginMode string, originPolicy *origins.Policy, registry *metrics.Registry) *gin.Engine {
 	gin.SetMode(ginMode)
 
-	router := gin.Default()
+	// gin's own logger is replaced by one that writes to the configured log
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(logging.Middleware())
+
+	// Registered before CORS so that requests rejected by it are counted too
+	router.Use(registry.Middleware())
 
 	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	// config.AllowOrigins = []string{"http://localhost:5173"}
+	if originPolicy.AllowsAll() {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOriginFunc = originPolicy.Allows
+	}
 	config.AllowCredentials = true
+	config.AddAllowHeaders("Authorization", security.CSRFHeaderName)
 
 	router.Use(cors.New(config))
 
@@ -154,49 +495,199 @@ func createGinRouter(ginMode string) *gin.Engine {
 
 type HandlerFunction func(context *gin.Context, configuration *configuration.Configuration, db *sql.DB, userId models.Id, roleId models.RoleId)
 
-func (server *Server) withUserAndRole(handler HandlerFunction, mutates bool) gin.HandlerFunc {
+// withUserAndRole looks up the logged in user and checks whether their role is allowed to perform the action
+// before passing control to the handler. Whether the action is allowed on the specific resource
+// the request refers to can only be determined by the handler itself (see authorization.IsAllowedOn).
+func (server *Server) withUserAndRole(action authorization.Action, handler HandlerFunction, mutates bool) gin.HandlerFunc {
 	db := server.database
 	configuration := server.configuration
 	broadcaster := server.broadcaster
 
 	return func(context *gin.Context) {
-		sessionIdString, err := context.Cookie(security.SessionCookieName)
-		if err != nil {
-			slog.Error("Unauthorized: missing session ID")
-			failure_response.MissingSessionId(context, err.Error())
+		credentials, ok := server.authenticate(context)
+		if !ok {
 			return
 		}
 
-		sessionId := models.SessionId(sessionIdString)
-		sessionData, err := queries.GetSessionData(db, sessionId)
+		userId := credentials.userId
+		roleId := credentials.roleId
+		logging.WithUser(context, userId, roleId)
+		logger := logging.FromContext(context)
 
-		if errors.Is(err, dberr.ErrNoSuchSession) {
-			slog.Error("Session not found")
-			failure_response.NoSuchSession(context, err.Error())
+		if mutates && !credentials.hasValidCSRFToken(context) {
+			logger.Info("Mutating request without valid CSRF token", slog.String("action", string(action)))
+			failure_response.InvalidCSRFToken(context, fmt.Sprintf("Missing or invalid %s header", security.CSRFHeaderName))
 			return
 		}
 
-		if err != nil {
-			slog.Error("Failed to retrieve session from database", slog.String("error", err.Error()))
-			failure_response.Unknown(context, "Failed to retrieve session from database: "+err.Error())
+		if !credentials.permits(action) {
+			logger.Info("API token not allowed to perform action", slog.String("action", string(action)))
+			failure_response.ApiTokenOutOfScope(context, fmt.Sprintf("API token is not allowed to perform %s", action))
 			return
 		}
 
-		userId := sessionData.UserId
-		roleId := sessionData.RoleId
+		if !authorization.IsAllowed(action, roleId) {
+			logger.Info("Role not allowed to perform action", slog.String("action", string(action)))
+			failure_response.WrongRole(context, fmt.Sprintf("Role %s is not allowed to perform %s", roleId.Name(), action))
+			return
+		}
 
-		now := models.Now()
-		if err := queries.UpdateLastActivity(db, userId, now); err != nil {
-			slog.Error("Failed to update last activity", slog.String("error", err.Error()))
-			// Keep going, we don't want to block the request
+		if server.sessionCache != nil {
+			sessions.StoreInContext(context, server.sessionCache)
 		}
+		sessions.StoreConnectionsInContext(context, server)
+		events.StoreInContext(context, server)
+		websocket.StoreInContext(context, broadcaster)
+		metrics.StoreInContext(context, server.metrics)
+		jobs.StoreInContext(context, server.labelJobs)
 
 		handler(context, configuration, db, userId, roleId)
+	}
+}
 
-		if mutates {
-			broadcaster.Broadcast("update")
-		}
+type credentials struct {
+	userId models.Id
+	roleId models.RoleId
+
+	// Session the request belongs to; empty for API tokens
+	sessionId models.SessionId
+
+	// API token the request was authenticated with; empty for sessions
+	apiToken string
+
+	// Actions an API token is restricted to; nil for sessions and unrestricted tokens
+	tokenActions []string
+}
+
+func (c *credentials) permits(action authorization.Action) bool {
+	return len(c.tokenActions) == 0 || slices.Contains(c.tokenActions, string(action))
+}
+
+// hasValidCSRFToken checks the CSRF token of cookie-authenticated requests.
+// Browsers never attach API tokens on their own, so requests authenticated by one cannot be forged cross-site.
+func (c *credentials) hasValidCSRFToken(context *gin.Context) bool {
+	if c.sessionId == "" {
+		return true
+	}
+
+	return security.IsValidCSRFToken(c.sessionId, context.GetHeader(security.CSRFHeaderName))
+}
+
+// authenticate determines who is making the request.
+// Non-browser clients pass an API token in the Authorization header, browsers rely on the session cookie.
+// If authentication fails, a failure response is written and false is returned.
+func (server *Server) authenticate(context *gin.Context) (*credentials, bool) {
+	if authorizationHeader := context.GetHeader("Authorization"); authorizationHeader != "" {
+		return server.authenticateWithApiToken(context, authorizationHeader)
+	}
+
+	return server.authenticateWithSession(context)
+}
+
+func (server *Server) authenticateWithApiToken(context *gin.Context, authorizationHeader string) (*credentials, bool) {
+	logger := logging.FromContext(context)
+
+	token, found := strings.CutPrefix(authorizationHeader, "Bearer ")
+	if !found {
+		logger.Error("Unauthorized: unsupported authorization scheme")
+		failure_response.InvalidApiToken(context, "Only bearer tokens are supported")
+		return nil, false
 	}
+
+	tokenData, err := queries.GetApiTokenData(server.database, token)
+
+	if errors.Is(err, dberr.ErrNoSuchApiToken) {
+		logger.Error("API token not found")
+		failure_response.InvalidApiToken(context, err.Error())
+		return nil, false
+	}
+
+	if err != nil {
+		logger.Error("Failed to retrieve API token from database", slog.String("error", err.Error()))
+		failure_response.UnknownError(context, fmt.Errorf("Failed to retrieve API token from database: %w", err))
+		return nil, false
+	}
+
+	return &credentials{userId: tokenData.UserId, roleId: tokenData.RoleId, apiToken: token, tokenActions: tokenData.Actions}, true
+}
+
+func (server *Server) authenticateWithSession(context *gin.Context) (*credentials, bool) {
+	logger := logging.FromContext(context)
+
+	sessionIdString, err := context.Cookie(security.SessionCookieName)
+	if err != nil {
+		logger.Error("Unauthorized: missing session ID")
+		failure_response.MissingSessionId(context, err.Error())
+		return nil, false
+	}
+
+	sessionId := models.SessionId(sessionIdString)
+	sessionData, err := server.lookupSession(logger, sessionId)
+
+	if errors.Is(err, dberr.ErrNoSuchSession) {
+		logger.Error("Session not found")
+		failure_response.NoSuchSession(context, err.Error())
+		return nil, false
+	}
+
+	if err != nil {
+		logger.Error("Failed to retrieve session from database", slog.String("error", err.Error()))
+		failure_response.UnknownError(context, fmt.Errorf("Failed to retrieve session from database: %w", err))
+		return nil, false
+	}
+
+	return &credentials{userId: sessionData.UserId, roleId: sessionData.RoleId, sessionId: sessionId, tokenActions: nil}, true
+}
+
+// lookupSession retrieves the session data and registers activity on the session.
+// If the session cache is disabled, this means one read and two writes to the database per request.
+func (server *Server) lookupSession(logger *slog.Logger, sessionId models.SessionId) (*queries.SessionData, error) {
+	if server.sessionCache != nil {
+		return server.sessionCache.Lookup(sessionId)
+	}
+
+	db := server.database
+	sessionData, err := queries.GetSessionData(db, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := models.Now()
+	if err := queries.RenewSession(db, sessionId, now+security.SessionIdleTimeoutInSeconds, security.SessionDurationInSeconds); err != nil {
+		logger.Error("Failed to renew session", slog.String("error", err.Error()))
+		// Keep going, the session is still valid for now
+	}
+
+	if err := queries.UpdateLastActivity(db, sessionData.UserId, now); err != nil {
+		logger.Error("Failed to update last activity", slog.String("error", err.Error()))
+		// Keep going, we don't want to block the request
+	}
+
+	return sessionData, nil
+}
+
+// FlushSessionActivity writes pending session renewals and last activity timestamps to the database.
+func (server *Server) FlushSessionActivity() error {
+	if server.sessionCache == nil {
+		return nil
+	}
+
+	return server.sessionCache.Flush()
+}
+
+// WaitForJobs blocks until all background jobs have finished.
+func (server *Server) WaitForJobs() {
+	server.labelJobs.Wait()
+}
+
+// WebsocketSubscriberCount returns the number of clients listening for events over a websocket.
+func (server *Server) WebsocketSubscriberCount() int {
+	return server.broadcaster.SubscriberCount()
+}
+
+// EventStreamSubscriberCount returns the number of clients listening for Server-Sent Events.
+func (server *Server) EventStreamSubscriberCount() int {
+	return server.eventStream.SubscriberCount()
 }
 
 func (server *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -0,0 +1,243 @@
+package sessions
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/security"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Cache keeps recently validated sessions in memory so that authenticated requests
+// do not need to hit the database for every request.
+//
+// Each lookup slides the expiration time of the session forward and records the
+// user's last activity. These writes are coalesced in memory and periodically flushed
+// in a single transaction, which keeps SQLite write contention down when many
+// requests come in at once.
+//
+// Sessions that are revoked in-process must be invalidated explicitly
+// (see InvalidateSession and InvalidateUserSessions). Sessions revoked by another
+// process, e.g., the command line interface, are picked up once the cached entry
+// needs to be revalidated, i.e., after timeToLive.
+type Cache struct {
+	database   *sql.DB
+	timeToLive time.Duration
+
+	mutex                  sync.Mutex
+	entries                map[models.SessionId]*cacheEntry
+	pendingExpirationTimes map[models.SessionId]models.Timestamp
+	pendingLastActivities  map[models.Id]models.Timestamp
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+type cacheEntry struct {
+	sessionData queries.SessionData
+	validatedAt time.Time
+}
+
+func NewCache(db *sql.DB, timeToLive time.Duration) *Cache {
+	return &Cache{
+		database:               db,
+		timeToLive:             timeToLive,
+		entries:                make(map[models.SessionId]*cacheEntry),
+		pendingExpirationTimes: make(map[models.SessionId]models.Timestamp),
+		pendingLastActivities:  make(map[models.Id]models.Timestamp),
+		done:                   nil,
+		stopped:                nil,
+	}
+}
+
+// Lookup returns the data associated with the given session and registers activity on it.
+// An ErrNoSuchSession is returned if the session does not exist or has expired.
+func (cache *Cache) Lookup(sessionId models.SessionId) (*queries.SessionData, error) {
+	now := models.Now()
+
+	if sessionData, found := cache.lookupInMemory(sessionId, now); found {
+		if sessionData == nil {
+			return nil, dberr.ErrNoSuchSession
+		}
+		return sessionData, nil
+	}
+
+	sessionData, err := queries.GetSessionData(cache.database, sessionId)
+	if err != nil {
+		if errors.Is(err, dberr.ErrNoSuchSession) {
+			cache.InvalidateSession(sessionId)
+		}
+		return nil, err
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	// A pending renewal can be more recent than what is stored in the database
+	if pendingExpirationTime, ok := cache.pendingExpirationTimes[sessionId]; ok && pendingExpirationTime > sessionData.ExpirationTime {
+		sessionData.ExpirationTime = pendingExpirationTime
+	}
+
+	entry := &cacheEntry{
+		sessionData: *sessionData,
+		validatedAt: time.Now(),
+	}
+	cache.entries[sessionId] = entry
+	cache.registerActivity(sessionId, entry, now)
+
+	result := entry.sessionData
+	return &result, nil
+}
+
+// lookupInMemory looks for the session in the cache.
+// The second return value indicates whether the cache could answer the question.
+// If so, a nil session data means that the session has expired.
+func (cache *Cache) lookupInMemory(sessionId models.SessionId, now models.Timestamp) (*queries.SessionData, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[sessionId]
+	if !ok || time.Since(entry.validatedAt) >= cache.timeToLive {
+		return nil, false
+	}
+
+	if entry.sessionData.ExpirationTime <= now {
+		delete(cache.entries, sessionId)
+		return nil, true
+	}
+
+	cache.registerActivity(sessionId, entry, now)
+
+	result := entry.sessionData
+	return &result, true
+}
+
+// registerActivity slides the expiration time of the session forward and records the user's last activity.
+// Must be called while holding the mutex.
+func (cache *Cache) registerActivity(sessionId models.SessionId, entry *cacheEntry, now models.Timestamp) {
+	sessionData := &entry.sessionData
+
+	expirationTime := min(now+security.SessionIdleTimeoutInSeconds, sessionData.CreatedAt+security.SessionDurationInSeconds)
+	if expirationTime > sessionData.ExpirationTime {
+		sessionData.ExpirationTime = expirationTime
+		cache.pendingExpirationTimes[sessionId] = expirationTime
+	}
+
+	cache.pendingLastActivities[sessionData.UserId] = now
+}
+
+// InvalidateSession removes the session from the cache.
+// Must be called whenever a session is removed from the database.
+func (cache *Cache) InvalidateSession(sessionId models.SessionId) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.entries, sessionId)
+	delete(cache.pendingExpirationTimes, sessionId)
+}
+
+// InvalidateUserSessions removes all sessions of the given user from the cache.
+func (cache *Cache) InvalidateUserSessions(userId models.Id) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for sessionId, entry := range cache.entries {
+		if entry.sessionData.UserId == userId {
+			delete(cache.entries, sessionId)
+			delete(cache.pendingExpirationTimes, sessionId)
+		}
+	}
+}
+
+// RemoveExpired drops all cached sessions that expired before the cut-off.
+// Without it, sessions that are never looked up again would stay in memory indefinitely.
+func (cache *Cache) RemoveExpired(cutOff models.Timestamp) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for sessionId, entry := range cache.entries {
+		if entry.sessionData.ExpirationTime <= cutOff {
+			delete(cache.entries, sessionId)
+			delete(cache.pendingExpirationTimes, sessionId)
+		}
+	}
+}
+
+// Flush writes all pending session renewals and last activity timestamps to the database.
+// If writing fails, the pending updates are kept so that they can be retried later.
+func (cache *Cache) Flush() error {
+	cache.mutex.Lock()
+	expirationTimes := cache.pendingExpirationTimes
+	lastActivities := cache.pendingLastActivities
+	cache.pendingExpirationTimes = make(map[models.SessionId]models.Timestamp)
+	cache.pendingLastActivities = make(map[models.Id]models.Timestamp)
+	cache.mutex.Unlock()
+
+	if len(expirationTimes) == 0 && len(lastActivities) == 0 {
+		return nil
+	}
+
+	if err := queries.UpdateActivity(cache.database, expirationTimes, lastActivities); err != nil {
+		cache.restorePending(expirationTimes, lastActivities)
+		return err
+	}
+
+	return nil
+}
+
+func (cache *Cache) restorePending(expirationTimes map[models.SessionId]models.Timestamp, lastActivities map[models.Id]models.Timestamp) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for sessionId, expirationTime := range expirationTimes {
+		if _, ok := cache.entries[sessionId]; !ok {
+			// Session was invalidated in the meantime
+			continue
+		}
+
+		cache.pendingExpirationTimes[sessionId] = max(cache.pendingExpirationTimes[sessionId], expirationTime)
+	}
+
+	for userId, lastActivity := range lastActivities {
+		cache.pendingLastActivities[userId] = max(cache.pendingLastActivities[userId], lastActivity)
+	}
+}
+
+// Start launches a goroutine that flushes pending updates every flushInterval.
+func (cache *Cache) Start(flushInterval time.Duration) {
+	cache.done = make(chan struct{})
+	cache.stopped = make(chan struct{})
+
+	go func() {
+		defer close(cache.stopped)
+
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := cache.Flush(); err != nil {
+					slog.Error("Failed to flush session activity", slog.String("error", err.Error()))
+				}
+			case <-cache.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the flushing goroutine and writes out whatever is still pending.
+func (cache *Cache) Stop() error {
+	if cache.done != nil {
+		close(cache.done)
+		<-cache.stopped
+		cache.done = nil
+	}
+
+	return cache.Flush()
+}
@@ -0,0 +1,73 @@
+package sessions
+
+import (
+	"bctbackend/database/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	cacheContextKey       = "bct_session_cache"
+	connectionsContextKey = "bct_session_connections"
+)
+
+// Connections keeps long-lived connections open on behalf of sessions,
+// which need to be closed when their session is revoked.
+type Connections interface {
+	DisconnectSession(sessionId models.SessionId)
+	DisconnectUserSessions(userId models.Id)
+}
+
+// StoreInContext makes the cache available to request handlers
+// so that they can invalidate sessions they remove.
+func StoreInContext(context *gin.Context, cache *Cache) {
+	context.Set(cacheContextKey, cache)
+}
+
+// StoreConnectionsInContext makes the open connections available to request handlers
+// so that revoking a session also closes its connections.
+func StoreConnectionsInContext(context *gin.Context, connections Connections) {
+	context.Set(connectionsContextKey, connections)
+}
+
+func fromContext(context *gin.Context) *Cache {
+	value, exists := context.Get(cacheContextKey)
+	if !exists {
+		return nil
+	}
+
+	return value.(*Cache)
+}
+
+func connectionsFromContext(context *gin.Context) Connections {
+	value, exists := context.Get(connectionsContextKey)
+	if !exists {
+		return nil
+	}
+
+	return value.(Connections)
+}
+
+// InvalidateSession removes the session from the cache associated with the request, if any,
+// and closes the connections that were opened with it.
+func InvalidateSession(context *gin.Context, sessionId models.SessionId) {
+	if cache := fromContext(context); cache != nil {
+		cache.InvalidateSession(sessionId)
+	}
+
+	if connections := connectionsFromContext(context); connections != nil {
+		connections.DisconnectSession(sessionId)
+	}
+}
+
+// InvalidateUserSessions removes all sessions of the user from the cache associated with the request, if any,
+// and closes the connections that were opened with them.
+func InvalidateUserSessions(context *gin.Context, userId models.Id) {
+	if cache := fromContext(context); cache != nil {
+		cache.InvalidateUserSessions(userId)
+	}
+
+	if connections := connectionsFromContext(context); connections != nil {
+		connections.DisconnectUserSessions(userId)
+	}
+}
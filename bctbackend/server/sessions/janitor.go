@@ -0,0 +1,70 @@
+package sessions
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// Janitor periodically removes expired sessions from the database and from the cache.
+// Without it, the sessions table would keep growing as expired sessions are never cleaned up.
+type Janitor struct {
+	database *sql.DB
+	cache    *Cache
+	interval time.Duration
+	done     chan struct{}
+}
+
+// NewJanitor creates a janitor that purges expired sessions every interval.
+// If cache is not nil, it is flushed before each purge so that sessions
+// that were renewed in memory are not mistaken for expired ones.
+func NewJanitor(db *sql.DB, cache *Cache, interval time.Duration) *Janitor {
+	return &Janitor{
+		database: db,
+		cache:    cache,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+func (janitor *Janitor) Start() {
+	go func() {
+		ticker := time.NewTicker(janitor.interval)
+		defer ticker.Stop()
+
+		janitor.purge()
+
+		for {
+			select {
+			case <-ticker.C:
+				janitor.purge()
+			case <-janitor.done:
+				return
+			}
+		}
+	}()
+}
+
+func (janitor *Janitor) Stop() {
+	close(janitor.done)
+}
+
+func (janitor *Janitor) purge() {
+	now := models.Now()
+
+	if janitor.cache != nil {
+		if err := janitor.cache.Flush(); err != nil {
+			slog.Error("Failed to flush session activity", slog.String("error", err.Error()))
+		}
+
+		janitor.cache.RemoveExpired(now)
+	}
+
+	slog.Debug("Removing expired sessions")
+
+	if err := queries.DeleteExpiredSessions(janitor.database, now); err != nil {
+		slog.Error("Failed to remove expired sessions", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,313 @@
+package sse
+
+import (
+	"bctbackend/database/models"
+	"bctbackend/server/events"
+	"bctbackend/server/failure_response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrClosed = errors.New("event stream is closed")
+
+const (
+	// Name of the event sent to clients that resume from an event that is no longer remembered.
+	// Such clients have missed events and must refetch their data.
+	ResetEventName = "reset"
+)
+
+type Options struct {
+	// Number of past events remembered so that reconnecting clients can catch up
+	HistorySize int
+
+	// Number of events that can be waiting to be sent to a single subscriber.
+	// Subscribers that fall further behind are disconnected; they can resume where they left off by reconnecting.
+	QueueSize int
+
+	// How often a comment is sent to keep proxies from closing idle connections
+	HeartbeatInterval time.Duration
+
+	// How often subscriptions are checked for expired or revoked credentials
+	RevalidationInterval time.Duration
+}
+
+// Broadcaster delivers events to clients over Server-Sent Events.
+// Every event is numbered so that clients that reconnect can pass the last id they received
+// in the Last-Event-ID header and receive the events they missed.
+// Numbering restarts with the process, so ids are prefixed with an epoch identifying the broadcaster:
+// an id with another epoch refers to events that were never remembered by this broadcaster.
+type Broadcaster struct {
+	options Options
+	epoch   string
+
+	mutex       sync.Mutex
+	closed      bool
+	lastEventId uint64
+	history     []*entry
+	subscribers map[*subscriber]struct{}
+}
+
+// eventId identifies an event as <epoch>-<sequence number>.
+type eventId struct {
+	epoch    string
+	sequence uint64
+}
+
+type entry struct {
+	id    uint64
+	event *events.Event
+	data  []byte
+}
+
+type subscriber struct {
+	subscription *events.Subscription
+	queue        chan *entry
+	closed       chan struct{}
+	closeOnce    sync.Once
+}
+
+func (s *subscriber) close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func NewBroadcaster(options Options) *Broadcaster {
+	return &Broadcaster{
+		options:     options,
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 10),
+		lastEventId: 0,
+		history:     make([]*entry, 0, options.HistorySize),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish numbers the event, remembers it and queues it for all subscribers that accept it.
+func (broadcaster *Broadcaster) Publish(event *events.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to serialize event", slog.String("type", string(event.Type)), slog.String("error", err.Error()))
+		return
+	}
+
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.lastEventId++
+	entry := &entry{id: broadcaster.lastEventId, event: event, data: data}
+
+	if len(broadcaster.history) == broadcaster.options.HistorySize {
+		broadcaster.history = append(broadcaster.history[1:], entry)
+	} else {
+		broadcaster.history = append(broadcaster.history, entry)
+	}
+
+	for subscriber := range broadcaster.subscribers {
+		if !subscriber.subscription.Accepts(event) {
+			continue
+		}
+
+		select {
+		case subscriber.queue <- entry:
+		default:
+			slog.Warn("Disconnecting slow event stream subscriber", slog.Int64("user_id", subscriber.subscription.UserId.Int64()))
+			broadcaster.removeSubscriber(subscriber)
+		}
+	}
+}
+
+// DisconnectSession closes all streams opened with the given session.
+func (broadcaster *Broadcaster) DisconnectSession(sessionId models.SessionId) {
+	broadcaster.disconnect(func(subscription *events.Subscription) bool {
+		return subscription.SessionId == sessionId
+	})
+}
+
+// DisconnectUserSessions closes all streams the user opened with a session.
+func (broadcaster *Broadcaster) DisconnectUserSessions(userId models.Id) {
+	broadcaster.disconnect(func(subscription *events.Subscription) bool {
+		return subscription.SessionId != "" && subscription.UserId == userId
+	})
+}
+
+// Close ends all streams and refuses new subscriptions.
+func (broadcaster *Broadcaster) Close() {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.closed = true
+	for subscriber := range broadcaster.subscribers {
+		broadcaster.removeSubscriber(subscriber)
+	}
+}
+
+// SubscriberCount returns the number of currently connected subscribers.
+func (broadcaster *Broadcaster) SubscriberCount() int {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	return len(broadcaster.subscribers)
+}
+
+func (broadcaster *Broadcaster) disconnect(matches func(subscription *events.Subscription) bool) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	for subscriber := range broadcaster.subscribers {
+		if matches(subscriber.subscription) {
+			broadcaster.removeSubscriber(subscriber)
+		}
+	}
+}
+
+// removeSubscriber must be called with the mutex held.
+func (broadcaster *Broadcaster) removeSubscriber(subscriber *subscriber) {
+	delete(broadcaster.subscribers, subscriber)
+	subscriber.close()
+}
+
+// addSubscriber registers the subscriber and returns the remembered events it missed.
+// The second return value is false if events were missed that are no longer remembered.
+// Returns ErrClosed if the broadcaster no longer accepts subscribers.
+func (broadcaster *Broadcaster) addSubscriber(subscriber *subscriber, lastEventId *eventId) ([]*entry, bool, error) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	if broadcaster.closed {
+		return nil, false, ErrClosed
+	}
+
+	broadcaster.subscribers[subscriber] = struct{}{}
+
+	if lastEventId == nil {
+		return nil, true, nil
+	}
+
+	// The client received its events from before a restart of the server
+	if lastEventId.epoch != broadcaster.epoch || lastEventId.sequence > broadcaster.lastEventId {
+		return nil, false, nil
+	}
+
+	if lastEventId.sequence == broadcaster.lastEventId {
+		return nil, true, nil
+	}
+
+	missed := []*entry{}
+	for _, entry := range broadcaster.history {
+		if entry.id > lastEventId.sequence && subscriber.subscription.Accepts(entry.event) {
+			missed = append(missed, entry)
+		}
+	}
+
+	complete := len(broadcaster.history) > 0 && broadcaster.history[0].id <= lastEventId.sequence+1
+	return missed, complete, nil
+}
+
+func (broadcaster *Broadcaster) unsubscribe(subscriber *subscriber) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.removeSubscriber(subscriber)
+}
+
+// Subscribe streams the events accepted by the subscription until the client disconnects
+// or the subscription is ended.
+func (broadcaster *Broadcaster) Subscribe(context *gin.Context, subscription *events.Subscription) {
+	subscriber := &subscriber{
+		subscription: subscription,
+		queue:        make(chan *entry, broadcaster.options.QueueSize),
+		closed:       make(chan struct{}),
+	}
+
+	missed, complete, err := broadcaster.addSubscriber(subscriber, parseLastEventId(context.GetHeader("Last-Event-ID")))
+	if err != nil {
+		failure_response.ShuttingDown(context, err.Error())
+		return
+	}
+	defer broadcaster.unsubscribe(subscriber)
+
+	context.Header("Content-Type", "text/event-stream")
+	context.Header("Cache-Control", "no-cache")
+	context.Header("Connection", "keep-alive")
+	context.Header("X-Accel-Buffering", "no")
+	context.Status(200)
+
+	if !complete {
+		fmt.Fprintf(context.Writer, "event: %s\ndata: {}\n\n", ResetEventName)
+	}
+	for _, entry := range missed {
+		broadcaster.writeEntry(context, subscription, entry)
+	}
+	context.Writer.Flush()
+
+	heartbeatTicker := time.NewTicker(broadcaster.options.HeartbeatInterval)
+	defer heartbeatTicker.Stop()
+	revalidationTicker := time.NewTicker(broadcaster.options.RevalidationInterval)
+	defer revalidationTicker.Stop()
+
+	for {
+		select {
+		case entry := <-subscriber.queue:
+			broadcaster.writeEntry(context, subscription, entry)
+			context.Writer.Flush()
+
+		case <-heartbeatTicker.C:
+			fmt.Fprint(context.Writer, ": heartbeat\n\n")
+			context.Writer.Flush()
+
+		case <-revalidationTicker.C:
+			if !subscription.IsValid() {
+				slog.Info("Closing event stream of expired or revoked credentials", slog.Int64("user_id", subscription.UserId.Int64()))
+				return
+			}
+
+		case <-subscriber.closed:
+			return
+
+		case <-context.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEntry writes the event as seen by the subscriber, which is only serialized anew if it differs from what others see.
+func (broadcaster *Broadcaster) writeEntry(context *gin.Context, subscription *events.Subscription, entry *entry) {
+	data := entry.data
+	if view := subscription.View(entry.event); view != entry.event {
+		serializedView, err := json.Marshal(view)
+		if err != nil {
+			slog.Error("Failed to serialize event", slog.String("type", string(view.Type)), slog.String("error", err.Error()))
+			return
+		}
+		data = serializedView
+	}
+
+	fmt.Fprintf(context.Writer, "id: %s-%d\ndata: %s\n\n", broadcaster.epoch, entry.id, data)
+}
+
+// parseLastEventId parses an id of the form <epoch>-<sequence number>.
+// A bare sequence number is accepted as an id without epoch, which never matches the current one.
+func parseLastEventId(header string) *eventId {
+	if header == "" {
+		return nil
+	}
+
+	epoch, sequenceString, found := strings.Cut(header, "-")
+	if !found {
+		epoch, sequenceString = "", header
+	}
+
+	sequence, err := strconv.ParseUint(sequenceString, 10, 64)
+	if err != nil {
+		slog.Info("Ignoring invalid Last-Event-ID", slog.String("last_event_id", header))
+		return nil
+	}
+
+	return &eventId{epoch: epoch, sequence: sequence}
+}
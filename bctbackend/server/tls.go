@@ -0,0 +1,70 @@
+package server
+
+import (
+	"bctbackend/algorithms"
+	"bctbackend/security"
+	"bctbackend/server/configuration"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+)
+
+// ensureCertificateExists generates a self-signed certificate if asked to and none exists yet.
+func ensureCertificateExists(configuration *configuration.Configuration) error {
+	if !configuration.GenerateSelfSignedCertificate {
+		return nil
+	}
+
+	certificateExists, err := algorithms.FileExists(configuration.TLSCertificatePath)
+	if err != nil {
+		return fmt.Errorf("failed to check existence of certificate: %w", err)
+	}
+
+	if certificateExists {
+		return nil
+	}
+
+	hosts := certificateHosts(configuration.BindAddress)
+	slog.Info("Generating self-signed certificate", slog.String("path", configuration.TLSCertificatePath), slog.Any("hosts", hosts))
+
+	if err := security.GenerateSelfSignedCertificate(configuration.TLSCertificatePath, configuration.TLSKeyPath, hosts); err != nil {
+		return fmt.Errorf("failed to generate self-signed certificate: %w", err)
+	}
+
+	return nil
+}
+
+// certificateHosts lists the names and addresses under which clients can reach the server.
+// When bound to all interfaces, this includes the addresses of all network interfaces,
+// so that devices on the local network can connect by IP.
+func certificateHosts(bindAddress string) []string {
+	hosts := []string{"localhost"}
+
+	if hostname, err := os.Hostname(); err == nil {
+		hosts = append(hosts, hostname)
+	}
+
+	bindIp := net.ParseIP(bindAddress)
+	if bindAddress != "" && bindAddress != "localhost" && (bindIp == nil || !bindIp.IsUnspecified()) {
+		return append(hosts, bindAddress)
+	}
+
+	if bindAddress == "localhost" {
+		return append(hosts, "127.0.0.1", "::1")
+	}
+
+	addresses, err := net.InterfaceAddrs()
+	if err != nil {
+		slog.Error("Failed to list network interfaces", slog.String("error", err.Error()))
+		return hosts
+	}
+
+	for _, address := range addresses {
+		if ipNetwork, ok := address.(*net.IPNet); ok {
+			hosts = append(hosts, ipNetwork.IP.String())
+		}
+	}
+
+	return hosts
+}
@@ -0,0 +1,22 @@
+package websocket
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const broadcasterContextKey = "bct_websocket_broadcaster"
+
+// StoreInContext makes the broadcaster available to request handlers.
+func StoreInContext(context *gin.Context, broadcaster *WebsocketBroadcaster) {
+	context.Set(broadcasterContextKey, broadcaster)
+}
+
+// ListSubscribers describes the subscribers of the broadcaster associated with the request, if any.
+func ListSubscribers(context *gin.Context) []*SubscriberInfo {
+	value, exists := context.Get(broadcasterContextKey)
+	if !exists {
+		return []*SubscriberInfo{}
+	}
+
+	return value.(*WebsocketBroadcaster).Subscribers()
+}
@@ -1,6 +1,9 @@
 package websocket
 
 import (
+	"bctbackend/database/models"
+	"bctbackend/server/events"
+	"encoding/json"
 	"log/slog"
 	"time"
 
@@ -12,28 +15,95 @@ type WebsocketBroadcasterMessage interface {
 }
 
 type AddSubscriberMessage struct {
-	connection *websocket.Conn
+	broadcaster   *WebsocketBroadcaster
+	connection    *websocket.Conn
+	subscription  *events.Subscription
+	remoteAddress string
 }
 
 func (m *AddSubscriberMessage) execute(broadcasterState *broadcasterState) {
 	subscriber := &subscriber{
-		connection: m.connection,
-		active:     true,
-		next:       broadcasterState.subscribers,
+		connection:      m.connection,
+		subscription:    m.subscription,
+		remoteAddress:   m.remoteAddress,
+		connectedAt:     models.Now(),
+		queue:           make(chan []byte, broadcasterState.options.QueueSize),
+		droppedMessages: 0,
+		active:          true,
+		closed:          make(chan struct{}),
+		next:            broadcasterState.subscribers,
 	}
 
 	broadcasterState.subscribers = subscriber
 
-	go func() {
-		m.connection.SetReadDeadline(time.Time{})
+	// The pumps may fail after the broadcaster has stopped, in which case nobody is left to receive the message
+	remove := func() {
+		m.broadcaster.RemoveSubscriber(subscriber)
+	}
+
+	go readPump(subscriber, broadcasterState.options, remove)
+	go writePump(subscriber, broadcasterState.options, remove)
+}
+
+// readPump keeps reading from the connection, which is needed to process pongs and to detect closed connections.
+// Clients are not expected to send anything; whatever they send is ignored.
+func readPump(subscriber *subscriber, options Options, remove func()) {
+	connection := subscriber.connection
+
+	connection.SetReadDeadline(time.Now().Add(options.PingInterval + options.PongTimeout))
+	connection.SetPongHandler(func(string) error {
+		return connection.SetReadDeadline(time.Now().Add(options.PingInterval + options.PongTimeout))
+	})
+
+	for {
+		if _, _, err := connection.ReadMessage(); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				slog.Info("WebSocket connection lost", slog.Int64("user_id", subscriber.subscription.UserId.Int64()), slog.String("error", err.Error()))
+			}
 
-		_, _, err := m.connection.ReadMessage()
-		if err != nil {
-			slog.Error("Failed to read message from WebSocket", slog.String("error", err.Error()))
+			remove()
+			return
 		}
+	}
+}
+
+// writePump sends queued messages and pings to the subscriber.
+// As each subscriber has its own writer, a slow subscriber cannot hold up the others.
+func writePump(subscriber *subscriber, options Options, remove func()) {
+	connection := subscriber.connection
+	pingTicker := time.NewTicker(options.PingInterval)
+	defer pingTicker.Stop()
+	revalidationTicker := time.NewTicker(options.RevalidationInterval)
+	defer revalidationTicker.Stop()
+
+	for {
+		select {
+		case message := <-subscriber.queue:
+			connection.SetWriteDeadline(time.Now().Add(options.WriteTimeout))
+			if err := connection.WriteMessage(websocket.TextMessage, message); err != nil {
+				slog.Error("Failed to write message to WebSocket", slog.String("error", err.Error()))
+				remove()
+				return
+			}
 
-		broadcasterState.messageChannel <- &RemoveSubscriberMessage{subscriber: subscriber}
-	}()
+		case <-pingTicker.C:
+			if err := connection.WriteControl(websocket.PingMessage, nil, time.Now().Add(options.WriteTimeout)); err != nil {
+				slog.Info("Failed to ping WebSocket", slog.String("error", err.Error()))
+				remove()
+				return
+			}
+
+		case <-revalidationTicker.C:
+			if !subscriber.subscription.IsValid() {
+				slog.Info("Closing WebSocket of expired or revoked credentials", slog.Int64("user_id", subscriber.subscription.UserId.Int64()))
+				remove()
+				return
+			}
+
+		case <-subscriber.closed:
+			return
+		}
+	}
 }
 
 type RemoveSubscriberMessage struct {
@@ -41,36 +111,115 @@ type RemoveSubscriberMessage struct {
 }
 
 func (m *RemoveSubscriberMessage) execute(broadcasterState *broadcasterState) {
-	m.subscriber.active = false
-	m.subscriber.connection.Close()
+	m.subscriber.close()
+}
+
+// CloseMessage disconnects all subscribers and makes the broadcaster stop.
+type CloseMessage struct{}
+
+func (m *CloseMessage) execute(broadcasterState *broadcasterState) {
+	for current := broadcasterState.subscribers; current != nil; current = current.next {
+		if current.active {
+			current.active = false
+			closeWithCloseFrame(current.connection, websocket.CloseGoingAway, "server is shutting down")
+			close(current.closed)
+		}
+	}
+
+	broadcasterState.subscribers = nil
+	broadcasterState.stopping = true
+}
+
+type DisconnectMessage struct {
+	matches func(subscription *events.Subscription) bool
+}
+
+func (m *DisconnectMessage) execute(broadcasterState *broadcasterState) {
+	for current := broadcasterState.subscribers; current != nil; current = current.next {
+		if current.active && m.matches(current.subscription) {
+			current.close()
+		}
+	}
 }
 
 type BroadcastMessage struct {
-	message string
+	event   *events.Event
+	message []byte
 }
 
+// execute only queues the message; the subscribers' writers take care of actually sending it.
 func (m *BroadcastMessage) execute(broadcasterState *broadcasterState) {
-	var dummy *subscriber
-	previousSubscriberNext := &dummy
-
+	previousSubscriberNext := &broadcasterState.subscribers
 	current := broadcasterState.subscribers
-	subscriberCount := 0
 
 	for current != nil {
 		if !current.active {
 			*previousSubscriberNext = current.next
 		} else {
-			current.connection.SetWriteDeadline(time.Now().Add(2 * time.Second))
-			if err := current.connection.WriteMessage(websocket.TextMessage, []byte(m.message)); err != nil {
-				slog.Error("Failed to write message to WebSocket", slog.String("error", err.Error()))
-				current.active = false
-				current.connection.Close()
+			if current.subscription.Accepts(m.event) {
+				if message, ok := m.messageFor(current.subscription); ok {
+					enqueue(current, message, broadcasterState.options.OverflowPolicy)
+				}
 			}
 
-			subscriberCount++
 			previousSubscriberNext = &current.next
 		}
 
 		current = current.next
 	}
 }
+
+// messageFor serializes the event as seen by the subscriber, which is only done anew if it differs from what others see.
+func (m *BroadcastMessage) messageFor(subscription *events.Subscription) ([]byte, bool) {
+	view := subscription.View(m.event)
+	if view == m.event {
+		return m.message, true
+	}
+
+	message, err := json.Marshal(view)
+	if err != nil {
+		slog.Error("Failed to serialize event", slog.String("type", string(view.Type)), slog.String("error", err.Error()))
+		return nil, false
+	}
+
+	return message, true
+}
+
+func enqueue(subscriber *subscriber, message []byte, overflowPolicy OverflowPolicy) {
+	select {
+	case subscriber.queue <- message:
+		return
+	default:
+	}
+
+	switch overflowPolicy {
+	case DropOnOverflow:
+		subscriber.droppedMessages++
+		slog.Warn("Dropping message for slow WebSocket subscriber", slog.Int64("user_id", subscriber.subscription.UserId.Int64()))
+	default:
+		slog.Warn("Disconnecting slow WebSocket subscriber", slog.Int64("user_id", subscriber.subscription.UserId.Int64()))
+		subscriber.close()
+	}
+}
+
+type ListSubscribersMessage struct {
+	result chan<- []*SubscriberInfo
+}
+
+func (m *ListSubscribersMessage) execute(broadcasterState *broadcasterState) {
+	subscribers := []*SubscriberInfo{}
+	for current := broadcasterState.subscribers; current != nil; current = current.next {
+		if current.active {
+			subscribers = append(subscribers, &SubscriberInfo{
+				UserId:          current.subscription.UserId,
+				RoleId:          current.subscription.RoleId,
+				RemoteAddress:   current.remoteAddress,
+				ConnectedAt:     current.connectedAt,
+				QueueDepth:      len(current.queue),
+				DroppedMessages: current.droppedMessages,
+			})
+		}
+	}
+
+	m.result <- subscribers
+}
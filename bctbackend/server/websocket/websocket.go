@@ -1,52 +1,151 @@
 package websocket
 
 import (
+	"bctbackend/database/models"
+	"bctbackend/server/events"
+	"encoding/json"
 	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
+// OverflowPolicy determines what happens when a subscriber cannot keep up with the events.
+type OverflowPolicy int
+
+const (
+	// Close the connection; the client is expected to reconnect and refetch its data
+	DisconnectOnOverflow OverflowPolicy = iota
+
+	// Drop the events that do not fit in the subscriber's queue
+	DropOnOverflow
+)
+
+type Options struct {
+	// Number of messages that can be waiting to be sent to a single subscriber
+	QueueSize int
+
+	// What to do with a subscriber whose queue is full
+	OverflowPolicy OverflowPolicy
+
+	// How often subscribers are pinged; connections that do not answer within PongTimeout are closed
+	PingInterval time.Duration
+	PongTimeout  time.Duration
+
+	// How long writing a single message may take
+	WriteTimeout time.Duration
+
+	// How often subscriptions are checked for expired or revoked credentials
+	RevalidationInterval time.Duration
+}
+
 type WebsocketBroadcaster struct {
 	messageChannel chan<- WebsocketBroadcasterMessage
+	upgrader       websocket.Upgrader
+	closeOnce      sync.Once
+	stopped        chan struct{}
+}
+
+// SubscriberInfo describes a connected subscriber.
+type SubscriberInfo struct {
+	UserId          models.Id
+	RoleId          models.RoleId
+	RemoteAddress   string
+	ConnectedAt     models.Timestamp
+	QueueDepth      int
+	DroppedMessages int
 }
 
 type subscriber struct {
-	connection *websocket.Conn
-	active     bool
-	next       *subscriber
+	connection      *websocket.Conn
+	subscription    *events.Subscription
+	remoteAddress   string
+	connectedAt     models.Timestamp
+	queue           chan []byte
+	droppedMessages int
+	active          bool
+	closed          chan struct{}
+	next            *subscriber
+}
+
+// close ends the connection. Must only be called from the broadcaster's goroutine.
+func (s *subscriber) close() {
+	if s.active {
+		s.active = false
+		s.connection.Close()
+		close(s.closed)
+	}
 }
 
 type broadcasterState struct {
-	subscribers    *subscriber
-	messageChannel chan<- WebsocketBroadcasterMessage
+	subscribers *subscriber
+	options     Options
+	stopping    bool
 }
 
-func NewWebsocketBroadcaster() *WebsocketBroadcaster {
+// NewWebsocketBroadcaster creates a broadcaster that only accepts connections whose origin passes checkOrigin.
+func NewWebsocketBroadcaster(checkOrigin func(*http.Request) bool, options Options) *WebsocketBroadcaster {
 	messageChannel := make(chan WebsocketBroadcasterMessage)
 
 	state := broadcasterState{
-		subscribers:    nil,
-		messageChannel: messageChannel,
+		subscribers: nil,
+		options:     options,
 	}
 
+	stopped := make(chan struct{})
+
 	go func() {
+		defer close(stopped)
+
 		for message := range messageChannel {
 			message.execute(&state)
+
+			if state.stopping {
+				return
+			}
 		}
 	}()
 
 	return &WebsocketBroadcaster{
 		messageChannel: messageChannel,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin,
+		},
+		stopped: stopped,
 	}
 }
 
-func (wb *WebsocketBroadcaster) AddSubscriber(connection *websocket.Conn) {
+// Close sends a close frame to all subscribers, disconnects them and stops the broadcaster.
+// Afterwards, new subscribers are refused and published events are discarded.
+func (wb *WebsocketBroadcaster) Close() {
+	wb.closeOnce.Do(func() {
+		wb.sendMessage(&CloseMessage{})
+		<-wb.stopped
+	})
+}
+
+// Subscribe upgrades the request to a websocket connection over which the events
+// accepted by the subscription will be sent.
+func (wb *WebsocketBroadcaster) Subscribe(context *gin.Context, subscription *events.Subscription) {
+	connection, err := wb.upgrader.Upgrade(context.Writer, context.Request, nil)
+	if err != nil {
+		slog.Error("Failed to upgrade connection to WebSocket", slog.String("error", err.Error()))
+		return
+	}
+
 	message := &AddSubscriberMessage{
-		connection: connection,
+		broadcaster:   wb,
+		connection:    connection,
+		subscription:  subscription,
+		remoteAddress: context.ClientIP(),
+	}
+
+	if !wb.sendMessage(message) {
+		closeWithCloseFrame(connection, websocket.CloseGoingAway, "server is shutting down")
 	}
-	wb.sendMessage(message)
 }
 
 func (wb *WebsocketBroadcaster) RemoveSubscriber(subscriber *subscriber) {
@@ -56,36 +155,72 @@ func (wb *WebsocketBroadcaster) RemoveSubscriber(subscriber *subscriber) {
 	wb.sendMessage(message)
 }
 
-func (wb *WebsocketBroadcaster) Broadcast(msg string) {
-	message := &BroadcastMessage{
-		message: msg,
-	}
-	wb.sendMessage(message)
+// DisconnectSession closes all connections opened with the given session.
+func (wb *WebsocketBroadcaster) DisconnectSession(sessionId models.SessionId) {
+	wb.sendMessage(&DisconnectMessage{
+		matches: func(subscription *events.Subscription) bool {
+			return subscription.SessionId == sessionId
+		},
+	})
 }
 
-func (wb *WebsocketBroadcaster) sendMessage(message WebsocketBroadcasterMessage) {
-	wb.messageChannel <- message
+// DisconnectUserSessions closes all connections the user opened with a session.
+// Connections opened with API tokens are left alone.
+func (wb *WebsocketBroadcaster) DisconnectUserSessions(userId models.Id) {
+	wb.sendMessage(&DisconnectMessage{
+		matches: func(subscription *events.Subscription) bool {
+			return subscription.SessionId != "" && subscription.UserId == userId
+		},
+	})
 }
 
-func (wb *WebsocketBroadcaster) CreateHandler() func(*gin.Context) {
-	var upgrader = websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
-			return true // TODO Replace with origin check
-		},
+// SubscriberCount returns the number of currently connected subscribers.
+func (wb *WebsocketBroadcaster) SubscriberCount() int {
+	return len(wb.Subscribers())
+}
+
+// Subscribers describes all currently connected subscribers.
+func (wb *WebsocketBroadcaster) Subscribers() []*SubscriberInfo {
+	result := make(chan []*SubscriberInfo)
+	if !wb.sendMessage(&ListSubscribersMessage{result: result}) {
+		return []*SubscriberInfo{}
 	}
 
-	websocketHandler := func(c *gin.Context) {
-		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-		if err != nil {
-			slog.Error("Failed to upgrade connection to WebSocket", slog.String("error", err.Error()))
-			return
-		}
+	return <-result
+}
 
-		message := AddSubscriberMessage{
-			connection: conn,
-		}
-		wb.sendMessage(&message)
+// Publish queues the event as JSON for all subscribers that accept it.
+func (wb *WebsocketBroadcaster) Publish(event *events.Event) {
+	serializedEvent, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to serialize event", slog.String("type", string(event.Type)), slog.String("error", err.Error()))
+		return
+	}
+
+	wb.sendMessage(&BroadcastMessage{
+		event:   event,
+		message: serializedEvent,
+	})
+}
+
+// sendMessage hands the message to the broadcaster's goroutine.
+// Returns false if the broadcaster has been stopped, in which case the message is not executed.
+func (wb *WebsocketBroadcaster) sendMessage(message WebsocketBroadcasterMessage) bool {
+	select {
+	case wb.messageChannel <- message:
+		return true
+	case <-wb.stopped:
+		return false
+	}
+}
+
+// closeWithCloseFrame tells the client why the connection is closed before closing it,
+// so that it can distinguish a shutdown from a network failure.
+func closeWithCloseFrame(connection *websocket.Conn, code int, reason string) {
+	deadline := time.Now().Add(time.Second)
+	if err := connection.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline); err != nil {
+		slog.Debug("Failed to send close frame", slog.String("error", err.Error()))
 	}
 
-	return websocketHandler
+	connection.Close()
 }
@@ -0,0 +1,119 @@
+//go:build test
+
+package barcode
+
+import (
+	"bctbackend/barcode"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Round trip", func(t *testing.T) {
+			for _, itemId := range []int64{1, 9, 10, 123, 99999, 1234567890} {
+				t.Run(fmt.Sprintf("Item %d", itemId), func(t *testing.T) {
+					payload := barcode.FormatPayload("KQB", itemId)
+
+					actual, err := barcode.ParsePayload(payload, "KQB")
+					require.NoError(t, err)
+					require.Equal(t, itemId, actual)
+				})
+			}
+		})
+
+		t.Run("EAN-13", func(t *testing.T) {
+			content, err := barcode.InternalEan13Content("KQB", 1234)
+			require.NoError(t, err)
+
+			// KQB is 10*26*26 + 16*26 + 1 = 7177, of which the last three digits identify the event
+			require.Equal(t, "201770001234", content)
+
+			// 201770001234 has check digit 7
+			actual, err := barcode.ParsePayload(content+"7", "KQB")
+			require.NoError(t, err)
+			require.Equal(t, int64(1234), actual)
+		})
+
+		t.Run("Generated event prefix", func(t *testing.T) {
+			prefix, err := barcode.GenerateEventPrefix()
+			require.NoError(t, err)
+			require.True(t, barcode.IsValidEventPrefix(prefix))
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Other event", func(t *testing.T) {
+			payload := barcode.FormatPayload("ABC", 123)
+
+			_, err := barcode.ParsePayload(payload, "KQB")
+			require.ErrorIs(t, err, barcode.ErrForeignPayload)
+		})
+
+		t.Run("Single misread digit", func(t *testing.T) {
+			payload := []byte(barcode.FormatPayload("KQB", 123456))
+
+			for index := barcode.EventPrefixLength; index < len(payload); index++ {
+				for digit := byte('0'); digit <= '9'; digit++ {
+					if digit == payload[index] {
+						continue
+					}
+
+					corrupted := append([]byte{}, payload...)
+					corrupted[index] = digit
+
+					_, err := barcode.ParsePayload(string(corrupted), "KQB")
+					require.ErrorIs(t, err, barcode.ErrCorruptedPayload, string(corrupted))
+				}
+			}
+		})
+
+		t.Run("Swapped adjacent digits", func(t *testing.T) {
+			payload := []byte(barcode.FormatPayload("KQB", 1357))
+
+			corrupted := append([]byte{}, payload...)
+			corrupted[4], corrupted[5] = corrupted[5], corrupted[4]
+
+			_, err := barcode.ParsePayload(string(corrupted), "KQB")
+			require.ErrorIs(t, err, barcode.ErrCorruptedPayload)
+		})
+
+		t.Run("EAN-13 of other event", func(t *testing.T) {
+			// ABC is 0*26*26 + 1*26 + 2 = 28
+			content, err := barcode.InternalEan13Content("ABC", 1234)
+			require.NoError(t, err)
+			require.Equal(t, "200280001234", content)
+
+			// 200280001234 has check digit 2
+			_, err = barcode.ParsePayload(content+"2", "KQB")
+			require.ErrorIs(t, err, barcode.ErrForeignPayload)
+		})
+
+		t.Run("Item id too large for EAN-13", func(t *testing.T) {
+			_, err := barcode.InternalEan13Content("KQB", 10000000)
+			require.Error(t, err)
+		})
+
+		t.Run("Corrupted EAN-13", func(t *testing.T) {
+			_, err := barcode.ParsePayload("2000000012345", "KQB")
+			require.ErrorIs(t, err, barcode.ErrCorruptedPayload)
+		})
+
+		t.Run("EAN-13 not for internal use", func(t *testing.T) {
+			// A valid EAN-13 code of a retail product
+			_, err := barcode.ParsePayload("4006381333931", "KQB")
+			require.ErrorIs(t, err, barcode.ErrForeignPayload)
+		})
+
+		t.Run("Malformed", func(t *testing.T) {
+			for _, payload := range []string{"", "KQB", "KQB1", "123x", "kqb1234", "KQB12a4", "K1B1234"} {
+				t.Run(fmt.Sprintf("Payload %q", payload), func(t *testing.T) {
+					_, err := barcode.ParsePayload(payload, "KQB")
+					require.ErrorIs(t, err, barcode.ErrMalformedPayload)
+				})
+			}
+		})
+	})
+}
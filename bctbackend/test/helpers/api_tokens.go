@@ -0,0 +1,46 @@
+//go:build test
+
+package helpers
+
+import (
+	models "bctbackend/database/models"
+	queries "bctbackend/database/queries"
+	"database/sql"
+)
+
+type AddApiTokenData struct {
+	actions        []string
+	expirationTime *models.Timestamp
+}
+
+func WithActions(actions ...string) func(*AddApiTokenData) {
+	return func(data *AddApiTokenData) {
+		data.actions = actions
+	}
+}
+
+func WithTokenExpiration(secondsBeforeExpiration int64) func(*AddApiTokenData) {
+	return func(data *AddApiTokenData) {
+		expirationTime := models.Now() + models.Timestamp(secondsBeforeExpiration)
+		data.expirationTime = &expirationTime
+	}
+}
+
+func AddApiTokenToDatabase(db *sql.DB, userId models.Id, options ...func(*AddApiTokenData)) (models.Id, string) {
+	data := &AddApiTokenData{
+		actions:        nil,
+		expirationTime: nil,
+	}
+
+	for _, option := range options {
+		option(data)
+	}
+
+	tokenId, token, err := queries.AddApiToken(db, userId, "", data.actions, data.expirationTime)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return tokenId, token
+}
@@ -0,0 +1,48 @@
+//go:build test
+
+package helpers
+
+import (
+	models "bctbackend/database/models"
+	queries "bctbackend/database/queries"
+	"database/sql"
+)
+
+// DefaultLabelLayout returns a valid A4 layout with the given name.
+func DefaultLabelLayout(name string) *models.LabelLayout {
+	return &models.LabelLayout{
+		Name:         name,
+		PaperWidth:   210,
+		PaperHeight:  297,
+		PaperMargins: models.Insets{Top: 10, Bottom: 10, Left: 10, Right: 10},
+		Columns:      2,
+		Rows:         10,
+		LabelMargins: models.Insets{Top: 2, Bottom: 2, Left: 2, Right: 2},
+		LabelPadding: models.Insets{Top: 2, Bottom: 2, Left: 2, Right: 2},
+		FontSize:     12,
+	}
+}
+
+func WithGrid(columns int, rows int) func(*models.LabelLayout) {
+	return func(layout *models.LabelLayout) {
+		layout.Columns = columns
+		layout.Rows = rows
+	}
+}
+
+func AddLabelLayoutToDatabase(db *sql.DB, name string, options ...func(*models.LabelLayout)) *models.LabelLayout {
+	layout := DefaultLabelLayout(name)
+
+	for _, option := range options {
+		option(layout)
+	}
+
+	layoutId, err := queries.AddLabelLayout(db, layout)
+
+	if err != nil {
+		panic(err)
+	}
+
+	layout.LayoutId = layoutId
+	return layout
+}
@@ -3,6 +3,8 @@
 package helpers
 
 import (
+	"bctbackend/barcode"
+	"bctbackend/pdf"
 	"bctbackend/server"
 	"bctbackend/server/configuration"
 	"database/sql"
@@ -11,14 +13,28 @@ import (
 	gin "github.com/gin-gonic/gin"
 )
 
-func CreateRestServer(db *sql.DB) *server.Server {
+func CreateRestServer(db *sql.DB, options ...func(*configuration.Configuration)) *server.Server {
 	configuration := configuration.Configuration{
-		FontDirectory: os.Getenv("BCT_FONT_DIR"),
-		FontFilename:  os.Getenv("BCT_FONT_FILE"),
-		FontFamily:    os.Getenv("BCT_FONT_FAMILY"),
-		BarcodeWidth:  150,
-		BarcodeHeight: 30,
-		GinMode:       gin.TestMode,
+		FontDirectory:      os.Getenv("BCT_FONT_DIR"),
+		FontFilename:       os.Getenv("BCT_FONT_FILE"),
+		FontFamily:         os.Getenv("BCT_FONT_FAMILY"),
+		MinimumFontSize:    2,
+		BarcodeRenderer:    pdf.VectorBarcodes,
+		BarcodeSymbology:   barcode.Code128,
+		BarcodeModuleWidth: 0.33,
+		BarcodeBarHeight:   10,
+		BarcodeWidth:       150,
+		BarcodeHeight:      30,
+		ZplLabelWidth:      812,
+		ZplLabelHeight:     406,
+		ZplLabelPadding:    16,
+		ZplDpi:             203,
+		ZplFontSize:        3,
+		GinMode:            gin.TestMode,
+	}
+
+	for _, option := range options {
+		option(&configuration)
 	}
 
 	server := server.NewServer(db, &configuration)
@@ -0,0 +1,48 @@
+//go:build test
+
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bctbackend/server/jobs"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("Cancels running jobs", func(t *testing.T) {
+		registry := jobs.NewRegistry(nil, time.Hour)
+
+		started := make(chan struct{})
+		snapshot := registry.Start(1, func(ctx context.Context, tracker *jobs.Tracker) (*jobs.Result, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		<-started
+
+		require.True(t, registry.Shutdown(time.Second))
+
+		actual, result, ok := registry.Get(snapshot.JobId)
+		require.True(t, ok)
+		require.Nil(t, result)
+		require.Equal(t, jobs.Failed, actual.Status)
+		require.Equal(t, context.Canceled.Error(), actual.Error)
+	})
+
+	t.Run("Gives up after timeout", func(t *testing.T) {
+		registry := jobs.NewRegistry(nil, time.Hour)
+
+		release := make(chan struct{})
+		defer close(release)
+		registry.Start(1, func(ctx context.Context, tracker *jobs.Tracker) (*jobs.Result, error) {
+			<-release
+			return nil, nil
+		})
+
+		require.False(t, registry.Shutdown(10*time.Millisecond))
+	})
+}
@@ -0,0 +1,83 @@
+//go:build test
+
+package logging
+
+import (
+	"bctbackend/server/logging"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	readFile := func(t *testing.T, path string) string {
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(contents)
+	}
+
+	t.Run("Rotates once full", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bct.log")
+		file, err := logging.OpenRotatingFile(path, 8, 2)
+		require.NoError(t, err)
+		defer file.Close()
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := file.Write([]byte(line))
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, "fourth\n", readFile(t, path))
+		require.Equal(t, "third\n", readFile(t, path+".1"))
+		require.Equal(t, "second\n", readFile(t, path+".2"))
+		require.NoFileExists(t, path+".3")
+	})
+
+	t.Run("Appends to existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bct.log")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+
+		file, err := logging.OpenRotatingFile(path, 100, 1)
+		require.NoError(t, err)
+		defer file.Close()
+
+		_, err = file.Write([]byte("new\n"))
+		require.NoError(t, err)
+		require.Equal(t, "old\nnew\n", readFile(t, path))
+	})
+
+	t.Run("Keeps writing when rotation fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bct.log")
+		file, err := logging.OpenRotatingFile(path, 4, 1)
+		require.NoError(t, err)
+		defer file.Close()
+
+		// A non-empty directory cannot be replaced by the log file
+		require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "blocked"), 0755))
+
+		_, err = file.Write([]byte("abc\n"))
+		require.NoError(t, err)
+		_, err = file.Write([]byte("def\n"))
+		require.Error(t, err)
+		file.Write([]byte("ghi\n"))
+
+		require.Equal(t, "abc\ndef\nghi\n", readFile(t, path))
+	})
+
+	t.Run("Without backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bct.log")
+		file, err := logging.OpenRotatingFile(path, 4, 0)
+		require.NoError(t, err)
+		defer file.Close()
+
+		for _, line := range []string{"abc\n", "def\n"} {
+			_, err := file.Write([]byte(line))
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, "def\n", readFile(t, path))
+		require.NoFileExists(t, path+".1")
+	})
+}
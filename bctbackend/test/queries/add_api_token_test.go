@@ -0,0 +1,80 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddApiToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Unrestricted", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			cashier := setup.Cashier()
+
+			tokenId, token, err := queries.AddApiToken(db, cashier.UserId, "kiosk", nil, nil)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+
+			tokens, err := queries.GetApiTokens(db)
+			require.NoError(t, err)
+			require.Len(t, tokens, 1)
+			require.Equal(t, tokenId, tokens[0].TokenId)
+			require.Equal(t, cashier.UserId, tokens[0].UserId)
+			require.Equal(t, "kiosk", tokens[0].Description)
+			require.Empty(t, tokens[0].Actions)
+			require.Nil(t, tokens[0].ExpirationTime)
+		})
+
+		t.Run("Restricted with expiry", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			expirationTime := models.Now() + 1000
+
+			_, _, err := queries.AddApiToken(db, seller.UserId, "printer", []string{"generate_labels", "view_item"}, &expirationTime)
+			require.NoError(t, err)
+
+			tokens, err := queries.GetApiTokens(db)
+			require.NoError(t, err)
+			require.Len(t, tokens, 1)
+			require.Equal(t, []string{"generate_labels", "view_item"}, tokens[0].Actions)
+			require.Equal(t, &expirationTime, tokens[0].ExpirationTime)
+		})
+
+		t.Run("Token is not stored in plain text", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin := setup.Admin()
+
+			_, token, err := queries.AddApiToken(db, admin.UserId, "", nil, nil)
+			require.NoError(t, err)
+
+			var count int
+			err = db.QueryRow(`SELECT COUNT(*) FROM api_tokens WHERE token_hash = ?`, token).Scan(&count)
+			require.NoError(t, err)
+			require.Equal(t, 0, count)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		userId := models.Id(999)
+		setup.RequireNoSuchUsers(t, userId)
+
+		_, _, err := queries.AddApiToken(db, userId, "", nil, nil)
+		require.ErrorIs(t, err, dberr.ErrNoSuchUser)
+	})
+}
@@ -0,0 +1,57 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/queries"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLabelLayout(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, db := NewDatabaseFixture()
+		defer setup.Close()
+
+		layout := aux.DefaultLabelLayout("Avery L7160 21-up")
+		layout.LabelPadding.Left = 3
+		layout.Symbology = "qr"
+
+		layoutId, err := queries.AddLabelLayout(db, layout)
+		require.NoError(t, err)
+
+		actual, err := queries.GetLabelLayoutWithId(db, layoutId)
+		require.NoError(t, err)
+
+		layout.LayoutId = layoutId
+		require.Equal(t, layout, actual)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Empty name", func(t *testing.T) {
+			setup, db := NewDatabaseFixture()
+			defer setup.Close()
+
+			_, err := queries.AddLabelLayout(db, aux.DefaultLabelLayout(""))
+			require.ErrorIs(t, err, dberr.ErrInvalidLabelLayoutName)
+		})
+
+		t.Run("Name in use", func(t *testing.T) {
+			setup, db := NewDatabaseFixture()
+			defer setup.Close()
+
+			setup.LabelLayout("Herma 4226")
+
+			_, err := queries.AddLabelLayout(db, aux.DefaultLabelLayout("Herma 4226"))
+			require.ErrorIs(t, err, dberr.ErrLabelLayoutNameInUse)
+
+			layouts, err := queries.GetLabelLayouts(db)
+			require.NoError(t, err)
+			require.Len(t, layouts, 1)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteSessionsOfUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		user := setup.Cashier()
+		otherUser := setup.Cashier()
+
+		_, sessionId1 := setup.LoggedIn(user)
+		_, sessionId2 := setup.LoggedIn(user)
+		_, otherSessionId := setup.LoggedIn(otherUser)
+
+		removedSessionCount, err := queries.DeleteSessionsOfUser(db, user.UserId)
+		require.NoError(t, err)
+		require.Equal(t, 2, removedSessionCount)
+
+		for _, sessionId := range []models.SessionId{sessionId1, sessionId2} {
+			_, err = queries.GetSessionById(db, sessionId)
+			require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+		}
+
+		_, err = queries.GetSessionById(db, otherSessionId)
+		require.NoError(t, err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		userId := models.Id(999)
+		setup.RequireNoSuchUsers(t, userId)
+
+		_, err := queries.DeleteSessionsOfUser(db, userId)
+		require.ErrorIs(t, err, dberr.ErrNoSuchUser)
+	})
+}
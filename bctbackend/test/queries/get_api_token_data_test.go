@@ -0,0 +1,57 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetApiTokenData(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		seller := setup.Seller()
+		expirationTime := models.Now() + 1000
+
+		tokenId, token, err := queries.AddApiToken(db, seller.UserId, "", []string{"generate_labels"}, &expirationTime)
+		require.NoError(t, err)
+
+		tokenData, err := queries.GetApiTokenData(db, token)
+		require.NoError(t, err)
+		require.Equal(t, tokenId, tokenData.TokenId)
+		require.Equal(t, seller.UserId, tokenData.UserId)
+		require.Equal(t, models.NewSellerRoleId(), tokenData.RoleId)
+		require.Equal(t, []string{"generate_labels"}, tokenData.Actions)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown token", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, err := queries.GetApiTokenData(db, "bct_unknown")
+			require.ErrorIs(t, err, dberr.ErrNoSuchApiToken)
+		})
+
+		t.Run("Expired token", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			expirationTime := models.Now() - 1
+
+			_, token, err := queries.AddApiToken(db, seller.UserId, "", nil, &expirationTime)
+			require.NoError(t, err)
+
+			_, err = queries.GetApiTokenData(db, token)
+			require.ErrorIs(t, err, dberr.ErrNoSuchApiToken)
+		})
+	})
+}
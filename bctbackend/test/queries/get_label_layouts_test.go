@@ -0,0 +1,35 @@
+//go:build test
+
+package queries
+
+import (
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLabelLayouts(t *testing.T) {
+	t.Run("No layouts", func(t *testing.T) {
+		setup, db := NewDatabaseFixture()
+		defer setup.Close()
+
+		layouts, err := queries.GetLabelLayouts(db)
+		require.NoError(t, err)
+		require.Empty(t, layouts)
+	})
+
+	t.Run("Ordered by name", func(t *testing.T) {
+		setup, db := NewDatabaseFixture()
+		defer setup.Close()
+
+		herma := setup.LabelLayout("Herma 4226")
+		avery := setup.LabelLayout("Avery L7160 21-up")
+
+		layouts, err := queries.GetLabelLayouts(db)
+		require.NoError(t, err)
+		require.Equal(t, []*models.LabelLayout{avery, herma}, layouts)
+	})
+}
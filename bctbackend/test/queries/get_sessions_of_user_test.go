@@ -0,0 +1,60 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSessionsOfUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		user := setup.Seller()
+		otherUser := setup.Seller()
+
+		_, sessionId1 := setup.LoggedIn(user)
+		_, sessionId2 := setup.LoggedIn(user)
+		setup.LoggedIn(otherUser)
+
+		sessions, err := queries.GetSessionsOfUser(db, user.UserId)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		sessionIds := []models.SessionId{sessions[0].SessionID, sessions[1].SessionID}
+		require.ElementsMatch(t, []models.SessionId{sessionId1, sessionId2}, sessionIds)
+
+		for _, session := range sessions {
+			require.Equal(t, user.UserId, session.UserID)
+		}
+	})
+
+	t.Run("No sessions", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		user := setup.Seller()
+
+		sessions, err := queries.GetSessionsOfUser(db, user.UserId)
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		userId := models.Id(999)
+		setup.RequireNoSuchUsers(t, userId)
+
+		_, err := queries.GetSessionsOfUser(db, userId)
+		require.ErrorIs(t, err, dberr.ErrNoSuchUser)
+	})
+}
@@ -0,0 +1,52 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDatabaseBusy(t *testing.T) {
+	open := func(t *testing.T, path string) *sql.DB {
+		db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(0)")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	t.Run("Locked database", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bct.db")
+		first := open(t, path)
+		second := open(t, path)
+
+		_, err := first.Exec("CREATE TABLE entries (value INTEGER)")
+		require.NoError(t, err)
+
+		transaction, err := first.Begin()
+		require.NoError(t, err)
+		defer transaction.Rollback()
+		_, err = transaction.Exec("INSERT INTO entries VALUES (1)")
+		require.NoError(t, err)
+
+		_, err = second.Exec("INSERT INTO entries VALUES (2)")
+		require.Error(t, err)
+		require.True(t, dberr.IsDatabaseBusy(err))
+		require.True(t, dberr.IsDatabaseBusy(fmt.Errorf("wrapped: %w", err)))
+	})
+
+	t.Run("Other errors", func(t *testing.T) {
+		db := open(t, filepath.Join(t.TempDir(), "bct.db"))
+
+		_, err := db.Exec("SELECT * FROM missing")
+		require.Error(t, err)
+		require.False(t, dberr.IsDatabaseBusy(err))
+		require.False(t, dberr.IsDatabaseBusy(dberr.ErrNoSuchItem))
+		require.False(t, dberr.IsDatabaseBusy(nil))
+	})
+}
@@ -0,0 +1,44 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveApiToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		cashier := setup.Cashier()
+
+		tokenId, token, err := queries.AddApiToken(db, cashier.UserId, "", nil, nil)
+		require.NoError(t, err)
+		_, otherToken, err := queries.AddApiToken(db, cashier.UserId, "", nil, nil)
+		require.NoError(t, err)
+
+		err = queries.RemoveApiToken(db, tokenId)
+		require.NoError(t, err)
+
+		_, err = queries.GetApiTokenData(db, token)
+		require.ErrorIs(t, err, dberr.ErrNoSuchApiToken)
+
+		_, err = queries.GetApiTokenData(db, otherToken)
+		require.NoError(t, err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		err := queries.RemoveApiToken(db, models.Id(1))
+		require.ErrorIs(t, err, dberr.ErrNoSuchApiToken)
+	})
+}
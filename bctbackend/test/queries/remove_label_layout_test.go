@@ -0,0 +1,35 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveLabelLayout(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, db := NewDatabaseFixture()
+		defer setup.Close()
+
+		layout := setup.LabelLayout("Herma 4226")
+
+		require.NoError(t, queries.RemoveLabelLayout(db, layout.LayoutId))
+
+		_, err := queries.GetLabelLayoutWithId(db, layout.LayoutId)
+		require.ErrorIs(t, err, dberr.ErrNoSuchLabelLayout)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		setup, db := NewDatabaseFixture()
+		defer setup.Close()
+
+		err := queries.RemoveLabelLayout(db, models.Id(1))
+		require.ErrorIs(t, err, dberr.ErrNoSuchLabelLayout)
+	})
+}
@@ -0,0 +1,79 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewSession(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Extends expiration time", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			user := setup.Admin()
+			sessionId, err := queries.AddSession(db, user.UserId, models.Now()+10)
+			require.NoError(t, err)
+
+			newExpirationTime := models.Now() + 100
+			err = queries.RenewSession(db, sessionId, newExpirationTime, 1000)
+			require.NoError(t, err)
+
+			session, err := queries.GetSessionById(db, sessionId)
+			require.NoError(t, err)
+			require.Equal(t, newExpirationTime, session.ExpirationTime)
+		})
+
+		t.Run("Capped by maximum duration", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			user := setup.Admin()
+			sessionId, err := queries.AddSession(db, user.UserId, models.Now()+10)
+			require.NoError(t, err)
+
+			before, err := queries.GetSessionById(db, sessionId)
+			require.NoError(t, err)
+
+			err = queries.RenewSession(db, sessionId, models.Now()+1000, 50)
+			require.NoError(t, err)
+
+			session, err := queries.GetSessionById(db, sessionId)
+			require.NoError(t, err)
+			require.Equal(t, before.CreatedAt+50, session.ExpirationTime)
+		})
+
+		t.Run("Never shortens", func(t *testing.T) {
+			setup, db := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			user := setup.Admin()
+			expirationTime := models.Now() + 100
+			sessionId, err := queries.AddSession(db, user.UserId, expirationTime)
+			require.NoError(t, err)
+
+			err = queries.RenewSession(db, sessionId, models.Now()+10, 1000)
+			require.NoError(t, err)
+
+			session, err := queries.GetSessionById(db, sessionId)
+			require.NoError(t, err)
+			require.Equal(t, expirationTime, session.ExpirationTime)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		setup, db := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		nonexistentSessionId := models.SessionId("nonexistent-session-id")
+		err := queries.RenewSession(db, nonexistentSessionId, models.Now(), 1000)
+		require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+	})
+}
@@ -0,0 +1,73 @@
+//go:build test
+
+package queries
+
+import (
+	dberr "bctbackend/database/errors"
+	models "bctbackend/database/models"
+	"bctbackend/database/queries"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateLabelLayout(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Change layout", func(t *testing.T) {
+			setup, db := NewDatabaseFixture()
+			defer setup.Close()
+
+			layout := setup.LabelLayout("Avery L7160 21-up")
+			other := setup.LabelLayout("Herma 4226")
+
+			layout.Name = "Avery L7160"
+			layout.Columns = 3
+			layout.Rows = 7
+			require.NoError(t, queries.UpdateLabelLayout(db, layout))
+
+			actual, err := queries.GetLabelLayoutWithId(db, layout.LayoutId)
+			require.NoError(t, err)
+			require.Equal(t, layout, actual)
+
+			unchanged, err := queries.GetLabelLayoutWithId(db, other.LayoutId)
+			require.NoError(t, err)
+			require.Equal(t, other, unchanged)
+		})
+
+		t.Run("Keep name", func(t *testing.T) {
+			setup, db := NewDatabaseFixture()
+			defer setup.Close()
+
+			layout := setup.LabelLayout("Herma 4226")
+			layout.FontSize = 8
+			require.NoError(t, queries.UpdateLabelLayout(db, layout))
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("No such layout", func(t *testing.T) {
+			setup, db := NewDatabaseFixture()
+			defer setup.Close()
+
+			layout := aux.DefaultLabelLayout("Herma 4226")
+			layout.LayoutId = models.Id(1)
+
+			err := queries.UpdateLabelLayout(db, layout)
+			require.ErrorIs(t, err, dberr.ErrNoSuchLabelLayout)
+		})
+
+		t.Run("Name in use", func(t *testing.T) {
+			setup, db := NewDatabaseFixture()
+			defer setup.Close()
+
+			layout := setup.LabelLayout("Avery L7160 21-up")
+			setup.LabelLayout("Herma 4226")
+
+			layout.Name = "Herma 4226"
+			err := queries.UpdateLabelLayout(db, layout)
+			require.ErrorIs(t, err, dberr.ErrLabelLayoutNameInUse)
+		})
+	})
+}
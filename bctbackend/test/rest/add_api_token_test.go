@@ -0,0 +1,105 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddApiToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		seller := setup.Seller()
+		expiresInSeconds := int64(3600)
+
+		url := path.ApiTokens()
+		payload := restapi.AddApiTokenPayload{
+			UserId:           seller.UserId,
+			Description:      "label printer",
+			Actions:          []string{"generate_labels"},
+			ExpiresInSeconds: &expiresInSeconds,
+		}
+		request := CreatePostRequest(url, &payload, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+		response := FromJson[restapi.AddApiTokenSuccessResponse](t, writer.Body.String())
+		tokenData, err := queries.GetApiTokenData(setup.Db, response.Token)
+		require.NoError(t, err)
+		require.Equal(t, response.TokenId, tokenData.TokenId)
+		require.Equal(t, seller.UserId, tokenData.UserId)
+		require.Equal(t, []string{"generate_labels"}, tokenData.Actions)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.ApiTokens()
+			payload := restapi.AddApiTokenPayload{UserId: seller.UserId}
+			request := CreatePostRequest(url, &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+
+		t.Run("Unknown user", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.RequireNoSuchUsers(t, 999)
+
+			url := path.ApiTokens()
+			payload := restapi.AddApiTokenPayload{UserId: 999}
+			request := CreatePostRequest(url, &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusNotFound, "no_such_user")
+		})
+
+		t.Run("Unknown action", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller := setup.Seller()
+
+			url := path.ApiTokens()
+			payload := restapi.AddApiTokenPayload{UserId: seller.UserId, Actions: []string{"fly"}}
+			request := CreatePostRequest(url, &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusBadRequest, "invalid_action")
+		})
+
+		t.Run("Action not allowed for owner's role", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller := setup.Seller()
+
+			url := path.ApiTokens()
+			payload := restapi.AddApiTokenPayload{UserId: seller.UserId, Actions: []string{"add_sale"}}
+			request := CreatePostRequest(url, &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusBadRequest, "invalid_action")
+		})
+	})
+}
@@ -0,0 +1,142 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+var presetLayout = restapi.Layout{
+	PaperWidth:   210,
+	PaperHeight:  297,
+	PaperMargins: restapi.Insets{Top: 15.1, Bottom: 15.1, Left: 7.2, Right: 7.2},
+	Columns:      3,
+	Rows:         7,
+	LabelMargins: restapi.Insets{Top: 0, Bottom: 0, Left: 1.25, Right: 1.25},
+	LabelPadding: restapi.Insets{Top: 2, Bottom: 2, Left: 2, Right: 2},
+	FontSize:     10,
+}
+
+func TestAddLabelLayout(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+
+		payload := restapi.LabelLayoutPayload{Name: "Avery L7160 21-up", Layout: presetLayout}
+		request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+		response := FromJson[restapi.AddLabelLayoutSuccessResponse](t, writer.Body.String())
+		layout, err := queries.GetLabelLayoutWithId(setup.Db, response.LayoutId)
+		require.NoError(t, err)
+		require.Equal(t, "Avery L7160 21-up", layout.Name)
+		require.Equal(t, 3, layout.Columns)
+		require.Equal(t, 7, layout.Rows)
+		require.Equal(t, 7.2, layout.PaperMargins.Left)
+	})
+
+	t.Run("Success with symbology", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+
+		layout := presetLayout
+		layout.Symbology = "datamatrix"
+		payload := restapi.LabelLayoutPayload{Name: "Small labels", Layout: layout}
+		request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+		response := FromJson[restapi.AddLabelLayoutSuccessResponse](t, writer.Body.String())
+		storedLayout, err := queries.GetLabelLayoutWithId(setup.Db, response.LayoutId)
+		require.NoError(t, err)
+		require.Equal(t, "datamatrix", storedLayout.Symbology)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown symbology", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			layout := presetLayout
+			layout.Symbology = "aztec"
+			payload := restapi.LabelLayoutPayload{Name: "Aztec", Layout: layout}
+			request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+
+			layouts, err := queries.GetLabelLayouts(setup.Db)
+			require.NoError(t, err)
+			require.Empty(t, layouts)
+		})
+
+		t.Run("Invalid layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			layout := presetLayout
+			layout.Columns = 0
+			payload := restapi.LabelLayoutPayload{Name: "Broken", Layout: layout}
+			request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+
+			layouts, err := queries.GetLabelLayouts(setup.Db)
+			require.NoError(t, err)
+			require.Empty(t, layouts)
+		})
+
+		t.Run("Missing name", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			payload := restapi.LabelLayoutPayload{Layout: presetLayout}
+			request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "invalid_request")
+		})
+
+		t.Run("Name in use", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.LabelLayout("Herma 4226")
+
+			payload := restapi.LabelLayoutPayload{Name: "Herma 4226", Layout: presetLayout}
+			request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "label_layout_name_in_use")
+		})
+
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+
+			payload := restapi.LabelLayoutPayload{Name: "Herma 4226", Layout: presetLayout}
+			request := CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
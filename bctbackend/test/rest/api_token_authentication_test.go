@@ -0,0 +1,150 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiTokenAuthentication(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Unrestricted token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			cashier := setup.Cashier()
+			seller := setup.Seller()
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+			_, token := setup.ApiToken(cashier)
+
+			url := path.Sales()
+			payload := restapi.AddSalePayload{Items: []models.Id{item.ItemID}}
+			request := CreatePostRequest(url, &payload, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.AddSaleSuccessResponse](t, writer.Body.String())
+			sale, err := queries.GetSaleWithId(setup.Db, response.SaleId)
+			require.NoError(t, err)
+			require.Equal(t, cashier.UserId, sale.CashierID)
+		})
+
+		t.Run("Restricted token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+			_, token := setup.ApiToken(seller, aux.WithActions("view_item"))
+
+			url := path.Item(item.ItemID)
+			request := CreateGetRequest(url, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+
+		t.Run("Token that has not expired yet", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin := setup.Admin()
+			_, token := setup.ApiToken(admin, aux.WithTokenExpiration(1000))
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithBearerToken("bct_unknown"))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusUnauthorized, "invalid_api_token")
+		})
+
+		t.Run("Unsupported scheme", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithHeader("Authorization", "Basic YWRtaW46YWRtaW4="))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusUnauthorized, "invalid_api_token")
+		})
+
+		t.Run("Expired token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin := setup.Admin()
+			_, token := setup.ApiToken(admin, aux.WithTokenExpiration(-1))
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusUnauthorized, "invalid_api_token")
+		})
+
+		t.Run("Revoked token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin := setup.Admin()
+			tokenId, token := setup.ApiToken(admin)
+			require.NoError(t, queries.RemoveApiToken(setup.Db, tokenId))
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusUnauthorized, "invalid_api_token")
+		})
+
+		t.Run("Action outside of token scope", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin := setup.Admin()
+			_, token := setup.ApiToken(admin, aux.WithActions("view_item"))
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "api_token_out_of_scope")
+		})
+
+		t.Run("Action not allowed for owner's role", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			_, token := setup.ApiToken(seller)
+
+			url := path.Items()
+			request := CreateGetRequest(url, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
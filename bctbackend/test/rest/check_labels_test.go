@@ -0,0 +1,179 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"bctbackend/database/models"
+	"bctbackend/server/configuration"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLabels(t *testing.T) {
+	longDescription := strings.Repeat("Hand-knitted woolen scarf in many colors ", 20)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Short description", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithDescription("Lamp"), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.CheckLabelsSuccessResponse](t, writer.Body.String())
+			require.Empty(t, response.Warnings)
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("Description requiring smaller font", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithDescription("Wooden rocking horse with saddle"), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.CheckLabelsSuccessResponse](t, writer.Body.String())
+			require.Len(t, response.Warnings, 1)
+			require.Equal(t, item.ItemID, response.Warnings[0].ItemId)
+			require.Equal(t, restapi.DescriptionShrunk, response.Warnings[0].Kind)
+			require.Less(t, response.Warnings[0].FontSize, presetLayout.FontSize)
+			require.GreaterOrEqual(t, response.Warnings[0].FontSize, 2.0)
+		})
+
+		t.Run("Description too long to fit", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			shortItem := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithDescription("Lamp"), aux.WithFrozen(false), aux.WithHidden(false))
+			longItem := setup.Item(seller.UserId, aux.WithDummyData(2), aux.WithDescription(longDescription), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{shortItem.ItemID, longItem.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.CheckLabelsSuccessResponse](t, writer.Body.String())
+			require.Len(t, response.Warnings, 1)
+			require.Equal(t, longItem.ItemID, response.Warnings[0].ItemId)
+			require.Equal(t, restapi.DescriptionTruncated, response.Warnings[0].Kind)
+			require.Equal(t, 2.0, response.Warnings[0].FontSize)
+		})
+
+		t.Run("Shrinking disabled", func(t *testing.T) {
+			setup, _, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			server := aux.CreateRestServer(setup.Db, func(configuration *configuration.Configuration) {
+				configuration.MinimumFontSize = 0
+			})
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithDescription("Wooden rocking horse with saddle"), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			server.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.CheckLabelsSuccessResponse](t, writer.Body.String())
+			require.Len(t, response.Warnings, 1)
+			require.Equal(t, restapi.DescriptionTruncated, response.Warnings[0].Kind)
+			require.Equal(t, presetLayout.FontSize, response.Warnings[0].FontSize)
+		})
+
+		t.Run("Layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithDescription(longDescription), aux.WithFrozen(false), aux.WithHidden(false))
+			layout := setup.LabelLayout("Avery L7160 21-up", aux.WithGrid(3, 7))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				LayoutId: &layout.LayoutId,
+				ItemIds:  []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.CheckLabelsSuccessResponse](t, writer.Body.String())
+			require.Len(t, response.Warnings, 1)
+			require.Equal(t, restapi.DescriptionTruncated, response.Warnings[0].Kind)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("No items", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "missing_items")
+		})
+
+		t.Run("Items of other seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			otherSeller := setup.Seller()
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(otherSeller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_seller")
+		})
+
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelCheck(), &restapi.CheckLabelsPayload{
+				Layout:  &presetLayout,
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
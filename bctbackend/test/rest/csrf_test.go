@@ -0,0 +1,143 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/queries"
+	"bctbackend/security"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRF(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Session with CSRF token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest[any](path.ItemFreeze(item.ItemID), nil, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+		})
+
+		t.Run("Bearer token without CSRF token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, token := setup.ApiToken(setup.Admin())
+			item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest[any](path.ItemFreeze(item.ItemID), nil, WithBearerToken(token))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+		})
+
+		t.Run("Non-mutating request without CSRF token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreateGetRequest(path.Items(), WithSessionCookieOnly(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+
+		t.Run("Token returned by login", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			request := CreatePostRequest(path.Login(), &rest.LoginRequest{Username: seller.UserId.String(), Password: seller.Password})
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			var sessionId string
+			var csrfCookie *http.Cookie
+			for _, cookie := range writer.Result().Cookies() {
+				switch cookie.Name {
+				case security.SessionCookieName:
+					sessionId = cookie.Value
+				case security.CSRFCookieName:
+					csrfCookie = cookie
+				}
+			}
+
+			require.NotEmpty(t, sessionId)
+			require.NotNil(t, csrfCookie)
+			require.False(t, csrfCookie.HttpOnly, "Frontend must be able to read the CSRF token")
+
+			response := FromJson[rest.LoginSuccessResponse](t, writer.Body.String())
+			require.Equal(t, csrfCookie.Value, response.CSRFToken)
+			require.NotEqual(t, sessionId, response.CSRFToken)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Missing CSRF token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest[any](path.ItemFreeze(item.ItemID), nil, WithSessionCookieOnly(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_csrf_token")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("CSRF token of other session", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			_, otherSessionId := setup.LoggedIn(setup.Admin())
+			item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest[any](
+				path.ItemFreeze(item.ItemID),
+				nil,
+				WithSessionCookieOnly(sessionId),
+				WithHeader(security.CSRFHeaderName, security.DeriveCSRFToken(otherSessionId)))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_csrf_token")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("Logout without CSRF token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreatePostRequest(path.Logout(), &rest.LogoutPayload{}, WithSessionCookieOnly(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_csrf_token")
+
+			_, err := queries.GetSessionById(setup.Db, sessionId)
+			require.NoError(t, err)
+		})
+
+		t.Run("Delete without CSRF token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreateDeleteRequest(path.UserSessions(admin.UserId), WithSessionCookieOnly(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_csrf_token")
+		})
+	})
+}
@@ -0,0 +1,236 @@
+//go:build test
+
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"bctbackend/database/models"
+	"bctbackend/server"
+	"bctbackend/server/events"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	"bctbackend/server/sse"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+type serverSentEvent struct {
+	Id   string
+	Name string
+	Data string
+}
+
+// openEventStream connects to the event stream of the server and waits until the server has registered the connection.
+func openEventStream(t *testing.T, router *server.Server, options ...func(*http.Request)) (*http.Response, *bufio.Reader) {
+	httpServer := httptest.NewServer(router)
+	t.Cleanup(httpServer.Close)
+
+	subscriberCount := router.EventStreamSubscriberCount()
+
+	requestContext, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	request := CreateGetRequest(path.Events(), options...)
+	request = request.WithContext(requestContext)
+	request.URL, _ = request.URL.Parse(httpServer.URL + path.Events().String())
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { response.Body.Close() })
+
+	if response.StatusCode == http.StatusOK {
+		require.Eventually(t, func() bool { return router.EventStreamSubscriberCount() == subscriberCount+1 }, time.Second, time.Millisecond)
+	}
+
+	return response, bufio.NewReader(response.Body)
+}
+
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) *serverSentEvent {
+	var event serverSentEvent
+
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return &event
+		}
+
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			event.Id = value
+		case "event":
+			event.Name = value
+		case "data":
+			event.Data = value
+		}
+	}
+}
+
+func readStreamedEvent[T any](t *testing.T, reader *bufio.Reader, expectedType events.Type) (string, *T) {
+	serverSentEvent := readServerSentEvent(t, reader)
+	require.Empty(t, serverSentEvent.Name)
+
+	var event receivedEvent
+	require.NoError(t, json.Unmarshal([]byte(serverSentEvent.Data), &event))
+	require.Equal(t, expectedType, event.Type)
+
+	var payload T
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	return serverSentEvent.Id, &payload
+}
+
+func freezeItems(t *testing.T, router *server.Server, sessionId models.SessionId, itemIds ...models.Id) {
+	for _, itemId := range itemIds {
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, CreatePostRequest[any](path.ItemFreeze(itemId), nil, WithSessionCookie(sessionId)))
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Receive event", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			response, reader := openEventStream(t, router, WithSessionCookie(sessionId))
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+			freezeItems(t, router, sessionId, item.ItemID)
+
+			eventId, payload := readStreamedEvent[events.ItemsPayload](t, reader, events.ItemsFrozen)
+			require.NotEmpty(t, eventId)
+			require.Equal(t, []models.Id{item.ItemID}, payload.ItemIds)
+		})
+
+		t.Run("Resume from last event id", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			items := setup.Items(setup.Seller().UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			_, reader := openEventStream(t, router, WithSessionCookie(sessionId))
+			freezeItems(t, router, sessionId, items[0].ItemID)
+			lastEventId, _ := readStreamedEvent[events.ItemsPayload](t, reader, events.ItemsFrozen)
+
+			// Events published while the client is disconnected
+			freezeItems(t, router, sessionId, items[1].ItemID, items[2].ItemID)
+
+			_, reader = openEventStream(t, router, WithSessionCookie(sessionId), WithHeader("Last-Event-ID", lastEventId))
+			for _, item := range items[1:] {
+				_, payload := readStreamedEvent[events.ItemsPayload](t, reader, events.ItemsFrozen)
+				require.Equal(t, []models.Id{item.ItemID}, payload.ItemIds)
+			}
+		})
+
+		t.Run("Reset when resuming from unknown event id", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			_, reader := openEventStream(t, router, WithSessionCookie(sessionId), WithHeader("Last-Event-ID", strconv.Itoa(1000)))
+			event := readServerSentEvent(t, reader)
+			require.Equal(t, sse.ResetEventName, event.Name)
+		})
+
+		t.Run("Reset when resuming after restart", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			items := setup.Items(setup.Seller().UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			_, reader := openEventStream(t, router, WithSessionCookie(sessionId))
+			freezeItems(t, router, sessionId, items[0].ItemID)
+			lastEventId, _ := readStreamedEvent[events.ItemsPayload](t, reader, events.ItemsFrozen)
+
+			// The restarted server numbers its events from scratch and has already passed the client's last event id
+			restartedRouter := aux.CreateRestServer(setup.Db)
+			freezeItems(t, restartedRouter, sessionId, items[1].ItemID, items[2].ItemID)
+
+			_, reader = openEventStream(t, restartedRouter, WithSessionCookie(sessionId), WithHeader("Last-Event-ID", lastEventId))
+			event := readServerSentEvent(t, reader)
+			require.Equal(t, sse.ResetEventName, event.Name)
+		})
+
+		t.Run("Seller only receives events about own items", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, adminSessionId := setup.LoggedIn(setup.Admin())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+			otherItem := setup.Item(setup.Seller().UserId, aux.WithDummyData(2), aux.WithFrozen(false), aux.WithHidden(false))
+
+			_, reader := openEventStream(t, router, WithSessionCookie(sellerSessionId))
+			freezeItems(t, router, adminSessionId, otherItem.ItemID, item.ItemID)
+
+			_, payload := readStreamedEvent[events.ItemsPayload](t, reader, events.ItemsFrozen)
+			require.Equal(t, []models.Id{item.ItemID}, payload.ItemIds)
+		})
+
+		t.Run("Seller only sees own items of sale", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, cashierSessionId := setup.LoggedIn(setup.Cashier())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+			otherItem := setup.Item(setup.Seller().UserId, aux.WithDummyData(2), aux.WithHidden(false))
+
+			_, reader := openEventStream(t, router, WithSessionCookie(sellerSessionId))
+
+			writer := httptest.NewRecorder()
+			request := CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{item.ItemID, otherItem.ItemID}}, WithSessionCookie(cashierSessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			_, payload := readStreamedEvent[events.SalePayload](t, reader, events.SaleCreated)
+			require.Equal(t, []models.Id{item.ItemID}, payload.ItemIds)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Not logged in", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			response, _ := openEventStream(t, router)
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		})
+	})
+
+	t.Run("Disconnected on logout", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Seller())
+		_, reader := openEventStream(t, router, WithSessionCookie(sessionId))
+
+		router.ServeHTTP(writer, CreatePostRequest(path.Logout(), &rest.LogoutPayload{}, WithSessionCookie(sessionId)))
+		require.Equal(t, http.StatusOK, writer.Code)
+
+		_, err := reader.ReadString('\n')
+		require.Error(t, err)
+	})
+}
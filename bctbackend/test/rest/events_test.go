@@ -0,0 +1,207 @@
+//go:build test
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bctbackend/database/models"
+	"bctbackend/server"
+	"bctbackend/server/events"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedEvent struct {
+	Type    events.Type     `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// listenForEvents connects to the websocket of the server and waits until the server has registered the connection.
+func listenForEvents(t *testing.T, router *server.Server, sessionId models.SessionId) *websocket.Conn {
+	subscriberCount := router.WebsocketSubscriberCount()
+
+	connection, _, err := DialWebsocket(t, router, WithSessionCookie(sessionId))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return router.WebsocketSubscriberCount() == subscriberCount+1 }, time.Second, time.Millisecond)
+
+	return connection
+}
+
+func receiveEvent[T any](t *testing.T, connection *websocket.Conn, expectedType events.Type) *T {
+	require.NoError(t, connection.SetReadDeadline(time.Now().Add(time.Second)))
+
+	var event receivedEvent
+	require.NoError(t, connection.ReadJSON(&event))
+	require.Equal(t, expectedType, event.Type)
+
+	var payload T
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	return &payload
+}
+
+func TestEvents(t *testing.T) {
+	t.Run("Item added", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		seller, sessionId := setup.LoggedIn(setup.Seller())
+		_, adminSessionId := setup.LoggedIn(setup.Admin())
+		connection := listenForEvents(t, router, adminSessionId)
+
+		price := models.MoneyInCents(100)
+		description := "Shoes"
+		donation := false
+		charity := false
+		payload := rest.AddSellerItemPayload{
+			Price:       &price,
+			Description: &description,
+			CategoryId:  aux.CategoryId_Shoes,
+			Donation:    &donation,
+			Charity:     &charity,
+		}
+		request := CreatePostRequest(path.SellerItems(seller.UserId), &payload, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+		response := FromJson[rest.AddSellerItemResponse](t, writer.Body.String())
+
+		itemAdded := receiveEvent[events.ItemAddedPayload](t, connection, events.ItemAdded)
+		require.Equal(t, response.ItemId, itemAdded.ItemId)
+		require.Equal(t, seller.UserId, itemAdded.SellerId)
+		require.Equal(t, aux.CategoryId_Shoes, itemAdded.CategoryId)
+
+		categoryChanged := receiveEvent[events.CategoryChangedPayload](t, connection, events.CategoryChanged)
+		require.Equal(t, []models.Id{aux.CategoryId_Shoes}, categoryChanged.CategoryIds)
+	})
+
+	t.Run("Item updated", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		seller, sessionId := setup.LoggedIn(setup.Seller())
+		item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+		connection := listenForEvents(t, router, sessionId)
+
+		payload := struct {
+			Description string `json:"description"`
+		}{
+			Description: "new description",
+		}
+		request := CreatePutRequest(path.Item(item.ItemID), &payload, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		itemUpdated := receiveEvent[events.ItemUpdatedPayload](t, connection, events.ItemUpdated)
+		require.Equal(t, item.ItemID, itemUpdated.ItemId)
+		require.Equal(t, seller.UserId, itemUpdated.SellerId)
+	})
+
+	t.Run("Items frozen", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+		connection := listenForEvents(t, router, sessionId)
+
+		request := CreatePostRequest[any](path.ItemFreeze(item.ItemID), nil, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		itemsFrozen := receiveEvent[events.ItemsPayload](t, connection, events.ItemsFrozen)
+		require.Equal(t, []models.Id{item.ItemID}, itemsFrozen.ItemIds)
+	})
+
+	t.Run("Sale created and voided", func(t *testing.T) {
+		setup, router, _ := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		cashier, cashierSessionId := setup.LoggedIn(setup.Cashier())
+		_, adminSessionId := setup.LoggedIn(setup.Admin())
+		seller := setup.Seller()
+		item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+		connection := listenForEvents(t, router, adminSessionId)
+
+		writer := httptest.NewRecorder()
+		request := CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{item.ItemID}}, WithSessionCookie(cashierSessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+		response := FromJson[rest.AddSaleSuccessResponse](t, writer.Body.String())
+
+		saleCreated := receiveEvent[events.SalePayload](t, connection, events.SaleCreated)
+		require.Equal(t, response.SaleId, saleCreated.SaleId)
+		require.Equal(t, cashier.UserId, saleCreated.CashierId)
+		require.Equal(t, []models.Id{item.ItemID}, saleCreated.ItemIds)
+
+		writer = httptest.NewRecorder()
+		router.ServeHTTP(writer, CreateDeleteRequest(path.Sale(response.SaleId), WithSessionCookie(adminSessionId)))
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		saleVoided := receiveEvent[events.SalePayload](t, connection, events.SaleVoided)
+		require.Equal(t, response.SaleId, saleVoided.SaleId)
+		require.Equal(t, cashier.UserId, saleVoided.CashierId)
+		require.Equal(t, []models.Id{item.ItemID}, saleVoided.ItemIds)
+	})
+
+	t.Run("Seller only sees own items of sale", func(t *testing.T) {
+		setup, router, _ := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, cashierSessionId := setup.LoggedIn(setup.Cashier())
+		seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+		item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+		otherItem := setup.Item(setup.Seller().UserId, aux.WithDummyData(2), aux.WithHidden(false))
+		sellerConnection := listenForEvents(t, router, sellerSessionId)
+		cashierConnection := listenForEvents(t, router, cashierSessionId)
+
+		writer := httptest.NewRecorder()
+		request := CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{item.ItemID, otherItem.ItemID}}, WithSessionCookie(cashierSessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+		sellerSaleCreated := receiveEvent[events.SalePayload](t, sellerConnection, events.SaleCreated)
+		require.Equal(t, []models.Id{item.ItemID}, sellerSaleCreated.ItemIds)
+
+		// The cashier handled all items of the sale
+		cashierSaleCreated := receiveEvent[events.SalePayload](t, cashierConnection, events.SaleCreated)
+		require.Equal(t, []models.Id{item.ItemID, otherItem.ItemID}, cashierSaleCreated.ItemIds)
+	})
+
+	t.Run("Failed mutation emits nothing", func(t *testing.T) {
+		setup, router, _ := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		seller, sessionId := setup.LoggedIn(setup.Seller())
+		frozenItem := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(true), aux.WithHidden(false))
+		item := setup.Item(seller.UserId, aux.WithDummyData(2), aux.WithFrozen(false), aux.WithHidden(false))
+		connection := listenForEvents(t, router, sessionId)
+
+		payload := struct {
+			Description string `json:"description"`
+		}{
+			Description: "new description",
+		}
+
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, CreatePutRequest(path.Item(frozenItem.ItemID), &payload, WithSessionCookie(sessionId)))
+		RequireFailureType(t, writer, http.StatusForbidden, "item_frozen")
+
+		writer = httptest.NewRecorder()
+		router.ServeHTTP(writer, CreatePutRequest(path.Item(item.ItemID), &payload, WithSessionCookie(sessionId)))
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		// The first event to arrive must be the one of the successful update
+		itemUpdated := receiveEvent[events.ItemUpdatedPayload](t, connection, events.ItemUpdated)
+		require.Equal(t, item.ItemID, itemUpdated.ItemId)
+	})
+}
@@ -0,0 +1,130 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/models"
+	path "bctbackend/server/paths"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewAdminRoleId(), models.NewSupervisorRoleId(), models.NewVolunteerRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.User(roleId))
+				seller := setup.Seller()
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+				url := path.ItemFreeze(item.ItemID)
+				request := CreatePostRequest[any](url, nil, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+				setup.RequireFrozen(t, item.ItemID)
+			})
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewSellerRoleId(), models.NewCashierRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				user, sessionId := setup.LoggedIn(setup.User(roleId))
+				seller := setup.Seller()
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+				if roleId.IsSeller() {
+					item = setup.Item(user.UserId, aux.WithDummyData(2), aux.WithFrozen(false), aux.WithHidden(false))
+				}
+
+				url := path.ItemFreeze(item.ItemID)
+				request := CreatePostRequest[any](url, nil, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+
+				RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+				setup.RequireNotFrozen(t, item.ItemID)
+			})
+		}
+
+		t.Run("Unknown item", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Volunteer())
+
+			url := path.ItemFreeze(1)
+			request := CreatePostRequest[any](url, nil, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusNotFound, "no_such_item")
+		})
+
+		t.Run("Hidden item", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Volunteer())
+			seller := setup.Seller()
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(true))
+
+			url := path.ItemFreeze(item.ItemID)
+			request := CreatePostRequest[any](url, nil, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "item_hidden")
+		})
+	})
+}
+
+func TestUnfreezeItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewAdminRoleId(), models.NewSupervisorRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.User(roleId))
+				seller := setup.Seller()
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(true), aux.WithHidden(false))
+
+				url := path.ItemUnfreeze(item.ItemID)
+				request := CreatePostRequest[any](url, nil, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+				setup.RequireNotFrozen(t, item.ItemID)
+			})
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewSellerRoleId(), models.NewCashierRoleId(), models.NewVolunteerRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.User(roleId))
+				seller := setup.Seller()
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(true), aux.WithHidden(false))
+
+				url := path.ItemUnfreeze(item.ItemID)
+				request := CreatePostRequest[any](url, nil, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+
+				RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+				setup.RequireFrozen(t, item.ItemID)
+			})
+		}
+	})
+}
@@ -0,0 +1,94 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/models"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCalibrationPage(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Inline layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+
+			request := CreatePostRequest(path.LabelCalibration(), &restapi.GenerateCalibrationPagePayload{
+				Layout: &presetLayout,
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			require.Equal(t, "application/pdf", writer.Header().Get("Content-Type"))
+			require.Equal(t, 1, countPdfPages(writer.Body.String()))
+		})
+
+		t.Run("Layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			layout := setup.LabelLayout("Avery L7160 21-up", aux.WithGrid(3, 7))
+
+			request := CreatePostRequest(path.LabelCalibration(), &restapi.GenerateCalibrationPagePayload{
+				LayoutId: &layout.LayoutId,
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			require.Equal(t, 1, countPdfPages(writer.Body.String()))
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Invalid layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+
+			layout := presetLayout
+			layout.Rows = 0
+			request := CreatePostRequest(path.LabelCalibration(), &restapi.GenerateCalibrationPagePayload{
+				Layout: &layout,
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+		})
+
+		t.Run("Unknown layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+
+			layoutId := models.Id(1)
+			request := CreatePostRequest(path.LabelCalibration(), &restapi.GenerateCalibrationPagePayload{
+				LayoutId: &layoutId,
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_layout")
+		})
+
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+
+			request := CreatePostRequest(path.LabelCalibration(), &restapi.GenerateCalibrationPagePayload{
+				Layout: &presetLayout,
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
@@ -3,11 +3,18 @@
 package rest
 
 import (
+	"compress/zlib"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 
+	"bctbackend/barcode"
 	"bctbackend/database/models"
+	"bctbackend/pdf"
+	"bctbackend/server/configuration"
 	path "bctbackend/server/paths"
 	"bctbackend/server/rest"
 	restapi "bctbackend/server/rest"
@@ -17,6 +24,41 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// countPdfPages counts the pages of a PDF generated by fpdf.
+func countPdfPages(pdf string) int {
+	return strings.Count(pdf, "<</Type /Page\n")
+}
+
+var pdfStreamPattern = regexp.MustCompile(`(?s)\nstream\n(.*?)\nendstream\n`)
+
+// pdfContents inflates the compressed streams of a PDF generated by fpdf, so that its drawing operators can be inspected.
+func pdfContents(t *testing.T, pdf string) string {
+	var contents strings.Builder
+
+	for _, match := range pdfStreamPattern.FindAllStringSubmatch(pdf, -1) {
+		reader, err := zlib.NewReader(strings.NewReader(match[1]))
+		if err != nil {
+			// Not every stream is compressed, e.g., embedded fonts
+			continue
+		}
+		data, _ := io.ReadAll(reader)
+		contents.Write(data)
+	}
+
+	require.NotZero(t, contents.Len())
+	return contents.String()
+}
+
+// countPdfImages counts the images embedded in a PDF generated by fpdf.
+func countPdfImages(pdf string) int {
+	return strings.Count(pdf, "/Subtype /Image")
+}
+
+// hasTranslucentContent checks whether the PDF generated by fpdf contains translucent content, such as a watermark.
+func hasTranslucentContent(pdf string) bool {
+	return strings.Contains(pdf, "/ca ")
+}
+
 func TestGenerateLabels(t *testing.T) {
 	defaultLayout := rest.Layout{
 		PaperWidth:   210,
@@ -40,7 +82,7 @@ func TestGenerateLabels(t *testing.T) {
 
 				url := path.Labels()
 				request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-					Layout:  defaultLayout,
+					Layout:  &defaultLayout,
 					ItemIds: []models.Id{item1.ItemID},
 				}, WithSessionCookie(sessionId))
 				router.ServeHTTP(writer, request)
@@ -48,6 +90,25 @@ func TestGenerateLabels(t *testing.T) {
 				setup.RequireFrozen(t, item1.ItemID)
 			})
 
+			t.Run("Item with long description", func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				description := strings.Repeat("Antique porcelain tea set with floral decorations ", 30)
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithDescription(description), aux.WithFrozen(false), aux.WithHidden(false))
+
+				url := path.Labels()
+				request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
+					Layout:  &defaultLayout,
+					ItemIds: []models.Id{item.ItemID},
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+				require.Equal(t, 1, countPdfPages(writer.Body.String()))
+				setup.RequireFrozen(t, item.ItemID)
+			})
+
 			t.Run("10 items", func(t *testing.T) {
 				setup, router, writer := NewRestFixture(WithDefaultCategories)
 				defer setup.Close()
@@ -59,7 +120,7 @@ func TestGenerateLabels(t *testing.T) {
 
 				url := path.Labels()
 				request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-					Layout:  defaultLayout,
+					Layout:  &defaultLayout,
 					ItemIds: itemIds,
 				}, WithSessionCookie(sessionId))
 				router.ServeHTTP(writer, request)
@@ -84,7 +145,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: itemIds,
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -110,7 +171,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: itemIds,
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -121,6 +182,196 @@ func TestGenerateLabels(t *testing.T) {
 			}
 		})
 
+		t.Run("Partially used sheet", func(t *testing.T) {
+			generate := func(t *testing.T, itemCount int, startCell *rest.Cell, skippedCells []rest.Cell) int {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				items := setup.Items(seller.UserId, itemCount, aux.WithFrozen(false), aux.WithHidden(false))
+
+				request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+					Layout:       &defaultLayout,
+					ItemIds:      models.CollectItemIds(items),
+					StartCell:    startCell,
+					SkippedCells: skippedCells,
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+				return countPdfPages(writer.Body.String())
+			}
+
+			t.Run("Start at first cell", func(t *testing.T) {
+				require.Equal(t, 1, generate(t, 20, &rest.Cell{Column: 0, Row: 0}, nil))
+			})
+
+			t.Run("Start at last cell", func(t *testing.T) {
+				require.Equal(t, 1, generate(t, 1, &rest.Cell{Column: 1, Row: 9}, nil))
+				require.Equal(t, 2, generate(t, 2, &rest.Cell{Column: 1, Row: 9}, nil))
+			})
+
+			t.Run("Start halfway", func(t *testing.T) {
+				require.Equal(t, 1, generate(t, 10, &rest.Cell{Column: 0, Row: 5}, nil))
+				require.Equal(t, 2, generate(t, 11, &rest.Cell{Column: 0, Row: 5}, nil))
+			})
+
+			t.Run("Skipped cells", func(t *testing.T) {
+				require.Equal(t, 1, generate(t, 18, nil, []rest.Cell{{Column: 0, Row: 0}, {Column: 1, Row: 4}}))
+				require.Equal(t, 2, generate(t, 19, nil, []rest.Cell{{Column: 0, Row: 0}, {Column: 1, Row: 4}}))
+			})
+
+			t.Run("Skipped cells only apply to first sheet", func(t *testing.T) {
+				require.Equal(t, 2, generate(t, 39, nil, []rest.Cell{{Column: 0, Row: 0}}))
+				require.Equal(t, 3, generate(t, 40, nil, []rest.Cell{{Column: 0, Row: 0}}))
+			})
+		})
+
+		t.Run("Layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			layout := setup.LabelLayout("Avery L7160 21-up", aux.WithGrid(3, 7))
+			items := setup.Items(seller.UserId, 22, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				LayoutId: &layout.LayoutId,
+				ItemIds:  models.CollectItemIds(items),
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			require.Equal(t, 2, countPdfPages(writer.Body.String()))
+
+			for _, item := range items {
+				setup.RequireFrozen(t, item.ItemID)
+			}
+		})
+
+		t.Run("Preview", func(t *testing.T) {
+			for _, showGrid := range []bool{false, true} {
+				t.Run(fmt.Sprintf("Show grid %v", showGrid), func(t *testing.T) {
+					setup, router, writer := NewRestFixture(WithDefaultCategories)
+					defer setup.Close()
+
+					seller, sessionId := setup.LoggedIn(setup.Seller())
+					items := setup.Items(seller.UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+
+					request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+						Layout:   &defaultLayout,
+						ItemIds:  models.CollectItemIds(items),
+						Preview:  true,
+						ShowGrid: showGrid,
+					}, WithSessionCookie(sessionId))
+					router.ServeHTTP(writer, request)
+					require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+					require.Equal(t, 1, countPdfPages(writer.Body.String()))
+					require.True(t, hasTranslucentContent(writer.Body.String()))
+
+					for _, item := range items {
+						setup.RequireNotFrozen(t, item.ItemID)
+					}
+				})
+			}
+		})
+
+		t.Run("No watermark outside of preview", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			items := setup.Items(seller.UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				Layout:  &defaultLayout,
+				ItemIds: models.CollectItemIds(items),
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			require.False(t, hasTranslucentContent(writer.Body.String()))
+		})
+
+		t.Run("Barcode renderers", func(t *testing.T) {
+			// The charity and donation icons are always embedded
+			const iconCount = 2
+
+			for _, testCase := range []struct {
+				renderer   pdf.BarcodeRenderer
+				showText   bool
+				imageCount int
+			}{
+				{renderer: pdf.VectorBarcodes, showText: false, imageCount: iconCount},
+				{renderer: pdf.VectorBarcodes, showText: true, imageCount: iconCount},
+				{renderer: pdf.RasterBarcodes, showText: false, imageCount: iconCount + 5},
+			} {
+				t.Run(fmt.Sprintf("%s, show text %v", testCase.renderer, testCase.showText), func(t *testing.T) {
+					setup, _, writer := NewRestFixture(WithDefaultCategories)
+					defer setup.Close()
+
+					router := aux.CreateRestServer(setup.Db, func(configuration *configuration.Configuration) {
+						configuration.BarcodeRenderer = testCase.renderer
+						configuration.BarcodeShowText = testCase.showText
+					})
+
+					seller, sessionId := setup.LoggedIn(setup.Seller())
+					items := setup.Items(seller.UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+
+					request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+						Layout:  &defaultLayout,
+						ItemIds: models.CollectItemIds(items),
+					}, WithSessionCookie(sessionId))
+					router.ServeHTTP(writer, request)
+					require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+					require.Equal(t, testCase.imageCount, countPdfImages(writer.Body.String()))
+				})
+			}
+		})
+
+		t.Run("Symbologies", func(t *testing.T) {
+			for _, renderer := range []pdf.BarcodeRenderer{pdf.VectorBarcodes, pdf.RasterBarcodes} {
+				for _, symbology := range barcode.Symbologies {
+					t.Run(fmt.Sprintf("%s, %s", renderer, symbology), func(t *testing.T) {
+						setup, _, writer := NewRestFixture(WithDefaultCategories)
+						defer setup.Close()
+
+						router := aux.CreateRestServer(setup.Db, func(configuration *configuration.Configuration) {
+							configuration.BarcodeRenderer = renderer
+						})
+
+						seller, sessionId := setup.LoggedIn(setup.Seller())
+						items := setup.Items(seller.UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+						layout := defaultLayout
+						layout.Symbology = string(symbology)
+						request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+							Layout:  &layout,
+							ItemIds: models.CollectItemIds(items),
+						}, WithSessionCookie(sessionId))
+						router.ServeHTTP(writer, request)
+						require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+					})
+				}
+			}
+		})
+
+		t.Run("Symbology of layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+			layout := setup.LabelLayout("QR labels", func(layout *models.LabelLayout) {
+				layout.Symbology = string(barcode.QR)
+			})
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				LayoutId: &layout.LayoutId,
+				ItemIds:  []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+
 		t.Run("Duplicate items", func(t *testing.T) {
 			setup, router, writer := NewRestFixture(WithDefaultCategories)
 			defer setup.Close()
@@ -132,7 +383,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: append(itemIds, itemIds...),
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -142,10 +393,126 @@ func TestGenerateLabels(t *testing.T) {
 				setup.RequireFrozen(t, item.ItemID)
 			}
 		})
+
+		t.Run("ZPL", func(t *testing.T) {
+			t.Run("Without layout", func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				items := setup.Items(seller.UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+				request := CreatePostRequest(path.Labels().Format(restapi.ZplLabelFormat), &restapi.GenerateLabelsPayload{
+					ItemIds: models.CollectItemIds(items),
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+				require.Contains(t, writer.Header().Get("Content-Type"), "text/plain")
+				require.Equal(t, 3, strings.Count(writer.Body.String(), "^XA"))
+				require.Equal(t, 3, strings.Count(writer.Body.String(), "^BC"))
+
+				for _, item := range items {
+					setup.RequireFrozen(t, item.ItemID)
+				}
+			})
+
+			t.Run("Symbology of layout preset", func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+				layout := setup.LabelLayout("QR labels", func(layout *models.LabelLayout) {
+					layout.Symbology = string(barcode.QR)
+				})
+
+				request := CreatePostRequest(path.Labels().Format(restapi.ZplLabelFormat), &restapi.GenerateLabelsPayload{
+					LayoutId: &layout.LayoutId,
+					ItemIds:  []models.Id{item.ItemID},
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+				require.Contains(t, writer.Body.String(), "^BQ")
+				require.NotContains(t, writer.Body.String(), "^BC")
+			})
+
+			t.Run("Preview", func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+				request := CreatePostRequest(path.Labels().Format(restapi.ZplLabelFormat), &restapi.GenerateLabelsPayload{
+					ItemIds: []models.Id{item.ItemID},
+					Preview: true,
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+				require.Contains(t, writer.Body.String(), pdf.PreviewWatermark)
+				setup.RequireNotFrozen(t, item.ItemID)
+			})
+
+			t.Run("Explicit PDF format", func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+				request := CreatePostRequest(path.Labels().Format(restapi.PdfLabelFormat), &restapi.GenerateLabelsPayload{
+					Layout:  &defaultLayout,
+					ItemIds: []models.Id{item.ItemID},
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+				require.Equal(t, 1, countPdfPages(writer.Body.String()))
+			})
+		})
 	})
 
 	t.Run("Failure", func(t *testing.T) {
 		t.Run("No items listed", func(t *testing.T) {
+			t.Run("Unknown format", func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+				request := CreatePostRequest(path.Labels().Format("epl"), &restapi.GenerateLabelsPayload{
+					Layout:  &defaultLayout,
+					ItemIds: []models.Id{item.ItemID},
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				RequireFailureType(t, writer, http.StatusBadRequest, "unknown_label_format")
+				setup.RequireNotFrozen(t, item.ItemID)
+			})
+
+			t.Run("Sheet options with ZPL", func(t *testing.T) {
+				payloads := map[string]restapi.GenerateLabelsPayload{
+					"Start cell":    {StartCell: &rest.Cell{Column: 1, Row: 0}},
+					"Skipped cells": {SkippedCells: []rest.Cell{{Column: 0, Row: 0}}},
+					"Grid":          {ShowGrid: true},
+				}
+
+				for name, payload := range payloads {
+					t.Run(name, func(t *testing.T) {
+						setup, router, writer := NewRestFixture(WithDefaultCategories)
+						defer setup.Close()
+
+						seller, sessionId := setup.LoggedIn(setup.Seller())
+						item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+						payload.ItemIds = []models.Id{item.ItemID}
+						request := CreatePostRequest(path.Labels().Format(restapi.ZplLabelFormat), &payload, WithSessionCookie(sessionId))
+						router.ServeHTTP(writer, request)
+						RequireFailureType(t, writer, http.StatusBadRequest, "invalid_request")
+						setup.RequireNotFrozen(t, item.ItemID)
+					})
+				}
+			})
+
 			setup, router, writer := NewRestFixture(WithDefaultCategories)
 			defer setup.Close()
 
@@ -155,7 +522,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{},
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -178,7 +545,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{nonexistendItemId},
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -200,7 +567,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{items[0].ItemID},
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -222,7 +589,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{items[0].ItemID},
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -244,7 +611,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{items[0].ItemID},
 			}, WithSessionCookie(sessionId))
 			router.ServeHTTP(writer, request)
@@ -265,7 +632,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{items[0].ItemID},
 			})
 			router.ServeHTTP(writer, request)
@@ -286,7 +653,7 @@ func TestGenerateLabels(t *testing.T) {
 
 			url := path.Labels()
 			request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-				Layout:  defaultLayout,
+				Layout:  &defaultLayout,
 				ItemIds: []models.Id{items[0].ItemID},
 			}, WithSessionCookie("fake_session_id"))
 			router.ServeHTTP(writer, request)
@@ -297,6 +664,105 @@ func TestGenerateLabels(t *testing.T) {
 			}
 		})
 
+		t.Run("Invalid cell", func(t *testing.T) {
+			payloads := []restapi.GenerateLabelsPayload{
+				{StartCell: &rest.Cell{Column: 2, Row: 0}},
+				{StartCell: &rest.Cell{Column: 0, Row: 10}},
+				{StartCell: &rest.Cell{Column: -1, Row: 0}},
+				{SkippedCells: []rest.Cell{{Column: 0, Row: 0}, {Column: 0, Row: 10}}},
+				{SkippedCells: []rest.Cell{{Column: 0, Row: -1}}},
+				{StartCell: &rest.Cell{Column: 1, Row: 9}, SkippedCells: []rest.Cell{{Column: 1, Row: 9}}},
+			}
+
+			for _, payload := range payloads {
+				testLabel := fmt.Sprintf("Start %v, skipped %v", payload.StartCell, payload.SkippedCells)
+				t.Run(testLabel, func(t *testing.T) {
+					setup, router, writer := NewRestFixture(WithDefaultCategories)
+					defer setup.Close()
+
+					seller, sessionId := setup.LoggedIn(setup.Seller())
+					items := setup.Items(seller.UserId, 10, aux.WithFrozen(false), aux.WithHidden(false))
+
+					payload.Layout = &defaultLayout
+					payload.ItemIds = models.CollectItemIds(items)
+					router.ServeHTTP(writer, CreatePostRequest(path.Labels(), &payload, WithSessionCookie(sessionId)))
+					RequireFailureType(t, writer, http.StatusBadRequest, "invalid_cell")
+
+					for _, item := range items {
+						setup.RequireNotFrozen(t, item.ItemID)
+					}
+				})
+			}
+		})
+
+		t.Run("Unknown layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			layoutId := models.Id(1)
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				LayoutId: &layoutId,
+				ItemIds:  []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_layout")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("Both layout and layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+			layout := setup.LabelLayout("Herma 4226")
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				Layout:   &defaultLayout,
+				LayoutId: &layout.LayoutId,
+				ItemIds:  []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("Unknown symbology", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			layout := defaultLayout
+			layout.Symbology = "aztec"
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				Layout:  &layout,
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("Neither layout nor layout preset", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
 		t.Run("Invalid layout", func(t *testing.T) {
 			layouts := []rest.Layout{
 				{
@@ -533,7 +999,7 @@ func TestGenerateLabels(t *testing.T) {
 
 					url := path.Labels()
 					request := CreatePostRequest(url, &restapi.GenerateLabelsPayload{
-						Layout:  layout,
+						Layout:  &layout,
 						ItemIds: itemIds,
 					}, WithSessionCookie(sessionId))
 					router.ServeHTTP(writer, request)
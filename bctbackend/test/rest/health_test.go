@@ -0,0 +1,169 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bctbackend/database/models"
+	"bctbackend/server/configuration"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	withHTMLPath := func(htmlPath string) func(*configuration.Configuration) {
+		return func(configuration *configuration.Configuration) {
+			configuration.HTMLPath = htmlPath
+		}
+	}
+
+	createHTMLFile := func(t *testing.T) string {
+		htmlPath := filepath.Join(t.TempDir(), "index.html")
+		require.NoError(t, os.WriteFile(htmlPath, []byte("<html></html>"), 0644))
+		return htmlPath
+	}
+
+	t.Run("Healthz", func(t *testing.T) {
+		setup, router, writer := NewRestFixture()
+		defer setup.Close()
+
+		router.ServeHTTP(writer, CreateGetRequest(path.Healthz()))
+		require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+	})
+
+	t.Run("Readyz", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			setup, _ := NewDatabaseFixture()
+			defer setup.Close()
+
+			router := aux.CreateRestServer(setup.Db, withHTMLPath(createHTMLFile(t)))
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Readyz()))
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("Missing HTML file", func(t *testing.T) {
+				setup, _ := NewDatabaseFixture()
+				defer setup.Close()
+
+				router := aux.CreateRestServer(setup.Db, withHTMLPath(filepath.Join(t.TempDir(), "missing.html")))
+				writer := httptest.NewRecorder()
+				router.ServeHTTP(writer, CreateGetRequest(path.Readyz()))
+				RequireFailureType(t, writer, http.StatusServiceUnavailable, "not_ready")
+			})
+
+			t.Run("Missing table", func(t *testing.T) {
+				setup, _ := NewDatabaseFixture()
+				defer setup.Close()
+
+				_, err := setup.Db.Exec("DROP TABLE api_tokens")
+				require.NoError(t, err)
+
+				router := aux.CreateRestServer(setup.Db, withHTMLPath(createHTMLFile(t)))
+				writer := httptest.NewRecorder()
+				router.ServeHTTP(writer, CreateGetRequest(path.Readyz()))
+				RequireFailureType(t, writer, http.StatusServiceUnavailable, "not_ready")
+			})
+
+			t.Run("Outdated schema", func(t *testing.T) {
+				setup, _ := NewDatabaseFixture()
+				defer setup.Close()
+
+				// Databases created before schema versions were recorded have version 0
+				_, err := setup.Db.Exec("PRAGMA user_version = 0")
+				require.NoError(t, err)
+
+				router := aux.CreateRestServer(setup.Db, withHTMLPath(createHTMLFile(t)))
+				writer := httptest.NewRecorder()
+				router.ServeHTTP(writer, CreateGetRequest(path.Readyz()))
+				RequireFailureType(t, writer, http.StatusServiceUnavailable, "not_ready")
+				require.Contains(t, writer.Body.String(), "schema version")
+			})
+
+			t.Run("Closed database", func(t *testing.T) {
+				setup, _ := NewDatabaseFixture()
+				router := aux.CreateRestServer(setup.Db, withHTMLPath(createHTMLFile(t)))
+				setup.Close()
+
+				writer := httptest.NewRecorder()
+				router.ServeHTTP(writer, CreateGetRequest(path.Readyz()))
+				RequireFailureType(t, writer, http.StatusServiceUnavailable, "not_ready")
+			})
+		})
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			admin := setup.Admin()
+			_, cashierSessionId := setup.LoggedIn(setup.Cashier())
+			_, token := setup.ApiToken(admin, aux.WithActions("view_metrics"))
+			item := setup.Item(setup.Seller().UserId, aux.WithDummyData(1), aux.WithPriceInCents(250), aux.WithHidden(false))
+
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{item.ItemID}}, WithSessionCookie(cashierSessionId)))
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			writer = httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Item(item.ItemID)))
+			RequireFailureType(t, writer, http.StatusUnauthorized, "missing_session_id")
+
+			writer = httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Metrics(), WithBearerToken(token)))
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			require.Contains(t, writer.Header().Get("Content-Type"), "text/plain")
+
+			metrics := writer.Body.String()
+			require.Contains(t, metrics, `bct_http_requests_total{method="POST",route="/api/v1/sales",status="201"} 1`)
+			require.Contains(t, metrics, `bct_http_request_duration_seconds_count{method="POST",route="/api/v1/sales"} 1`)
+			require.Contains(t, metrics, `bct_http_requests_total{method="GET",route="/api/v1/items/:id",status="401"} 1`)
+			require.Contains(t, metrics, `bct_failure_responses_total{type="missing_session_id"} 1`)
+			require.Contains(t, metrics, "bct_sales_created_total 1\n")
+			require.Contains(t, metrics, "bct_revenue_cents 250\n")
+			require.Contains(t, metrics, "bct_websocket_subscribers 0\n")
+			require.Contains(t, metrics, "bct_sqlite_busy_errors_total 0\n")
+		})
+
+		t.Run("Requests rejected by CORS", func(t *testing.T) {
+			setup, _ := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			router := aux.CreateRestServer(setup.Db, func(configuration *configuration.Configuration) {
+				configuration.AllowedOrigins = []string{"https://bct.example.com"}
+			})
+			_, token := setup.ApiToken(setup.Admin(), aux.WithActions("view_metrics"))
+
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Items(), WithHeader("Origin", "https://evil.example.com")))
+			require.Equal(t, http.StatusForbidden, writer.Code)
+
+			writer = httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Metrics(), WithBearerToken(token)))
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			require.Contains(t, writer.Body.String(), `bct_http_requests_total{method="GET",route="/api/v1/items",status="403"} 1`)
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("As seller", func(t *testing.T) {
+				setup, router, writer := NewRestFixture()
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.Seller())
+				router.ServeHTTP(writer, CreateGetRequest(path.Metrics(), WithSessionCookie(sessionId)))
+				RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+			})
+		})
+	})
+}
@@ -0,0 +1,466 @@
+//go:build test
+
+package rest
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bctbackend/database/models"
+	"bctbackend/server"
+	"bctbackend/server/events"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runLabelJob starts a label job, waits for it to finish and returns its final state.
+func runLabelJob(t *testing.T, router *server.Server, sessionId models.SessionId, payload *restapi.AddLabelJobPayload) *restapi.LabelJobData {
+	writer := httptest.NewRecorder()
+	request := CreatePostRequest(path.LabelJobs(), payload, WithSessionCookie(sessionId))
+	router.ServeHTTP(writer, request)
+	require.Equal(t, http.StatusAccepted, writer.Code, writer.Body.String())
+	job := FromJson[restapi.LabelJobData](t, writer.Body.String())
+
+	router.WaitForJobs()
+
+	writer = httptest.NewRecorder()
+	request = CreateGetRequest(path.LabelJob(job.JobId), WithSessionCookie(sessionId))
+	router.ServeHTTP(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+	return FromJson[restapi.LabelJobData](t, writer.Body.String())
+}
+
+func downloadLabelJobResult(t *testing.T, router *server.Server, sessionId models.SessionId, jobId models.Id) *httptest.ResponseRecorder {
+	writer := httptest.NewRecorder()
+	request := CreateGetRequest(path.LabelJobResult(jobId), WithSessionCookie(sessionId))
+	router.ServeHTTP(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+	return writer
+}
+
+// countPdfPagesInArchive opens the ZIP archive and counts the pages of each PDF in it.
+func countPdfPagesInArchive(t *testing.T, archive []byte) map[string]int {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	pageCounts := map[string]int{}
+	for _, file := range reader.File {
+		contents, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(contents)
+		require.NoError(t, err)
+		require.NoError(t, contents.Close())
+
+		pageCounts[file.Name] = countPdfPages(string(data))
+	}
+
+	return pageCounts
+}
+
+func TestLabelJobs(t *testing.T) {
+	defaultLayout := restapi.Layout{
+		PaperWidth:   210,
+		PaperHeight:  297,
+		PaperMargins: restapi.Insets{Top: 10, Bottom: 10, Left: 10, Right: 10},
+		Columns:      2,
+		Rows:         10,
+		LabelMargins: restapi.Insets{Top: 10, Bottom: 10, Left: 10, Right: 10},
+		LabelPadding: restapi.Insets{Top: 10, Bottom: 10, Left: 10, Right: 10},
+		FontSize:     12,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Combined", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller := setup.Seller(aux.WithUserId(301))
+			otherSeller := setup.Seller(aux.WithUserId(302))
+			items := setup.Items(seller.UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+			otherItems := setup.Items(otherSeller.UserId, 25, aux.WithFrozen(false), aux.WithHidden(false))
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout: &defaultLayout,
+				Output: restapi.CombinedLabelJobOutput,
+			})
+			require.Equal(t, "succeeded", job.Status)
+			require.Equal(t, 28, job.Done)
+			require.Equal(t, 28, job.Total)
+			require.NotNil(t, job.FinishedAt)
+
+			writer := downloadLabelJobResult(t, router, sessionId, job.JobId)
+			require.Equal(t, "application/pdf", writer.Header().Get("Content-Type"))
+
+			// Separator and one sheet for the first seller, separator and two sheets for the second
+			require.Equal(t, 5, countPdfPages(writer.Body.String()))
+
+			for _, item := range append(items, otherItems...) {
+				setup.RequireNotFrozen(t, item.ItemID)
+			}
+		})
+
+		t.Run("Per seller", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller := setup.Seller(aux.WithUserId(301))
+			otherSeller := setup.Seller(aux.WithUserId(302))
+			setup.Items(seller.UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(otherSeller.UserId, 25, aux.WithFrozen(false), aux.WithHidden(false))
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout: &defaultLayout,
+				Output: restapi.PerSellerLabelJobOutput,
+			})
+			require.Equal(t, "succeeded", job.Status)
+
+			writer := downloadLabelJobResult(t, router, sessionId, job.JobId)
+			require.Equal(t, "application/zip", writer.Header().Get("Content-Type"))
+			require.Equal(t, map[string]int{"seller-301.pdf": 1, "seller-302.pdf": 2}, countPdfPagesInArchive(t, writer.Body.Bytes()))
+		})
+
+		t.Run("Zone", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(399)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(401)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+
+			zone := 3
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout: &defaultLayout,
+				Zone:   &zone,
+				Output: restapi.PerSellerLabelJobOutput,
+			})
+			require.Equal(t, 4, job.Total)
+
+			writer := downloadLabelJobResult(t, router, sessionId, job.JobId)
+			require.Equal(t, map[string]int{"seller-301.pdf": 1, "seller-399.pdf": 1}, countPdfPagesInArchive(t, writer.Body.Bytes()))
+		})
+
+		t.Run("Seller range", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(300)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(340)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(341)).UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+
+			firstSellerId := models.Id(301)
+			lastSellerId := models.Id(340)
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout:        &defaultLayout,
+				FirstSellerId: &firstSellerId,
+				LastSellerId:  &lastSellerId,
+				Output:        restapi.PerSellerLabelJobOutput,
+			})
+			require.Equal(t, 4, job.Total)
+
+			writer := downloadLabelJobResult(t, router, sessionId, job.JobId)
+			require.Equal(t, map[string]int{"seller-301.pdf": 1, "seller-340.pdf": 1}, countPdfPagesInArchive(t, writer.Body.Bytes()))
+		})
+
+		t.Run("Unfrozen only", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller := setup.Seller(aux.WithUserId(301))
+			setup.Items(seller.UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(seller.UserId, 3, aux.WithFrozen(true), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(302)).UserId, 2, aux.WithFrozen(true), aux.WithHidden(false))
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout:       &defaultLayout,
+				UnfrozenOnly: true,
+				Output:       restapi.PerSellerLabelJobOutput,
+			})
+			require.Equal(t, 2, job.Total)
+
+			writer := downloadLabelJobResult(t, router, sessionId, job.JobId)
+			require.Equal(t, map[string]int{"seller-301.pdf": 1}, countPdfPagesInArchive(t, writer.Body.Bytes()))
+		})
+
+		t.Run("Item ids", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			items := setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+			otherItems := setup.Items(setup.Seller(aux.WithUserId(302)).UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(setup.Seller(aux.WithUserId(303)).UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout:  &defaultLayout,
+				ItemIds: []models.Id{otherItems[0].ItemID, items[1].ItemID, items[0].ItemID},
+				Output:  restapi.PerSellerLabelJobOutput,
+			})
+			require.Equal(t, 3, job.Total)
+
+			writer := downloadLabelJobResult(t, router, sessionId, job.JobId)
+			require.Equal(t, map[string]int{"seller-301.pdf": 1, "seller-302.pdf": 1}, countPdfPagesInArchive(t, writer.Body.Bytes()))
+		})
+
+		t.Run("Hidden items are skipped", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller := setup.Seller(aux.WithUserId(301))
+			setup.Items(seller.UserId, 2, aux.WithFrozen(false), aux.WithHidden(false))
+			setup.Items(seller.UserId, 2, aux.WithFrozen(false), aux.WithHidden(true))
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{Layout: &defaultLayout})
+			require.Equal(t, 2, job.Total)
+		})
+
+		t.Run("Freeze", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			items := setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+			otherItems := setup.Items(setup.Seller(aux.WithUserId(401)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			zone := 3
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{
+				Layout: &defaultLayout,
+				Zone:   &zone,
+				Freeze: true,
+			})
+			require.Equal(t, "succeeded", job.Status)
+
+			for _, item := range items {
+				setup.RequireFrozen(t, item.ItemID)
+			}
+			for _, item := range otherItems {
+				setup.RequireNotFrozen(t, item.ItemID)
+			}
+		})
+
+		t.Run("Layout preset", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+			layout := setup.LabelLayout("Avery")
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{LayoutId: &layout.LayoutId})
+			require.Equal(t, "succeeded", job.Status)
+		})
+
+		t.Run("Progress events", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+			connection := listenForEvents(t, router, sessionId)
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{Layout: &defaultLayout})
+
+			progress := []int{}
+			for {
+				update := receiveEvent[events.JobPayload](t, connection, events.JobUpdated)
+				require.Equal(t, job.JobId, update.JobId)
+				require.Equal(t, 5, update.Total)
+				progress = append(progress, update.Done)
+
+				if update.Status != "running" {
+					require.Equal(t, "succeeded", update.Status)
+					break
+				}
+			}
+			require.Equal(t, []int{0, 1, 2, 3, 4, 5, 5}, progress)
+		})
+
+		t.Run("Progress events are only sent to the owner", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			_, otherSessionId := setup.LoggedIn(setup.Admin())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller(aux.WithUserId(301)))
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+			connection := listenForEvents(t, router, otherSessionId)
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{Layout: &defaultLayout})
+			require.Equal(t, "succeeded", job.Status)
+
+			writer := httptest.NewRecorder()
+			payload := struct {
+				Description string `json:"description"`
+			}{
+				Description: "new description",
+			}
+			router.ServeHTTP(writer, CreatePutRequest(path.Item(item.ItemID), &payload, WithSessionCookie(sellerSessionId)))
+			require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+			// The first event to arrive must be the item update, not the progress of the other admin's job
+			itemUpdated := receiveEvent[events.ItemUpdatedPayload](t, connection, events.ItemUpdated)
+			require.Equal(t, item.ItemID, itemUpdated.ItemId)
+		})
+
+		t.Run("List jobs", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			job := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{Layout: &defaultLayout})
+			otherJob := runLabelJob(t, router, sessionId, &restapi.AddLabelJobPayload{Layout: &defaultLayout})
+
+			request := CreateGetRequest(path.LabelJobs(), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetLabelJobsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, []*restapi.LabelJobData{job, otherJob}, response.Jobs)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			setup.Items(seller.UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{Layout: &defaultLayout}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			setup.Items(setup.Seller().UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{Layout: &defaultLayout}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+
+		t.Run("No items selected", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			zone := 4
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{Layout: &defaultLayout, Zone: &zone}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "missing_items")
+		})
+
+		t.Run("Unknown item", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			nonexistentItem := models.Id(1)
+			setup.RequireNoSuchItems(t, nonexistentItem)
+
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{Layout: &defaultLayout, ItemIds: []models.Id{nonexistentItem}}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "no_such_item")
+		})
+
+		t.Run("Missing layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+		})
+
+		t.Run("Unknown output", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{Layout: &defaultLayout, Output: "perZone"}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "invalid_request")
+		})
+
+		t.Run("Empty seller range", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			setup.Items(setup.Seller(aux.WithUserId(301)).UserId, 3, aux.WithFrozen(false), aux.WithHidden(false))
+
+			firstSellerId := models.Id(340)
+			lastSellerId := models.Id(301)
+			request := CreatePostRequest(path.LabelJobs(), &restapi.AddLabelJobPayload{
+				Layout:        &defaultLayout,
+				FirstSellerId: &firstSellerId,
+				LastSellerId:  &lastSellerId,
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "invalid_request")
+		})
+
+		t.Run("Unknown job", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreateGetRequest(path.LabelJob(1), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_job")
+		})
+
+		t.Run("Result of unknown job", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreateGetRequest(path.LabelJobResult(1), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_job")
+		})
+
+		t.Run("Invalid job id", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreateGetRequest(path.LabelJobStr("abc"), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "invalid_uri_parameters")
+		})
+	})
+}
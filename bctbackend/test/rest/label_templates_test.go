@@ -0,0 +1,193 @@
+//go:build test
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/pdf"
+	"bctbackend/server/configuration"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelTemplates(t *testing.T) {
+	withLabelTemplates := func(configuration *configuration.Configuration) {
+		configuration.LabelTemplates = map[string]*pdf.LabelTemplate{
+			"compact": {
+				Fields: []pdf.TemplateField{
+					{Kind: pdf.BarcodeField, X: 0, Y: 0, Height: 0.5},
+					{Kind: pdf.DescriptionField, X: 0, Y: 0.55, Bold: true},
+					{Kind: pdf.ZoneField, X: 1, Y: 0.55, HorizontalAlignment: pdf.AlignRight, FontSize: 8},
+					{Kind: pdf.TextField, X: 0.5, Y: 1, HorizontalAlignment: pdf.AlignCenter, VerticalAlignment: pdf.AlignBottom, Text: "Spring sale"},
+				},
+			},
+		}
+	}
+
+	layoutWithTemplate := func(template string) *restapi.Layout {
+		layout := presetLayout
+		layout.Template = template
+		return &layout
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		for _, template := range []string{"", "default", "compact"} {
+			t.Run("Inline layout with template "+template, func(t *testing.T) {
+				setup, _, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+				router := aux.CreateRestServer(setup.Db, withLabelTemplates)
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				items := setup.Items(seller.UserId, 5, aux.WithFrozen(false), aux.WithHidden(false), aux.WithCharity(true), aux.WithDonation(true))
+
+				request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+					Layout:  layoutWithTemplate(template),
+					ItemIds: models.CollectItemIds(items),
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+			})
+		}
+
+		t.Run("Bold description", func(t *testing.T) {
+			for _, bold := range []bool{false, true} {
+				t.Run(fmt.Sprintf("Bold %t", bold), func(t *testing.T) {
+					setup, _, writer := NewRestFixture(WithDefaultCategories)
+					defer setup.Close()
+					router := aux.CreateRestServer(setup.Db, func(configuration *configuration.Configuration) {
+						configuration.LabelTemplates = map[string]*pdf.LabelTemplate{
+							"description": {Fields: []pdf.TemplateField{{Kind: pdf.DescriptionField, X: 0, Y: 0, Bold: bold}}},
+						}
+					})
+
+					seller, sessionId := setup.LoggedIn(setup.Seller())
+					item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+					request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+						Layout:  layoutWithTemplate("description"),
+						ItemIds: []models.Id{item.ItemID},
+					}, WithSessionCookie(sessionId))
+					router.ServeHTTP(writer, request)
+					require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+					// Bold text is stroked as well as filled
+					require.Equal(t, bold, strings.Contains(pdfContents(t, writer.Body.String()), "2 Tr"))
+				})
+			}
+		})
+
+		t.Run("Default template places text below the barcode", func(t *testing.T) {
+			// Vertical position in points of the first text drawn, i.e., the description
+			firstTextPosition := func(barHeight float64) float64 {
+				setup, _, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+				router := aux.CreateRestServer(setup.Db, func(configuration *configuration.Configuration) {
+					configuration.BarcodeRenderer = pdf.VectorBarcodes
+					configuration.BarcodeBarHeight = barHeight
+					configuration.BarcodeShowText = false
+				})
+
+				seller, sessionId := setup.LoggedIn(setup.Seller())
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+				request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+					Layout:  layoutWithTemplate("default"),
+					ItemIds: []models.Id{item.ItemID},
+				}, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+				match := regexp.MustCompile(`BT [\d.]+ ([\d.]+) Td`).FindStringSubmatch(pdfContents(t, writer.Body.String()))
+				require.NotNil(t, match)
+				position, err := strconv.ParseFloat(match[1], 64)
+				require.NoError(t, err)
+				return position
+			}
+
+			// PDF coordinates grow upwards, so a higher barcode pushes the text to a lower coordinate
+			millimetersToPoints := 72 / 25.4
+			require.InDelta(t, 5*millimetersToPoints, firstTextPosition(10)-firstTextPosition(15), 0.02)
+		})
+
+		t.Run("Layout preset with template", func(t *testing.T) {
+			setup, _, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+			router := aux.CreateRestServer(setup.Db, withLabelTemplates)
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			layout := setup.LabelLayout("Compact", func(layout *models.LabelLayout) { layout.Template = "compact" })
+			items := setup.Items(seller.UserId, 5, aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				LayoutId: &layout.LayoutId,
+				ItemIds:  models.CollectItemIds(items),
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+
+		t.Run("Add layout preset with template", func(t *testing.T) {
+			setup, _, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+			router := aux.CreateRestServer(setup.Db, withLabelTemplates)
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			payload := restapi.LabelLayoutPayload{Name: "Compact", Layout: *layoutWithTemplate("compact")}
+			router.ServeHTTP(writer, CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId)))
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.AddLabelLayoutSuccessResponse](t, writer.Body.String())
+			layout, err := queries.GetLabelLayoutWithId(setup.Db, response.LayoutId)
+			require.NoError(t, err)
+			require.Equal(t, "compact", layout.Template)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown template in inline layout", func(t *testing.T) {
+			setup, _, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+			router := aux.CreateRestServer(setup.Db, withLabelTemplates)
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+
+			request := CreatePostRequest(path.Labels(), &restapi.GenerateLabelsPayload{
+				Layout:  layoutWithTemplate("fancy"),
+				ItemIds: []models.Id{item.ItemID},
+			}, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_template")
+			setup.RequireNotFrozen(t, item.ItemID)
+		})
+
+		t.Run("Add layout preset with unknown template", func(t *testing.T) {
+			setup, _, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+			router := aux.CreateRestServer(setup.Db, withLabelTemplates)
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			payload := restapi.LabelLayoutPayload{Name: "Fancy", Layout: *layoutWithTemplate("fancy")}
+			router.ServeHTTP(writer, CreatePostRequest(path.LabelLayouts(), &payload, WithSessionCookie(sessionId)))
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_template")
+
+			layouts, err := queries.GetLabelLayouts(setup.Db)
+			require.NoError(t, err)
+			require.Empty(t, layouts)
+		})
+	})
+}
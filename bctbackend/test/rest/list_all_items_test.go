@@ -199,7 +199,7 @@ func TestGetAllItems(t *testing.T) {
 
 	t.Run("Failure", func(t *testing.T) {
 		t.Run("Wrong role", func(t *testing.T) {
-			for _, roleId := range []models.RoleId{models.NewSellerRoleId(), models.NewCashierRoleId()} {
+			for _, roleId := range []models.RoleId{models.NewSellerRoleId(), models.NewCashierRoleId(), models.NewVolunteerRoleId()} {
 				roleString := roleId.Name()
 
 				t.Run("As "+roleString, func(t *testing.T) {
@@ -0,0 +1,58 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListApiTokens(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		seller := setup.Seller()
+		cashier := setup.Cashier()
+		sellerTokenId, sellerToken := setup.ApiToken(seller, aux.WithActions("generate_labels"))
+		cashierTokenId, _ := setup.ApiToken(cashier, aux.WithTokenExpiration(100))
+
+		url := path.ApiTokens()
+		request := CreateGetRequest(url, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		require.NotContains(t, writer.Body.String(), sellerToken)
+
+		response := FromJson[restapi.GetApiTokensSuccessResponse](t, writer.Body.String())
+		require.Len(t, response.Tokens, 2)
+		require.Equal(t, sellerTokenId, response.Tokens[0].TokenId)
+		require.Equal(t, seller.UserId, response.Tokens[0].UserId)
+		require.Equal(t, []string{"generate_labels"}, response.Tokens[0].Actions)
+		require.Nil(t, response.Tokens[0].ExpirationTime)
+		require.Equal(t, cashierTokenId, response.Tokens[1].TokenId)
+		require.NotNil(t, response.Tokens[1].ExpirationTime)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+
+			url := path.ApiTokens()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
@@ -0,0 +1,53 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListLabelLayouts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Seller())
+		herma := setup.LabelLayout("Herma 4226")
+		avery := setup.LabelLayout("Avery L7160 21-up")
+
+		url := path.LabelLayouts()
+		request := CreateGetRequest(url, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+		response := FromJson[restapi.GetLabelLayoutsSuccessResponse](t, writer.Body.String())
+		require.Len(t, response.Layouts, 2)
+		require.Equal(t, avery.LayoutId, response.Layouts[0].LayoutId)
+		require.Equal(t, avery.Name, response.Layouts[0].Name)
+		require.Equal(t, herma.LayoutId, response.Layouts[1].LayoutId)
+		require.Equal(t, herma.Columns, response.Layouts[1].Layout.Columns)
+		require.Equal(t, herma.PaperMargins.Left, response.Layouts[1].Layout.PaperMargins.Left)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+
+			url := path.LabelLayouts()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
@@ -0,0 +1,131 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/models"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPermissions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("As admin", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			url := path.Permissions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetPermissionsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, models.AdminName, response.Role)
+			require.Equal(t, "any", response.Permissions["list_items"])
+			require.Equal(t, "any", response.Permissions["update_item"])
+			require.Equal(t, "any", response.Permissions["list_sales"])
+			require.NotContains(t, response.Permissions, "add_sale")
+			require.NotContains(t, response.Permissions, "add_seller_item")
+		})
+
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.Permissions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetPermissionsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, models.SellerName, response.Role)
+			require.Equal(t, "own", response.Permissions["update_item"])
+			require.Equal(t, "own", response.Permissions["add_seller_item"])
+			require.Equal(t, "any", response.Permissions["list_categories"])
+			require.NotContains(t, response.Permissions, "list_items")
+			require.NotContains(t, response.Permissions, "add_sale")
+		})
+
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+
+			url := path.Permissions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetPermissionsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, models.CashierName, response.Role)
+			require.Equal(t, "any", response.Permissions["add_sale"])
+			require.Equal(t, "own", response.Permissions["view_sale"])
+			require.Equal(t, "any", response.Permissions["view_item"])
+			require.NotContains(t, response.Permissions, "update_item")
+			require.NotContains(t, response.Permissions, "list_categories")
+		})
+
+		t.Run("As volunteer", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Volunteer())
+
+			url := path.Permissions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetPermissionsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, models.VolunteerName, response.Role)
+			require.Equal(t, "any", response.Permissions["view_item"])
+			require.Equal(t, "any", response.Permissions["freeze_item"])
+			require.NotContains(t, response.Permissions, "unfreeze_item")
+			require.NotContains(t, response.Permissions, "add_sale")
+		})
+
+		t.Run("As supervisor", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Supervisor())
+
+			url := path.Permissions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetPermissionsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, models.SupervisorName, response.Role)
+			require.Equal(t, "any", response.Permissions["void_sale"])
+			require.Equal(t, "any", response.Permissions["unfreeze_item"])
+			require.Equal(t, "any", response.Permissions["list_sales"])
+			require.NotContains(t, response.Permissions, "list_users")
+			require.NotContains(t, response.Permissions, "add_sale")
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Not logged in", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			url := path.Permissions()
+			request := CreateGetRequest(url)
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusUnauthorized, "missing_session_id")
+		})
+	})
+}
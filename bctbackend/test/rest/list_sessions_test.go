@@ -0,0 +1,85 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/algorithms"
+	"bctbackend/database/models"
+	"bctbackend/security"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func deriveSessionHandles(sessionIds ...models.SessionId) []string {
+	return algorithms.Map(sessionIds, security.DeriveSessionHandle)
+}
+
+func TestListSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("As admin", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			_, sellerSessionId := setup.LoggedIn(setup.Seller())
+			_, cashierSessionId := setup.LoggedIn(setup.Cashier())
+			setup.LoggedIn(setup.Seller(), aux.WithExpiration(-1))
+
+			url := path.Sessions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetSessionsSuccessResponse](t, writer.Body.String())
+			sessionHandles := []string{}
+			for _, session := range response.Sessions {
+				sessionHandles = append(sessionHandles, session.SessionHandle)
+				require.Equal(t, session.SessionHandle == security.DeriveSessionHandle(sessionId), session.Current)
+			}
+			require.ElementsMatch(t, deriveSessionHandles(sessionId, sellerSessionId, cashierSessionId), sessionHandles)
+			require.NotContains(t, writer.Body.String(), string(sellerSessionId))
+		})
+
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			_, otherSessionId := setup.LoggedIn(seller)
+			setup.LoggedIn(setup.Admin())
+			setup.LoggedIn(setup.Seller())
+
+			url := path.Sessions()
+			request := CreateGetRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.GetSessionsSuccessResponse](t, writer.Body.String())
+			sessionHandles := []string{}
+			for _, session := range response.Sessions {
+				require.Equal(t, seller.UserId, session.UserId)
+				sessionHandles = append(sessionHandles, session.SessionHandle)
+			}
+			require.ElementsMatch(t, deriveSessionHandles(sessionId, otherSessionId), sessionHandles)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Without cookie", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			url := path.Sessions()
+			request := CreateGetRequest(url)
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusUnauthorized, "missing_session_id")
+		})
+	})
+}
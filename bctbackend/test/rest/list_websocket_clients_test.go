@@ -0,0 +1,96 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"bctbackend/database/models"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWebsocketClients(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("No clients", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			router.ServeHTTP(writer, CreateGetRequest(path.WebsocketClients(), WithSessionCookie(sessionId)))
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[rest.GetWebsocketClientsSuccessResponse](t, writer.Body.String())
+			require.Empty(t, response.Clients)
+		})
+
+		t.Run("Connected clients", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, adminSessionId := setup.LoggedIn(setup.Admin())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+			cashier, cashierSessionId := setup.LoggedIn(setup.Cashier())
+			listenForEvents(t, router, sellerSessionId)
+			listenForEvents(t, router, cashierSessionId)
+
+			router.ServeHTTP(writer, CreateGetRequest(path.WebsocketClients(), WithSessionCookie(adminSessionId)))
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[rest.GetWebsocketClientsSuccessResponse](t, writer.Body.String())
+			require.Len(t, response.Clients, 2)
+
+			for _, expected := range []*models.User{seller, cashier} {
+				index := slices.IndexFunc(response.Clients, func(client *rest.GetWebsocketClientsClientData) bool {
+					return client.UserId == expected.UserId
+				})
+				require.NotEqual(t, -1, index)
+
+				client := response.Clients[index]
+				require.Equal(t, expected.RoleId.Name(), client.Role)
+				require.NotEmpty(t, client.RemoteAddress)
+				require.Zero(t, client.QueueDepth)
+				require.Zero(t, client.DroppedMessages)
+			}
+		})
+
+		t.Run("Closed connections are removed", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, adminSessionId := setup.LoggedIn(setup.Admin())
+			_, sellerSessionId := setup.LoggedIn(setup.Seller())
+			connection := listenForEvents(t, router, sellerSessionId)
+			require.NoError(t, connection.Close())
+
+			require.Eventually(t, func() bool {
+				writer := httptest.NewRecorder()
+				router.ServeHTTP(writer, CreateGetRequest(path.WebsocketClients(), WithSessionCookie(adminSessionId)))
+				response := FromJson[rest.GetWebsocketClientsSuccessResponse](t, writer.Body.String())
+				return len(response.Clients) == 0
+			}, time.Second, 10*time.Millisecond)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewSellerRoleId(), models.NewCashierRoleId(), models.NewVolunteerRoleId(), models.NewSupervisorRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.User(roleId))
+
+				router.ServeHTTP(writer, CreateGetRequest(path.WebsocketClients(), WithSessionCookie(sessionId)))
+				RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+			})
+		}
+	})
+}
@@ -6,11 +6,14 @@ import (
 	"bctbackend/database/models"
 	"bctbackend/database/queries"
 	"bctbackend/security"
+	"bctbackend/server/configuration"
 	path "bctbackend/server/paths"
+	aux "bctbackend/test/helpers"
 	. "bctbackend/test/setup"
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -224,3 +227,62 @@ func TestLogin(t *testing.T) {
 		})
 	})
 }
+
+func TestSessionCookie(t *testing.T) {
+	login := func(t *testing.T, options ...func(*configuration.Configuration)) *http.Cookie {
+		setup, _ := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		router := aux.CreateRestServer(setup.Db, options...)
+		writer := httptest.NewRecorder()
+		seller := setup.Seller()
+
+		form := url.Values{}
+		form.Add("username", seller.UserId.String())
+		form.Add("password", seller.Password)
+
+		request, err := http.NewRequest("POST", path.Login().String(), bytes.NewBufferString(form.Encode()))
+		require.NoError(t, err)
+
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusOK, writer.Code)
+
+		for _, cookie := range writer.Result().Cookies() {
+			if cookie.Name == security.SessionCookieName {
+				return cookie
+			}
+		}
+
+		require.Fail(t, "Expected session_id cookie to be set")
+		return nil
+	}
+
+	t.Run("Plain HTTP", func(t *testing.T) {
+		cookie := login(t)
+
+		require.False(t, cookie.Secure)
+		require.True(t, cookie.HttpOnly)
+		require.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+		require.Empty(t, cookie.Domain)
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		cookie := login(t, func(configuration *configuration.Configuration) {
+			configuration.TLSCertificatePath = "cert.pem"
+			configuration.TLSKeyPath = "key.pem"
+		})
+
+		require.True(t, cookie.Secure)
+		require.True(t, cookie.HttpOnly)
+		require.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	})
+
+	t.Run("Configured domain", func(t *testing.T) {
+		cookie := login(t, func(configuration *configuration.Configuration) {
+			configuration.CookieDomain = "bct.example.com"
+		})
+
+		require.Equal(t, "bct.example.com", cookie.Domain)
+	})
+}
@@ -3,6 +3,7 @@
 package rest
 
 import (
+	"bctbackend/security"
 	path "bctbackend/server/paths"
 	"bctbackend/server/rest"
 	. "bctbackend/test/setup"
@@ -22,4 +23,12 @@ func TestLogout(t *testing.T) {
 	request := CreatePostRequest(url, &rest.LogoutPayload{}, WithSessionCookie(sessionId))
 	router.ServeHTTP(writer, request)
 	require.Equal(t, http.StatusOK, writer.Code)
+
+	cleared := false
+	for _, cookie := range writer.Result().Cookies() {
+		if cookie.Name == security.SessionCookieName {
+			cleared = cookie.MaxAge < 0 && cookie.Value == ""
+		}
+	}
+	require.True(t, cleared, "Expected session cookie to be cleared")
 }
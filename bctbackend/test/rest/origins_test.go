@@ -0,0 +1,108 @@
+//go:build test
+
+package rest
+
+import (
+	"bctbackend/server/configuration"
+	path "bctbackend/server/paths"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigins(t *testing.T) {
+	withAllowedOrigins := func(origins ...string) func(*configuration.Configuration) {
+		return func(configuration *configuration.Configuration) {
+			configuration.AllowedOrigins = origins
+		}
+	}
+
+	withAllOriginsAllowed := func(configuration *configuration.Configuration) {
+		configuration.AllowAllOrigins = true
+	}
+
+	t.Run("CORS", func(t *testing.T) {
+		request := func(t *testing.T, origin string, options ...func(*configuration.Configuration)) *httptest.ResponseRecorder {
+			setup, _ := NewDatabaseFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			router := aux.CreateRestServer(setup.Db, options...)
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Items(), WithSessionCookie(sessionId), WithHeader("Origin", origin)))
+
+			return writer
+		}
+
+		t.Run("Allowed origin", func(t *testing.T) {
+			writer := request(t, "https://bct.example.com", withAllowedOrigins("https://bct.example.com"))
+			require.Equal(t, http.StatusOK, writer.Code)
+			require.Equal(t, "https://bct.example.com", writer.Header().Get("Access-Control-Allow-Origin"))
+		})
+
+		t.Run("Allowed origin with different case", func(t *testing.T) {
+			writer := request(t, "https://BCT.example.com", withAllowedOrigins("https://bct.example.com/"))
+			require.Equal(t, http.StatusOK, writer.Code)
+		})
+
+		t.Run("Disallowed origin", func(t *testing.T) {
+			writer := request(t, "https://evil.example.com", withAllowedOrigins("https://bct.example.com"))
+			require.Equal(t, http.StatusForbidden, writer.Code)
+			require.Empty(t, writer.Header().Get("Access-Control-Allow-Origin"))
+		})
+
+		t.Run("No allowed origins", func(t *testing.T) {
+			writer := request(t, "https://bct.example.com")
+			require.Equal(t, http.StatusForbidden, writer.Code)
+		})
+
+		t.Run("All origins allowed", func(t *testing.T) {
+			writer := request(t, "https://evil.example.com", withAllOriginsAllowed)
+			require.Equal(t, http.StatusOK, writer.Code)
+		})
+	})
+
+	t.Run("Websocket", func(t *testing.T) {
+		dial := func(t *testing.T, origin string, options ...func(*configuration.Configuration)) (*websocket.Conn, *http.Response, error) {
+			setup, _ := NewDatabaseFixture(WithDefaultCategories)
+			t.Cleanup(setup.Close)
+
+			router := aux.CreateRestServer(setup.Db, options...)
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			requestOptions := []func(*http.Request){WithSessionCookie(sessionId)}
+			if origin != "" {
+				requestOptions = append(requestOptions, WithHeader("Origin", origin))
+			}
+
+			return DialWebsocket(t, router, requestOptions...)
+		}
+
+		t.Run("Allowed origin", func(t *testing.T) {
+			_, _, err := dial(t, "https://bct.example.com", withAllowedOrigins("https://bct.example.com"))
+			require.NoError(t, err)
+		})
+
+		t.Run("No origin", func(t *testing.T) {
+			_, _, err := dial(t, "")
+			require.NoError(t, err)
+		})
+
+		t.Run("All origins allowed", func(t *testing.T) {
+			_, _, err := dial(t, "https://evil.example.com", withAllOriginsAllowed)
+			require.NoError(t, err)
+		})
+
+		t.Run("Disallowed origin", func(t *testing.T) {
+			_, response, err := dial(t, "https://evil.example.com", withAllowedOrigins("https://bct.example.com"))
+			require.Error(t, err)
+			require.NotNil(t, response)
+			require.Equal(t, http.StatusForbidden, response.StatusCode)
+		})
+	})
+}
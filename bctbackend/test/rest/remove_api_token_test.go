@@ -0,0 +1,62 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveApiToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		tokenId, token := setup.ApiToken(setup.Cashier())
+
+		url := path.ApiToken(tokenId)
+		request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		_, err := queries.GetApiTokenData(setup.Db, token)
+		require.ErrorIs(t, err, dberr.ErrNoSuchApiToken)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown token", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			url := path.ApiToken(1)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_api_token")
+		})
+
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			tokenId, _ := setup.ApiToken(seller)
+
+			url := path.ApiToken(tokenId)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
@@ -0,0 +1,57 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveLabelLayout(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		layout := setup.LabelLayout("Herma 4226")
+
+		request := CreateDeleteRequest(path.LabelLayout(layout.LayoutId), WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		_, err := queries.GetLabelLayoutWithId(setup.Db, layout.LayoutId)
+		require.ErrorIs(t, err, dberr.ErrNoSuchLabelLayout)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			request := CreateDeleteRequest(path.LabelLayout(1), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_layout")
+		})
+
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			layout := setup.LabelLayout("Herma 4226")
+
+			request := CreateDeleteRequest(path.LabelLayout(layout.LayoutId), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
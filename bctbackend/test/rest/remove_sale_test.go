@@ -0,0 +1,73 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/models"
+	path "bctbackend/server/paths"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveSale(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewAdminRoleId(), models.NewSupervisorRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.User(roleId))
+				seller := setup.Seller()
+				cashier := setup.Cashier()
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+				sale := setup.Sale(cashier.UserId, []models.Id{item.ItemID})
+
+				url := path.Sale(sale.SaleID)
+				request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+				require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+				setup.RequireNoSuchSales(t, sale.SaleID)
+			})
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for _, roleId := range []models.RoleId{models.NewSellerRoleId(), models.NewCashierRoleId(), models.NewVolunteerRoleId()} {
+			t.Run("As "+roleId.Name(), func(t *testing.T) {
+				setup, router, writer := NewRestFixture(WithDefaultCategories)
+				defer setup.Close()
+
+				_, sessionId := setup.LoggedIn(setup.User(roleId))
+				seller := setup.Seller()
+				cashier := setup.Cashier()
+				item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+				sale := setup.Sale(cashier.UserId, []models.Id{item.ItemID})
+
+				url := path.Sale(sale.SaleID)
+				request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+				router.ServeHTTP(writer, request)
+
+				RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+			})
+		}
+
+		t.Run("Unknown sale", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Supervisor())
+
+			url := path.Sale(1)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+
+			RequireFailureType(t, writer, http.StatusNotFound, "no_such_sale")
+		})
+	})
+}
@@ -0,0 +1,101 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/queries"
+	"bctbackend/security"
+	path "bctbackend/server/paths"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveSession(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Own session", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			_, otherSessionId := setup.LoggedIn(seller)
+
+			url := path.SessionStr(security.DeriveSessionHandle(otherSessionId))
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+			_, err := queries.GetSessionById(setup.Db, otherSessionId)
+			require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+
+			_, err = queries.GetSessionById(setup.Db, sessionId)
+			require.NoError(t, err)
+		})
+
+		t.Run("Other user's session as admin", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			_, sellerSessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.SessionStr(security.DeriveSessionHandle(sellerSessionId))
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+			_, err := queries.GetSessionById(setup.Db, sellerSessionId)
+			require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Other user's session as seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			_, otherSessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.SessionStr(security.DeriveSessionHandle(otherSessionId))
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_user")
+
+			_, err := queries.GetSessionById(setup.Db, otherSessionId)
+			require.NoError(t, err)
+		})
+
+		t.Run("Session id instead of handle", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			_, sellerSessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.SessionStr(string(sellerSessionId))
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_session")
+
+			_, err := queries.GetSessionById(setup.Db, sellerSessionId)
+			require.NoError(t, err)
+		})
+
+		t.Run("Nonexistent session", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			url := path.SessionStr("nonexistent")
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_session")
+		})
+	})
+}
@@ -0,0 +1,93 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveUserSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Log out everywhere", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			cashier, sessionId := setup.LoggedIn(setup.Cashier())
+			_, otherSessionId := setup.LoggedIn(cashier)
+
+			url := path.UserSessions(cashier.UserId)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.RemoveUserSessionsSuccessResponse](t, writer.Body.String())
+			require.Equal(t, 2, response.RemovedSessionCount)
+
+			for _, id := range []models.SessionId{sessionId, otherSessionId} {
+				_, err := queries.GetSessionById(setup.Db, id)
+				require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+			}
+		})
+
+		t.Run("Other user as admin", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.UserSessions(seller.UserId)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			_, err := queries.GetSessionById(setup.Db, sellerSessionId)
+			require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+
+			_, err = queries.GetSessionById(setup.Db, sessionId)
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Other user as seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			otherSeller, otherSessionId := setup.LoggedIn(setup.Seller())
+
+			url := path.UserSessions(otherSeller.UserId)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_user")
+
+			_, err := queries.GetSessionById(setup.Db, otherSessionId)
+			require.NoError(t, err)
+		})
+
+		t.Run("Nonexistent user", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			userId := models.Id(999)
+			setup.RequireNoSuchUsers(t, userId)
+
+			url := path.UserSessions(userId)
+			request := CreateDeleteRequest(url, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "no_such_user")
+		})
+	})
+}
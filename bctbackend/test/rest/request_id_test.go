@@ -0,0 +1,72 @@
+//go:build test
+
+package rest
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"bctbackend/server/failure_response"
+	"bctbackend/server/logging"
+	path "bctbackend/server/paths"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestId(t *testing.T) {
+	t.Run("Generated", func(t *testing.T) {
+		setup, router, writer := NewRestFixture()
+		defer setup.Close()
+
+		router.ServeHTTP(writer, CreateGetRequest(path.Healthz()))
+		require.NotEmpty(t, writer.Header().Get(logging.RequestIdHeader))
+	})
+
+	t.Run("Passed along by proxy", func(t *testing.T) {
+		setup, router, writer := NewRestFixture()
+		defer setup.Close()
+
+		router.ServeHTTP(writer, CreateGetRequest(path.Healthz(), WithHeader(logging.RequestIdHeader, "proxy-1234")))
+		require.Equal(t, "proxy-1234", writer.Header().Get(logging.RequestIdHeader))
+	})
+
+	t.Run("Invalid id passed along by proxy", func(t *testing.T) {
+		setup, router, writer := NewRestFixture()
+		defer setup.Close()
+
+		router.ServeHTTP(writer, CreateGetRequest(path.Healthz(), WithHeader(logging.RequestIdHeader, "a b\nc")))
+		requestId := writer.Header().Get(logging.RequestIdHeader)
+		require.NotEmpty(t, requestId)
+		require.NotEqual(t, "a b\nc", requestId)
+	})
+
+	t.Run("Included in failure response", func(t *testing.T) {
+		setup, router, writer := NewRestFixture()
+		defer setup.Close()
+
+		router.ServeHTTP(writer, CreateGetRequest(path.Items()))
+		require.Equal(t, http.StatusUnauthorized, writer.Code)
+
+		response := FromJson[failure_response.FailureResponse](t, writer.Body.String())
+		require.Equal(t, writer.Header().Get(logging.RequestIdHeader), response.RequestId)
+	})
+
+	t.Run("Included in authentication failure logs", func(t *testing.T) {
+		setup, router, writer := NewRestFixture()
+		defer setup.Close()
+
+		var output bytes.Buffer
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&output, nil)))
+		defer slog.SetDefault(defaultLogger)
+
+		request := CreateGetRequest(path.Items(), WithSessionCookie("unknown_session_id"), WithHeader(logging.RequestIdHeader, "proxy-5678"))
+		router.ServeHTTP(writer, request)
+		RequireFailureType(t, writer, http.StatusUnauthorized, "no_such_session")
+
+		require.Regexp(t, `"msg":"Session not found".*"request_id":"proxy-5678"`, output.String())
+	})
+}
@@ -50,6 +50,10 @@ func CreatePutRequest[T any](url *path.URL, payload *T, options ...func(*http.Re
 	return createRequest(HTTP_VERB_PUT, url, payload, options...)
 }
 
+func CreateDeleteRequest(url *path.URL, options ...func(*http.Request)) *http.Request {
+	return createRequest[any](HTTP_VERB_DELETE, url, nil, options...)
+}
+
 func createCookie(name string, value string) *http.Cookie {
 	//exhaustruct:ignore
 	return &http.Cookie{
@@ -75,7 +79,17 @@ func WithCookie(name string, value string) func(*http.Request) {
 	}
 }
 
+// WithSessionCookie authenticates the request the way the frontend does,
+// i.e., it also passes along the CSRF token belonging to the session.
 func WithSessionCookie(sessionId models.SessionId) func(*http.Request) {
+	return func(request *http.Request) {
+		WithSessionCookieOnly(sessionId)(request)
+		request.Header.Set(security.CSRFHeaderName, security.DeriveCSRFToken(sessionId))
+	}
+}
+
+// WithSessionCookieOnly authenticates the request without CSRF token, as a forged cross-site request would.
+func WithSessionCookieOnly(sessionId models.SessionId) func(*http.Request) {
 	return func(request *http.Request) {
 		cookie := createSessionCookie(sessionId)
 		request.AddCookie(cookie)
@@ -88,6 +102,10 @@ func WithHeader(key string, value string) func(*http.Request) {
 	}
 }
 
+func WithBearerToken(token string) func(*http.Request) {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
 func WithContentType(contentType string) func(*http.Request) {
 	return WithHeader("Content-Type", contentType)
 }
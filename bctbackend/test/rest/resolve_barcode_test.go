@@ -0,0 +1,172 @@
+//go:build test
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"bctbackend/barcode"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBarcode(t *testing.T) {
+	payloadOf := func(t *testing.T, setup *RestFixture, itemId models.Id) string {
+		eventPrefix, err := queries.GetEventPrefix(setup.Db)
+		require.NoError(t, err)
+
+		return barcode.FormatPayload(eventPrefix, itemId.Int64())
+	}
+
+	// otherPrefix returns a valid event prefix that differs from the given one
+	otherPrefix := func(prefix string) string {
+		if prefix == "AAA" {
+			return "BBB"
+		}
+		return "AAA"
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Run("As cashier", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+
+			request := CreateGetRequest(path.BarcodeStr(payloadOf(t, &setup, item.ItemID)), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.ResolveBarcodeSuccessResponse](t, writer.Body.String())
+			require.Equal(t, item.ItemID, response.ItemId)
+		})
+
+		t.Run("As owning seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller, sessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+
+			request := CreateGetRequest(path.BarcodeStr(payloadOf(t, &setup, item.ItemID)), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+		})
+
+		t.Run("EAN-13", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+
+			eventPrefix, err := queries.GetEventPrefix(setup.Db)
+			require.NoError(t, err)
+			content, err := barcode.InternalEan13Content(eventPrefix, int(item.ItemID))
+			require.NoError(t, err)
+
+			request := CreateGetRequest(path.BarcodeStr(withEan13CheckDigit(content)), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			response := FromJson[restapi.ResolveBarcodeSuccessResponse](t, writer.Body.String())
+			require.Equal(t, item.ItemID, response.ItemId)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Foreign barcode", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+
+			eventPrefix, err := queries.GetEventPrefix(setup.Db)
+			require.NoError(t, err)
+			payload := barcode.FormatPayload(otherPrefix(eventPrefix), item.ItemID.Int64())
+
+			request := CreateGetRequest(path.BarcodeStr(payload), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "foreign_barcode")
+		})
+
+		t.Run("Corrupted barcode", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			seller := setup.Seller()
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+
+			payload := []byte(payloadOf(t, &setup, item.ItemID))
+			payload[len(payload)-1] = '0' + (payload[len(payload)-1]-'0'+1)%10
+
+			request := CreateGetRequest(path.BarcodeStr(string(payload)), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "corrupted_barcode")
+		})
+
+		t.Run("Malformed barcode", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+
+			request := CreateGetRequest(path.BarcodeStr("1x"), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "malformed_barcode")
+		})
+
+		t.Run("Unknown item", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Cashier())
+			setup.RequireNoSuchItems(t, models.Id(1))
+
+			request := CreateGetRequest(path.BarcodeStr(payloadOf(t, &setup, models.Id(1))), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "no_such_item")
+		})
+
+		t.Run("As nonowner seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			ownerSeller := setup.Seller()
+			item := setup.Item(ownerSeller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+
+			request := CreateGetRequest(path.BarcodeStr(payloadOf(t, &setup, item.ItemID)), WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_seller")
+		})
+	})
+}
+
+// withEan13CheckDigit appends the EAN-13 check digit to the given 12 digits
+func withEan13CheckDigit(digits string) string {
+	sum := 0
+	for index, digit := range digits {
+		weight := 1
+		if index%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(digit-'0')
+	}
+
+	return fmt.Sprintf("%s%d", digits, (10-sum%10)%10)
+}
@@ -0,0 +1,118 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bctbackend/database/queries"
+	"bctbackend/server/configuration"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCache(t *testing.T) {
+	t.Run("Logout invalidates cached session", func(t *testing.T) {
+		setup, router, _ := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, CreateGetRequest(path.Items(), WithSessionCookie(sessionId)))
+		require.Equal(t, http.StatusOK, writer.Code)
+
+		writer = httptest.NewRecorder()
+		router.ServeHTTP(writer, CreatePostRequest(path.Logout(), &rest.LogoutPayload{}, WithSessionCookie(sessionId)))
+		require.Equal(t, http.StatusOK, writer.Code)
+
+		writer = httptest.NewRecorder()
+		router.ServeHTTP(writer, CreateGetRequest(path.Items(), WithSessionCookie(sessionId)))
+		RequireFailureType(t, writer, http.StatusUnauthorized, "no_such_session")
+	})
+
+	t.Run("Revocation invalidates cached session", func(t *testing.T) {
+		setup, router, _ := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, adminSessionId := setup.LoggedIn(setup.Admin())
+		seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, CreateGetRequest(path.SellerItems(seller.UserId), WithSessionCookie(sellerSessionId)))
+		require.Equal(t, http.StatusOK, writer.Code)
+
+		writer = httptest.NewRecorder()
+		router.ServeHTTP(writer, CreateDeleteRequest(path.UserSessions(seller.UserId), WithSessionCookie(adminSessionId)))
+		require.Equal(t, http.StatusOK, writer.Code)
+
+		writer = httptest.NewRecorder()
+		router.ServeHTTP(writer, CreateGetRequest(path.SellerItems(seller.UserId), WithSessionCookie(sellerSessionId)))
+		RequireFailureType(t, writer, http.StatusUnauthorized, "no_such_session")
+	})
+
+	t.Run("Activity is batched", func(t *testing.T) {
+		setup, router, _ := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		admin, sessionId := setup.LoggedIn(setup.Admin())
+
+		for i := 0; i < 10; i++ {
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(path.Items(), WithSessionCookie(sessionId)))
+			require.Equal(t, http.StatusOK, writer.Code)
+		}
+
+		user, err := queries.GetUserWithId(setup.Db, admin.UserId)
+		require.NoError(t, err)
+		require.Nil(t, user.LastActivity)
+
+		require.NoError(t, router.FlushSessionActivity())
+
+		user, err = queries.GetUserWithId(setup.Db, admin.UserId)
+		require.NoError(t, err)
+		require.NotNil(t, user.LastActivity)
+	})
+}
+
+func BenchmarkAuthenticatedRequests(b *testing.B) {
+	benchmark := func(b *testing.B, options ...func(*configuration.Configuration)) {
+		setup, _ := NewDatabaseFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		router := aux.CreateRestServer(setup.Db, options...)
+		seller, sessionId := setup.LoggedIn(setup.Seller())
+		setup.Items(seller.UserId, 10, aux.WithHidden(false))
+		url := path.SellerItems(seller.UserId)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			writer := httptest.NewRecorder()
+			router.ServeHTTP(writer, CreateGetRequest(url, WithSessionCookie(sessionId)))
+			if writer.Code != http.StatusOK {
+				b.Fatalf("unexpected status code %d", writer.Code)
+			}
+		}
+		b.StopTimer()
+
+		if err := router.FlushSessionActivity(); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("Without session cache", func(b *testing.B) {
+		benchmark(b, func(configuration *configuration.Configuration) {
+			configuration.DisableSessionCache = true
+		})
+	})
+
+	b.Run("With session cache", func(b *testing.B) {
+		benchmark(b)
+	})
+}
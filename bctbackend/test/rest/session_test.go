@@ -3,11 +3,16 @@
 package rest
 
 import (
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/security"
 	path "bctbackend/server/paths"
 	aux "bctbackend/test/helpers"
 	. "bctbackend/test/setup"
 	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestSessionExpiration(t *testing.T) {
@@ -34,3 +39,21 @@ func TestMissingSessionId(t *testing.T) {
 	router.ServeHTTP(writer, request)
 	RequireFailureType(t, writer, http.StatusUnauthorized, "missing_session_id")
 }
+
+func TestSlidingSessionExpiration(t *testing.T) {
+	setup, router, writer := NewRestFixture(WithDefaultCategories)
+	defer setup.Close()
+
+	_, sessionId := setup.LoggedIn(setup.Admin(), aux.WithExpiration(10))
+
+	url := path.Items()
+	request := CreateGetRequest(url, WithSessionCookie(sessionId))
+	router.ServeHTTP(writer, request)
+	require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+	require.NoError(t, router.FlushSessionActivity())
+
+	session, err := queries.GetSessionById(setup.Db, sessionId)
+	require.NoError(t, err)
+	require.Less(t, models.Now()+10, session.ExpirationTime)
+	require.LessOrEqual(t, session.ExpirationTime, session.CreatedAt+security.SessionDurationInSeconds)
+}
@@ -0,0 +1,123 @@
+//go:build test
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"bctbackend/server"
+	path "bctbackend/server/paths"
+	. "bctbackend/test/setup"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// serve lets the router handle requests on a random port until the returned function is called,
+// which shuts the server down and returns the result of Serve.
+func serve(t *testing.T, router *server.Server) (string, func() error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- router.Serve(ctx, listener)
+	}()
+
+	shutdown := func() error {
+		cancel()
+
+		select {
+		case err := <-result:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not shut down")
+			return nil
+		}
+	}
+
+	return listener.Addr().String(), shutdown
+}
+
+func TestShutdown(t *testing.T) {
+	t.Run("Closes websocket connections with close frame", func(t *testing.T) {
+		setup, router, _ := NewRestFixture()
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		address, shutdown := serve(t, router)
+
+		handshake := CreateGetRequest(path.Websocket(), WithSessionCookie(sessionId))
+		connection, _, err := websocket.DefaultDialer.Dial("ws://"+address+path.Websocket().String(), handshake.Header)
+		require.NoError(t, err)
+		defer connection.Close()
+		require.Eventually(t, func() bool { return router.WebsocketSubscriberCount() == 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, shutdown())
+
+		require.NoError(t, connection.SetReadDeadline(time.Now().Add(time.Second)))
+		_, _, err = connection.ReadMessage()
+		require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+	})
+
+	t.Run("Releases websocket goroutines", func(t *testing.T) {
+		setup, router, _ := NewRestFixture()
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		goroutineCount := runtime.NumGoroutine()
+		address, shutdown := serve(t, router)
+
+		handshake := CreateGetRequest(path.Websocket(), WithSessionCookie(sessionId))
+		connection, _, err := websocket.DefaultDialer.Dial("ws://"+address+path.Websocket().String(), handshake.Header)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return router.WebsocketSubscriberCount() == 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, shutdown())
+		connection.Close()
+
+		// The pumps of the connection only notice it was closed after the broadcaster has stopped
+		require.Eventually(t, func() bool { return runtime.NumGoroutine() <= goroutineCount }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Ends event streams", func(t *testing.T) {
+		setup, router, _ := NewRestFixture()
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		address, shutdown := serve(t, router)
+
+		request := CreateGetRequest(path.Events(), WithSessionCookie(sessionId))
+		request.URL, _ = request.URL.Parse("http://" + address + path.Events().String())
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Eventually(t, func() bool { return router.EventStreamSubscriberCount() == 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, shutdown())
+
+		_, err = io.ReadAll(response.Body)
+		require.NoError(t, err)
+	})
+
+	t.Run("Stops accepting connections", func(t *testing.T) {
+		setup, router, _ := NewRestFixture()
+		defer setup.Close()
+
+		address, shutdown := serve(t, router)
+		require.NoError(t, shutdown())
+
+		_, err := net.DialTimeout("tcp", address, time.Second)
+		var netError *net.OpError
+		require.True(t, errors.As(err, &netError), err)
+	})
+}
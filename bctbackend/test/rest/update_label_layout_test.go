@@ -0,0 +1,97 @@
+//go:build test
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	path "bctbackend/server/paths"
+	restapi "bctbackend/server/rest"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateLabelLayout(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		setup, router, writer := NewRestFixture(WithDefaultCategories)
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		layout := setup.LabelLayout("Avery")
+
+		payload := restapi.LabelLayoutPayload{Name: "Avery L7160 21-up", Layout: presetLayout}
+		request := CreatePutRequest(path.LabelLayout(layout.LayoutId), &payload, WithSessionCookie(sessionId))
+		router.ServeHTTP(writer, request)
+		require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+
+		updated, err := queries.GetLabelLayoutWithId(setup.Db, layout.LayoutId)
+		require.NoError(t, err)
+		require.Equal(t, "Avery L7160 21-up", updated.Name)
+		require.Equal(t, 3, updated.Columns)
+		require.Equal(t, 15.1, updated.PaperMargins.Top)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Unknown layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+
+			payload := restapi.LabelLayoutPayload{Name: "Avery L7160 21-up", Layout: presetLayout}
+			request := CreatePutRequest(path.LabelLayout(models.Id(1)), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusNotFound, "unknown_label_layout")
+		})
+
+		t.Run("Invalid layout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			layout := setup.LabelLayout("Avery")
+
+			invalidLayout := presetLayout
+			invalidLayout.FontSize = 0
+			payload := restapi.LabelLayoutPayload{Name: "Avery", Layout: invalidLayout}
+			request := CreatePutRequest(path.LabelLayout(layout.LayoutId), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "invalid_layout")
+
+			unchanged, err := queries.GetLabelLayoutWithId(setup.Db, layout.LayoutId)
+			require.NoError(t, err)
+			require.Equal(t, layout, unchanged)
+		})
+
+		t.Run("Name in use", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Admin())
+			layout := setup.LabelLayout("Avery")
+			setup.LabelLayout("Herma 4226")
+
+			payload := restapi.LabelLayoutPayload{Name: "Herma 4226", Layout: presetLayout}
+			request := CreatePutRequest(path.LabelLayout(layout.LayoutId), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusBadRequest, "label_layout_name_in_use")
+		})
+
+		t.Run("As seller", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			layout := setup.LabelLayout("Avery")
+
+			payload := restapi.LabelLayoutPayload{Name: "Avery L7160 21-up", Layout: presetLayout}
+			request := CreatePutRequest(path.LabelLayout(layout.LayoutId), &payload, WithSessionCookie(sessionId))
+			router.ServeHTTP(writer, request)
+			RequireFailureType(t, writer, http.StatusForbidden, "wrong_role")
+		})
+	})
+}
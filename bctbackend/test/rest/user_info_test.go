@@ -5,6 +5,7 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"bctbackend/algorithms"
@@ -28,6 +29,11 @@ func TestGetUserInformation(t *testing.T) {
 				admin, sessionId := setup.LoggedIn(setup.Admin())
 
 				url := path.User(admin.UserId)
+
+				// Last activity is written to the database in batches
+				router.ServeHTTP(httptest.NewRecorder(), CreateGetRequest(url, WithSessionCookie(sessionId)))
+				require.NoError(t, router.FlushSessionActivity())
+
 				request := CreateGetRequest(url, WithSessionCookie(sessionId))
 				router.ServeHTTP(writer, request)
 				require.Equal(t, http.StatusOK, writer.Code)
@@ -145,6 +151,52 @@ func TestGetUserInformation(t *testing.T) {
 			})
 		})
 
+		t.Run("Logged in as admin, information about role without own data", func(t *testing.T) {
+			for roleName, addUser := range map[string]func(DatabaseFixture, ...func(*aux.AddUserData)) *models.User{
+				"volunteer":  DatabaseFixture.Volunteer,
+				"supervisor": DatabaseFixture.Supervisor,
+			} {
+				t.Run(roleName, func(t *testing.T) {
+					setup, router, writer := NewRestFixture(WithDefaultCategories)
+					defer setup.Close()
+
+					_, sessionId := setup.LoggedIn(setup.Admin())
+					user := addUser(setup.DatabaseFixture)
+
+					request := CreateGetRequest(path.User(user.UserId), WithSessionCookie(sessionId))
+					router.ServeHTTP(writer, request)
+					require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+					response := FromJson[restapi.GetUserInformationSuccessResponse](t, writer.Body.String())
+					require.Equal(t, user.UserId, response.UserId)
+					require.Equal(t, roleName, response.Role)
+				})
+			}
+		})
+
+		t.Run("Own information of role without summary", func(t *testing.T) {
+			for roleName, addUser := range map[string]func(DatabaseFixture, ...func(*aux.AddUserData)) *models.User{
+				"cashier":    DatabaseFixture.Cashier,
+				"volunteer":  DatabaseFixture.Volunteer,
+				"supervisor": DatabaseFixture.Supervisor,
+			} {
+				t.Run(roleName, func(t *testing.T) {
+					setup, router, writer := NewRestFixture(WithDefaultCategories)
+					defer setup.Close()
+
+					user, sessionId := setup.LoggedIn(addUser(setup.DatabaseFixture))
+
+					request := CreateGetRequest(path.User(user.UserId), WithSessionCookie(sessionId))
+					router.ServeHTTP(writer, request)
+					require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+					response := FromJson[restapi.GetUserInformationSuccessResponse](t, writer.Body.String())
+					require.Equal(t, user.UserId, response.UserId)
+					require.Equal(t, roleName, response.Role)
+				})
+			}
+		})
+
 		t.Run("Logged in as seller", func(t *testing.T) {
 			for _, unfrozenItemCount := range []int{0, 1, 2, 5, 10} {
 				for _, frozenItemCount := range []int{0, 1, 2, 5, 10} {
@@ -0,0 +1,31 @@
+//go:build test
+
+package rest
+
+import (
+	"bctbackend/server"
+	path "bctbackend/server/paths"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// DialWebsocket serves the router over HTTP and opens a websocket connection to it.
+// The options are the same as for regular requests, e.g., WithSessionCookie.
+func DialWebsocket(t *testing.T, router *server.Server, options ...func(*http.Request)) (*websocket.Conn, *http.Response, error) {
+	httpServer := httptest.NewServer(router)
+	t.Cleanup(httpServer.Close)
+
+	handshake := CreateGetRequest(path.Websocket(), options...)
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + path.Websocket().String()
+
+	connection, response, err := websocket.DefaultDialer.Dial(url, handshake.Header)
+	if connection != nil {
+		t.Cleanup(func() { connection.Close() })
+	}
+
+	return connection, response, err
+}
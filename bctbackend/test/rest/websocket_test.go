@@ -0,0 +1,171 @@
+//go:build test
+
+package rest
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bctbackend/database/models"
+	"bctbackend/server/events"
+	path "bctbackend/server/paths"
+	"bctbackend/server/rest"
+	aux "bctbackend/test/helpers"
+	. "bctbackend/test/setup"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebsocket(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Bearer token", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, token := setup.ApiToken(setup.Admin())
+
+			_, _, err := DialWebsocket(t, router, WithBearerToken(token))
+			require.NoError(t, err)
+		})
+
+		t.Run("Seller only receives events about own items", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, adminSessionId := setup.LoggedIn(setup.Admin())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+			otherSeller := setup.Seller()
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithFrozen(false), aux.WithHidden(false))
+			otherItem := setup.Item(otherSeller.UserId, aux.WithDummyData(2), aux.WithFrozen(false), aux.WithHidden(false))
+			connection := listenForEvents(t, router, sellerSessionId)
+
+			for _, itemId := range []models.Id{otherItem.ItemID, item.ItemID} {
+				writer := httptest.NewRecorder()
+				router.ServeHTTP(writer, CreatePostRequest[any](path.ItemFreeze(itemId), nil, WithSessionCookie(adminSessionId)))
+				require.Equal(t, http.StatusNoContent, writer.Code, writer.Body.String())
+			}
+
+			itemsFrozen := receiveEvent[events.ItemsPayload](t, connection, events.ItemsFrozen)
+			require.Equal(t, []models.Id{item.ItemID}, itemsFrozen.ItemIds)
+		})
+
+		t.Run("Seller receives sales of own items", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, cashierSessionId := setup.LoggedIn(setup.Cashier())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+			connection := listenForEvents(t, router, sellerSessionId)
+
+			request := CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{item.ItemID}}, WithSessionCookie(cashierSessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			saleCreated := receiveEvent[events.SalePayload](t, connection, events.SaleCreated)
+			require.Equal(t, []models.Id{item.ItemID}, saleCreated.ItemIds)
+		})
+
+		t.Run("Cashier only receives own sales", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			cashier, cashierSessionId := setup.LoggedIn(setup.Cashier())
+			_, otherCashierSessionId := setup.LoggedIn(setup.Cashier())
+			seller := setup.Seller()
+			item := setup.Item(seller.UserId, aux.WithDummyData(1), aux.WithHidden(false))
+			otherItem := setup.Item(seller.UserId, aux.WithDummyData(2), aux.WithHidden(false))
+			connection := listenForEvents(t, router, cashierSessionId)
+
+			writer := httptest.NewRecorder()
+			request := CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{otherItem.ItemID}}, WithSessionCookie(otherCashierSessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			writer = httptest.NewRecorder()
+			request = CreatePostRequest(path.Sales(), &rest.AddSalePayload{Items: []models.Id{item.ItemID}}, WithSessionCookie(cashierSessionId))
+			router.ServeHTTP(writer, request)
+			require.Equal(t, http.StatusCreated, writer.Code, writer.Body.String())
+
+			saleCreated := receiveEvent[events.SalePayload](t, connection, events.SaleCreated)
+			require.Equal(t, cashier.UserId, saleCreated.CashierId)
+			require.Equal(t, []models.Id{item.ItemID}, saleCreated.ItemIds)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Not logged in", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, response, err := DialWebsocket(t, router)
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		})
+
+		t.Run("Unknown session", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, response, err := DialWebsocket(t, router, WithSessionCookie("unknown"))
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		})
+
+		t.Run("API token out of scope", func(t *testing.T) {
+			setup, router, _ := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, token := setup.ApiToken(setup.Admin(), aux.WithActions("list_items"))
+
+			_, response, err := DialWebsocket(t, router, WithBearerToken(token))
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			require.Equal(t, http.StatusForbidden, response.StatusCode)
+		})
+	})
+
+	t.Run("Disconnected on revocation", func(t *testing.T) {
+		requireDisconnected := func(t *testing.T, connection *websocket.Conn) {
+			require.NoError(t, connection.SetReadDeadline(time.Now().Add(time.Second)))
+			_, _, err := connection.ReadMessage()
+			require.Error(t, err)
+
+			var netError net.Error
+			if errors.As(err, &netError) {
+				require.False(t, netError.Timeout(), "Expected connection to be closed by server")
+			}
+		}
+
+		t.Run("Logout", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, sessionId := setup.LoggedIn(setup.Seller())
+			connection := listenForEvents(t, router, sessionId)
+
+			router.ServeHTTP(writer, CreatePostRequest(path.Logout(), &rest.LogoutPayload{}, WithSessionCookie(sessionId)))
+			require.Equal(t, http.StatusOK, writer.Code)
+
+			requireDisconnected(t, connection)
+		})
+
+		t.Run("Remove user sessions", func(t *testing.T) {
+			setup, router, writer := NewRestFixture(WithDefaultCategories)
+			defer setup.Close()
+
+			_, adminSessionId := setup.LoggedIn(setup.Admin())
+			seller, sellerSessionId := setup.LoggedIn(setup.Seller())
+			connection := listenForEvents(t, router, sellerSessionId)
+
+			router.ServeHTTP(writer, CreateDeleteRequest(path.UserSessions(seller.UserId), WithSessionCookie(adminSessionId)))
+			require.Equal(t, http.StatusOK, writer.Code, writer.Body.String())
+
+			requireDisconnected(t, connection)
+		})
+	})
+}
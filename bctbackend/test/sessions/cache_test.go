@@ -0,0 +1,56 @@
+//go:build test
+
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	dberr "bctbackend/database/errors"
+	"bctbackend/database/models"
+	"bctbackend/database/queries"
+	"bctbackend/security"
+	"bctbackend/server/sessions"
+	. "bctbackend/test/setup"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveExpired(t *testing.T) {
+	t.Run("Removes expired sessions", func(t *testing.T) {
+		setup, _ := NewDatabaseFixture()
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		cache := sessions.NewCache(setup.Db, time.Hour)
+
+		_, err := cache.Lookup(sessionId)
+		require.NoError(t, err)
+
+		// Bypass the cache so that only a cache miss can notice the session is gone
+		require.NoError(t, queries.DeleteSession(setup.Db, sessionId))
+
+		cache.RemoveExpired(models.Now() + security.SessionDurationInSeconds + 1)
+
+		_, err = cache.Lookup(sessionId)
+		require.ErrorIs(t, err, dberr.ErrNoSuchSession)
+	})
+
+	t.Run("Keeps active sessions", func(t *testing.T) {
+		setup, _ := NewDatabaseFixture()
+		defer setup.Close()
+
+		_, sessionId := setup.LoggedIn(setup.Admin())
+		cache := sessions.NewCache(setup.Db, time.Hour)
+
+		_, err := cache.Lookup(sessionId)
+		require.NoError(t, err)
+
+		require.NoError(t, queries.DeleteSession(setup.Db, sessionId))
+
+		cache.RemoveExpired(models.Now())
+
+		_, err = cache.Lookup(sessionId)
+		require.NoError(t, err)
+	})
+}
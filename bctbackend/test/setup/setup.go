@@ -66,6 +66,10 @@ func NewRestFixture(databaseOptions ...func(*DatabaseFixture)) (RestFixture, *se
 }
 
 func (f *RestFixture) Close() {
+	// Background jobs must not outlive the database
+	if f.Server != nil {
+		f.Server.WaitForJobs()
+	}
 	f.DatabaseFixture.Close()
 	f.Server = nil
 	f.Writer = nil
@@ -100,6 +104,14 @@ func (s DatabaseFixture) Seller(options ...func(*aux.AddUserData)) *models.User
 	return aux.AddUserToDatabase(s.Db, models.NewSellerRoleId(), options...)
 }
 
+func (s DatabaseFixture) Volunteer(options ...func(*aux.AddUserData)) *models.User {
+	return aux.AddUserToDatabase(s.Db, models.NewVolunteerRoleId(), options...)
+}
+
+func (s DatabaseFixture) Supervisor(options ...func(*aux.AddUserData)) *models.User {
+	return aux.AddUserToDatabase(s.Db, models.NewSupervisorRoleId(), options...)
+}
+
 func (s DatabaseFixture) Session(userId models.Id, options ...func(*aux.AddSessionData)) models.SessionId {
 	return aux.AddSessionToDatabase(s.Db, userId)
 }
@@ -109,6 +121,14 @@ func (s DatabaseFixture) LoggedIn(user *models.User, options ...func(*aux.AddSes
 	return user, session
 }
 
+func (s DatabaseFixture) ApiToken(user *models.User, options ...func(*aux.AddApiTokenData)) (models.Id, string) {
+	return aux.AddApiTokenToDatabase(s.Db, user.UserId, options...)
+}
+
+func (s DatabaseFixture) LabelLayout(name string, options ...func(*models.LabelLayout)) *models.LabelLayout {
+	return aux.AddLabelLayoutToDatabase(s.Db, name, options...)
+}
+
 func (s DatabaseFixture) Item(seller models.Id, options ...func(*aux.AddItemData)) *models.Item {
 	return aux.AddItemToDatabase(s.Db, seller, options...)
 }
@@ -0,0 +1,175 @@
+//go:build test
+
+package zpl
+
+import (
+	"strings"
+	"testing"
+
+	"bctbackend/barcode"
+	"bctbackend/pdf"
+	"bctbackend/zpl"
+
+	"github.com/stretchr/testify/require"
+)
+
+func defaultConfiguration() *zpl.Configuration {
+	return &zpl.Configuration{
+		LabelWidth:         812,
+		LabelHeight:        406,
+		LabelPadding:       16,
+		Dpi:                203,
+		FontSize:           3,
+		BarcodeSymbology:   barcode.Code128,
+		BarcodeModuleWidth: 0.33,
+		BarcodeBarHeight:   10,
+	}
+}
+
+func label(itemIdentifier int) *pdf.LabelData {
+	return &pdf.LabelData{
+		BarcodeData:      "ABC" + strings.Repeat("1", itemIdentifier),
+		EventPrefix:      "ABC",
+		Description:      "Wooden train set",
+		Category:         "Toys",
+		ItemIdentifier:   itemIdentifier,
+		PriceInCents:     1250,
+		SellerIdentifier: 100,
+		SellerZone:       1,
+	}
+}
+
+func TestGenerateZpl(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("One block per label", func(t *testing.T) {
+			labels := []*pdf.LabelData{label(1), label(2), label(3)}
+
+			buffer, err := zpl.GenerateZpl(defaultConfiguration(), labels)
+			require.NoError(t, err)
+
+			output := buffer.String()
+			require.Equal(t, 3, strings.Count(output, "^XA"))
+			require.Equal(t, 3, strings.Count(output, "^XZ"))
+			require.Equal(t, 3, strings.Count(output, "^BC"))
+			require.Contains(t, output, "^PW812")
+			require.Contains(t, output, "^LL406")
+			require.Contains(t, output, "^FDABC11^FS")
+			require.Contains(t, output, "Wooden train set")
+			require.NotContains(t, output, pdf.PreviewWatermark)
+		})
+
+		t.Run("Symbologies", func(t *testing.T) {
+			commands := map[barcode.Symbology]string{
+				barcode.Code128:    "^BC",
+				barcode.QR:         "^BQ",
+				barcode.DataMatrix: "^BX",
+				barcode.EAN13:      "^BE",
+			}
+
+			for symbology, command := range commands {
+				t.Run(string(symbology), func(t *testing.T) {
+					buffer, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{label(1)}, zpl.UsingSymbology(symbology))
+					require.NoError(t, err)
+					require.Contains(t, buffer.String(), command)
+				})
+			}
+		})
+
+		t.Run("Icons", func(t *testing.T) {
+			withoutIcons := label(1)
+			withIcons := label(2)
+			withIcons.Charity = true
+			withIcons.Donation = true
+
+			buffer, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{withoutIcons, withIcons})
+			require.NoError(t, err)
+
+			blocks := strings.SplitAfter(buffer.String(), "^XZ")
+			require.Equal(t, 0, strings.Count(blocks[0], "^GFA"))
+			require.Equal(t, 2, strings.Count(blocks[1], "^GFA"))
+		})
+
+		t.Run("Control characters are escaped", func(t *testing.T) {
+			data := label(1)
+			data.Description = "A^B~C_D"
+
+			buffer, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{data})
+			require.NoError(t, err)
+			require.Contains(t, buffer.String(), "^FDA_5EB_7EC_5FD^FS")
+		})
+
+		t.Run("Template", func(t *testing.T) {
+			template := &pdf.LabelTemplate{
+				Fields: []pdf.TemplateField{
+					{Kind: pdf.TextField, X: 0.5, Y: 0, HorizontalAlignment: pdf.AlignCenter, Text: "Spring sale"},
+				},
+			}
+
+			buffer, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{label(1)}, zpl.UsingTemplate(template))
+			require.NoError(t, err)
+
+			output := buffer.String()
+			require.Contains(t, output, "Spring sale")
+			require.NotContains(t, output, "^BC")
+			require.NotContains(t, output, "Wooden train set")
+		})
+
+		t.Run("Default template places text below the barcode", func(t *testing.T) {
+			configuration := defaultConfiguration()
+			configuration.BarcodeBarHeight = 20
+
+			buffer, err := zpl.GenerateZpl(configuration, []*pdf.LabelData{label(1)})
+			require.NoError(t, err)
+
+			// The barcode starts at the padding and is 20mm, i.e., 160 dots, high.
+			// The printer wraps the description, so the category follows the three lines of 24 dots reserved for it.
+			output := buffer.String()
+			require.Regexp(t, `\^FO\d+,16\^BY`, output)
+			require.Regexp(t, `\^FO16,176\^[^\n]*Wooden train set`, output)
+			require.Regexp(t, `\^FO16,248\^[^\n]*Toys`, output)
+		})
+
+		t.Run("Preview", func(t *testing.T) {
+			buffer, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{label(1)}, zpl.MarkedAsPreview())
+			require.NoError(t, err)
+			require.Contains(t, buffer.String(), pdf.PreviewWatermark)
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("Invalid configuration", func(t *testing.T) {
+			modifications := map[string]func(*zpl.Configuration){
+				"Zero width":        func(c *zpl.Configuration) { c.LabelWidth = 0 },
+				"Zero height":       func(c *zpl.Configuration) { c.LabelHeight = 0 },
+				"Negative padding":  func(c *zpl.Configuration) { c.LabelPadding = -1 },
+				"Excessive padding": func(c *zpl.Configuration) { c.LabelPadding = 203 },
+				"Zero dpi":          func(c *zpl.Configuration) { c.Dpi = 0 },
+				"Zero font size":    func(c *zpl.Configuration) { c.FontSize = 0 },
+				"Unknown symbology": func(c *zpl.Configuration) { c.BarcodeSymbology = "aztec" },
+				"Zero module width": func(c *zpl.Configuration) { c.BarcodeModuleWidth = 0 },
+			}
+
+			for name, modify := range modifications {
+				t.Run(name, func(t *testing.T) {
+					configuration := defaultConfiguration()
+					modify(configuration)
+
+					_, err := zpl.GenerateZpl(configuration, []*pdf.LabelData{label(1)})
+					require.Error(t, err)
+				})
+			}
+		})
+
+		t.Run("Unknown symbology", func(t *testing.T) {
+			_, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{label(1)}, zpl.UsingSymbology("aztec"))
+			require.Error(t, err)
+		})
+
+		t.Run("Invalid template", func(t *testing.T) {
+			template := &pdf.LabelTemplate{Fields: []pdf.TemplateField{{Kind: "hologram"}}}
+
+			_, err := zpl.GenerateZpl(defaultConfiguration(), []*pdf.LabelData{label(1)}, zpl.UsingTemplate(template))
+			require.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package zpl
+
+type ZplError struct {
+	Message string
+	Wrapped error
+}
+
+func (e *ZplError) Error() string {
+	if e.Wrapped != nil {
+		return e.Message + ": " + e.Wrapped.Error()
+	}
+	return e.Message
+}
+
+func (e *ZplError) Unwrap() error {
+	return e.Wrapped
+}
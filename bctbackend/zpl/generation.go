@@ -0,0 +1,414 @@
+// Package zpl renders labels as ZPL II, the command language of Zebra-compatible thermal printers.
+// Unlike PDFs, which place labels in a grid on sheets of paper, each label is printed separately.
+package zpl
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"bctbackend/barcode"
+	"bctbackend/pdf"
+)
+
+const millimetersPerInch = 25.4
+
+// Font size in mm of the human-readable text beneath barcodes, as in PDFs
+const barcodeTextFontSize = 2.5
+
+type Configuration struct {
+	// Size of a label in dots
+	LabelWidth  int
+	LabelHeight int
+
+	// Padding inside each label in dots
+	LabelPadding int
+
+	// Resolution of the printer in dots per inch, needed to convert the sizes in mm used by templates and barcodes
+	Dpi int
+
+	// Font size in mm of fields that do not specify their own
+	FontSize float64
+
+	// Symbology used unless overridden by UsingSymbology
+	BarcodeSymbology barcode.Symbology
+
+	// Width in mm of the narrowest bar and height in mm of the bars, as for vector barcodes in PDFs.
+	// Both are rounded to whole dots.
+	BarcodeModuleWidth float64
+	BarcodeBarHeight   float64
+
+	// BarcodeShowText prints the encoded data beneath one-dimensional barcodes
+	BarcodeShowText bool
+}
+
+type generationSettings struct {
+	template  *pdf.LabelTemplate
+	symbology barcode.Symbology
+	preview   bool
+}
+
+type GenerationOption func(*generationSettings)
+
+// UsingTemplate determines the contents of the labels. Without this option, the default template is used.
+func UsingTemplate(template *pdf.LabelTemplate) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.template = template
+	}
+}
+
+// UsingSymbology overrides the symbology of the configuration. An empty symbology leaves it unchanged.
+func UsingSymbology(symbology barcode.Symbology) GenerationOption {
+	return func(settings *generationSettings) {
+		settings.symbology = symbology
+	}
+}
+
+// MarkedAsPreview prints pdf.PreviewWatermark across every label.
+func MarkedAsPreview() GenerationOption {
+	return func(settings *generationSettings) {
+		settings.preview = true
+	}
+}
+
+type zplBuilder struct {
+	buffer        bytes.Buffer
+	configuration *Configuration
+	template      *pdf.LabelTemplate
+	symbology     barcode.Symbology
+	preview       bool
+	icons         *icons
+
+	// Bottom of the last field that was written, so that the next field can be placed below it
+	fieldBottom int
+}
+
+// box is a rectangle measured in dots.
+type box struct {
+	left   int
+	top    int
+	width  int
+	height int
+}
+
+func (b *box) right() int {
+	return b.left + b.width
+}
+
+// GenerateZpl renders each label as a separate ^XA...^XZ block.
+func GenerateZpl(configuration *Configuration, labels []*pdf.LabelData, options ...GenerationOption) (*bytes.Buffer, error) {
+	settings := generationSettings{
+		template:  pdf.DefaultLabelTemplate(),
+		symbology: configuration.BarcodeSymbology,
+	}
+	for _, option := range options {
+		option(&settings)
+	}
+
+	if err := validateConfiguration(configuration); err != nil {
+		return nil, &ZplError{Message: "invalid configuration", Wrapped: err}
+	}
+	if settings.template == nil {
+		settings.template = pdf.DefaultLabelTemplate()
+	}
+	if err := pdf.ValidateTemplate(settings.template); err != nil {
+		return nil, &ZplError{Message: "invalid label template", Wrapped: err}
+	}
+	if settings.symbology == "" {
+		settings.symbology = configuration.BarcodeSymbology
+	}
+	if !barcode.IsValidSymbology(settings.symbology) {
+		return nil, &ZplError{Message: fmt.Sprintf("unknown barcode symbology %q", settings.symbology)}
+	}
+
+	icons, err := convertIcons(configuration.Dpi)
+	if err != nil {
+		return nil, &ZplError{Message: "failed to convert icons", Wrapped: err}
+	}
+
+	builder := zplBuilder{
+		configuration: configuration,
+		template:      settings.template,
+		symbology:     settings.symbology,
+		preview:       settings.preview,
+		icons:         icons,
+	}
+
+	for _, label := range labels {
+		if err := builder.writeLabel(label); err != nil {
+			return nil, &ZplError{Message: fmt.Sprintf("failed to write label for item %d", label.ItemIdentifier), Wrapped: err}
+		}
+	}
+
+	return &builder.buffer, nil
+}
+
+func validateConfiguration(configuration *Configuration) error {
+	if configuration.LabelWidth <= 0 || configuration.LabelHeight <= 0 {
+		return &ZplError{Message: "label width and height must be positive"}
+	}
+	if configuration.LabelPadding < 0 || 2*configuration.LabelPadding >= min(configuration.LabelWidth, configuration.LabelHeight) {
+		return &ZplError{Message: "label padding must be nonnegative and leave room for the contents"}
+	}
+	if configuration.Dpi <= 0 {
+		return &ZplError{Message: "dpi must be positive"}
+	}
+	if configuration.FontSize <= 0 {
+		return &ZplError{Message: "font size must be positive"}
+	}
+	if !barcode.IsValidSymbology(configuration.BarcodeSymbology) {
+		return &ZplError{Message: fmt.Sprintf("unknown barcode symbology %q", configuration.BarcodeSymbology)}
+	}
+	if configuration.BarcodeModuleWidth <= 0 || configuration.BarcodeBarHeight <= 0 {
+		return &ZplError{Message: "barcode module width and bar height must be positive"}
+	}
+
+	return nil
+}
+
+// toDots converts a length in mm to dots, rounding to the nearest dot.
+func (builder *zplBuilder) toDots(millimeters float64) int {
+	return int(math.Round(millimeters * float64(builder.configuration.Dpi) / millimetersPerInch))
+}
+
+func (builder *zplBuilder) writef(format string, arguments ...any) {
+	fmt.Fprintf(&builder.buffer, format, arguments...)
+}
+
+func (builder *zplBuilder) writeLabel(labelData *pdf.LabelData) error {
+	configuration := builder.configuration
+
+	// ^CI28 makes the printer interpret field data as UTF-8
+	builder.writef("^XA\n^CI28\n^PW%d\n^LL%d\n^LH0,0\n", configuration.LabelWidth, configuration.LabelHeight)
+
+	contents := box{
+		left:   configuration.LabelPadding,
+		top:    configuration.LabelPadding,
+		width:  configuration.LabelWidth - 2*configuration.LabelPadding,
+		height: configuration.LabelHeight - 2*configuration.LabelPadding,
+	}
+	builder.fieldBottom = contents.top
+
+	for index := range builder.template.Fields {
+		field := &builder.template.Fields[index]
+
+		if err := builder.writeField(field, fieldArea(field, &contents, builder.fieldBottom), labelData); err != nil {
+			return &ZplError{Message: fmt.Sprintf("failed to write %s field", field.Kind), Wrapped: err}
+		}
+	}
+
+	if builder.preview {
+		builder.writePreviewMark()
+	}
+
+	builder.writef("^XZ\n")
+	return nil
+}
+
+func (builder *zplBuilder) writeField(field *pdf.TemplateField, contents *box, labelData *pdf.LabelData) error {
+	switch field.Kind {
+	case pdf.BarcodeField:
+		data, err := pdf.BarcodeContent(builder.symbology, labelData)
+		if err != nil {
+			return &ZplError{Message: "failed to determine barcode content", Wrapped: err}
+		}
+		return builder.writeBarcodeField(field, contents, data)
+	case pdf.IconsField:
+		builder.writeIconsField(field, contents, labelData.Charity, labelData.Donation)
+		return nil
+	case pdf.DescriptionField:
+		builder.writeDescriptionField(field, contents, labelData.Description)
+		return nil
+	}
+
+	text, ok := pdf.FieldText(field, labelData)
+	if !ok {
+		return &ZplError{Message: fmt.Sprintf("unknown field kind %q", field.Kind)}
+	}
+
+	builder.writeTextBlock(field, contents, text, 1)
+	return nil
+}
+
+func (builder *zplBuilder) fontHeight(field *pdf.TemplateField) int {
+	fontSize := field.FontSize
+	if fontSize == 0 {
+		fontSize = builder.configuration.FontSize
+	}
+
+	return max(1, builder.toDots(fontSize))
+}
+
+// writeDescriptionField lets the printer wrap the description over the height of the field.
+// Unlike in PDFs, the font is not shrunk, as the printer's font metrics are unknown.
+func (builder *zplBuilder) writeDescriptionField(field *pdf.TemplateField, contents *box, description string) {
+	lineCount := 1
+	if field.Height > 0 {
+		lineCount = max(1, int(field.Height*float64(contents.height))/builder.fontHeight(field))
+	}
+
+	builder.writeTextBlock(field, contents, description, lineCount)
+}
+
+// writeTextBlock writes the text as a field block, which the printer aligns horizontally within the box of the field.
+// The width of the box is limited by the side of the label the text extends towards, as in PDFs.
+func (builder *zplBuilder) writeTextBlock(field *pdf.TemplateField, contents *box, text string, lineCount int) {
+	fontHeight := builder.fontHeight(field)
+	x := contents.left + int(math.Round(field.X*float64(contents.width)))
+
+	var left, width int
+	var justification string
+	switch field.HorizontalAlignment {
+	case pdf.AlignCenter:
+		width = 2 * min(x-contents.left, contents.right()-x)
+		left = x - width/2
+		justification = "C"
+	case pdf.AlignRight:
+		width = x - contents.left
+		left = contents.left
+		justification = "R"
+	default:
+		width = contents.right() - x
+		left = x
+		justification = "L"
+	}
+
+	_, top := builder.placeField(field, contents, 0, lineCount*fontHeight)
+
+	// The printer's font only comes in a regular style, so bold text is emulated by printing it twice, slightly shifted
+	offsets := []int{0}
+	if field.Bold {
+		offsets = append(offsets, max(1, fontHeight/30))
+	}
+
+	for _, offset := range offsets {
+		builder.writef("^FO%d,%d^A0N,%d,%d^FB%d,%d,0,%s,0^FH_^FD%s^FS\n", left+offset, top, fontHeight, fontHeight, max(1, width), lineCount, justification, escape(text))
+	}
+}
+
+// writeBarcodeField uses the printer's own barcode commands, so that bars are aligned with its dots.
+// Sizes are determined as for vector barcodes in PDFs, including the quiet zones.
+func (builder *zplBuilder) writeBarcodeField(field *pdf.TemplateField, contents *box, data string) error {
+	modules, err := barcode.EncodeModules(builder.symbology, data)
+	if err != nil {
+		return &ZplError{Message: fmt.Sprintf("failed to encode barcode for data %s", data), Wrapped: err}
+	}
+
+	quietZone := builder.symbology.QuietZoneModules()
+	showText := builder.configuration.BarcodeShowText && !builder.symbology.IsTwoDimensional()
+
+	textHeight := 0
+	if showText {
+		textHeight = builder.toDots(barcodeTextFontSize)
+	}
+
+	codeHeight := builder.toDots(builder.configuration.BarcodeBarHeight)
+	if field.Height > 0 {
+		codeHeight = int(field.Height*float64(contents.height)) - textHeight
+	}
+	codeHeight = max(codeHeight, 1)
+
+	moduleWidth := max(1, builder.toDots(builder.configuration.BarcodeModuleWidth))
+	if builder.symbology.IsTwoDimensional() {
+		moduleWidth = max(1, codeHeight/(modules.Rows+2*quietZone))
+	}
+
+	width := (modules.Columns + 2*quietZone) * moduleWidth
+	x, y := builder.placeField(field, contents, width, codeHeight+textHeight)
+	x += quietZone * moduleWidth
+
+	interpretationLine := "N"
+	if showText {
+		interpretationLine = "Y"
+	}
+
+	switch builder.symbology {
+	case barcode.Code128:
+		// Mode A lets the printer pick the most compact subsets
+		builder.writef("^FO%d,%d^BY%d^BCN,%d,%s,N,N,A^FD%s^FS\n", x, y, moduleWidth, codeHeight, interpretationLine, data)
+	case barcode.EAN13:
+		// The printer adds the check digit
+		builder.writef("^FO%d,%d^BY%d^BEN,%d,%s,N^FD%s^FS\n", x, y, moduleWidth, codeHeight, interpretationLine, data)
+	case barcode.QR:
+		// Magnification is limited to 10 by the printer. MA selects error correction level M, as in PDFs, and automatic input.
+		builder.writef("^FO%d,%d^BQN,2,%d^FDMA,%s^FS\n", x, y+quietZone*moduleWidth, min(moduleWidth, 10), data)
+	case barcode.DataMatrix:
+		builder.writef("^FO%d,%d^BXN,%d,200^FD%s^FS\n", x, y+quietZone*moduleWidth, moduleWidth, data)
+	default:
+		return &ZplError{Message: fmt.Sprintf("unknown barcode symbology %q", builder.symbology)}
+	}
+
+	return nil
+}
+
+// writeIconsField draws the donation and charity icons next to each other.
+// Space is reserved for both icons, so that they always appear at the same place.
+func (builder *zplBuilder) writeIconsField(field *pdf.TemplateField, contents *box, charity bool, donation bool) {
+	spacing := builder.toDots(2)
+	donationIcon := builder.icons.donation
+	charityIcon := builder.icons.charity
+
+	width := donationIcon.width + spacing + charityIcon.width
+	height := max(donationIcon.height, charityIcon.height)
+	x, y := builder.placeField(field, contents, width, height)
+
+	if donation {
+		builder.writef("^FO%d,%d%s^FS\n", x, y, donationIcon.command())
+	}
+
+	if charity {
+		builder.writef("^FO%d,%d%s^FS\n", x+width-charityIcon.width, y, charityIcon.command())
+	}
+}
+
+// writePreviewMark prints pdf.PreviewWatermark in reverse across the middle of the label.
+func (builder *zplBuilder) writePreviewMark() {
+	configuration := builder.configuration
+	fontHeight := configuration.LabelHeight / 4
+	top := (configuration.LabelHeight - fontHeight) / 2
+
+	builder.writef("^FO0,%d^A0N,%d,%d^FB%d,1,0,C,0^FR^FD%s^FS\n", top, fontHeight, fontHeight, configuration.LabelWidth, pdf.PreviewWatermark)
+}
+
+// fieldArea determines the box relative to which the field is positioned, as in PDFs.
+func fieldArea(field *pdf.TemplateField, contents *box, previousBottom int) *box {
+	if !field.Below {
+		return contents
+	}
+
+	return &box{left: contents.left, top: previousBottom, width: contents.width, height: contents.height}
+}
+
+// placeField determines the top left corner of a field of the given size
+// and remembers its bottom, so that the next field can be placed below it.
+func (builder *zplBuilder) placeField(field *pdf.TemplateField, contents *box, width int, height int) (int, int) {
+	x, y := fieldPosition(field, contents, width, height)
+	builder.fieldBottom = y + height
+
+	return x, y
+}
+
+// fieldPosition determines the top left corner of a field of the given size.
+func fieldPosition(field *pdf.TemplateField, contents *box, width int, height int) (int, int) {
+	x := contents.left + int(math.Round(field.X*float64(contents.width)))
+	switch field.HorizontalAlignment {
+	case pdf.AlignCenter:
+		x -= width / 2
+	case pdf.AlignRight:
+		x -= width
+	}
+
+	y := contents.top + int(math.Round(field.Y*float64(contents.height)))
+	if field.VerticalAlignment == pdf.AlignBottom {
+		y -= height
+	}
+
+	return x, y
+}
+
+// escape makes the text safe for use as field data, which is interpreted with ^FH_ so that _ introduces a hexadecimal byte.
+func escape(text string) string {
+	replacer := strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E")
+	return replacer.Replace(text)
+}
@@ -0,0 +1,85 @@
+package zpl
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"bctbackend/pdf"
+)
+
+// Resolution of the icon images, which determines their size on the labels
+const iconDpi = 96
+
+// Gray level below which a pixel of an icon is printed
+const darkThreshold = 160
+
+// graphic is a monochrome image in the printer's format, one bit per dot with the rows padded to whole bytes.
+type graphic struct {
+	width       int
+	height      int
+	bytesPerRow int
+	data        []byte
+}
+
+type icons struct {
+	donation *graphic
+	charity  *graphic
+}
+
+// command returns the ^GF command printing the graphic.
+func (g *graphic) command() string {
+	return fmt.Sprintf("^GFA,%d,%d,%d,%X", len(g.data), len(g.data), g.bytesPerRow, g.data)
+}
+
+func convertIcons(dpi int) (*icons, error) {
+	donation, err := convertImage(pdf.DonationImageBuffer(), dpi)
+	if err != nil {
+		return nil, &ZplError{Message: "failed to convert donation image", Wrapped: err}
+	}
+
+	charity, err := convertImage(pdf.CharityImageBuffer(), dpi)
+	if err != nil {
+		return nil, &ZplError{Message: "failed to convert charity image", Wrapped: err}
+	}
+
+	return &icons{donation: donation, charity: charity}, nil
+}
+
+// convertImage scales the PNG to the printer's resolution and reduces it to black and white.
+func convertImage(buffer *bytes.Buffer, dpi int) (*graphic, error) {
+	img, err := png.Decode(buffer)
+	if err != nil {
+		return nil, &ZplError{Message: "failed to decode image", Wrapped: err}
+	}
+
+	bounds := img.Bounds()
+	scale := float64(dpi) / iconDpi
+	width := max(1, int(math.Round(float64(bounds.Dx())*scale)))
+	height := max(1, int(math.Round(float64(bounds.Dy())*scale)))
+	bytesPerRow := (width + 7) / 8
+
+	data := make([]byte, bytesPerRow*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sourceX := bounds.Min.X + x*bounds.Dx()/width
+			sourceY := bounds.Min.Y + y*bounds.Dy()/height
+
+			if isDark(img, sourceX, sourceY) {
+				data[y*bytesPerRow+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+
+	return &graphic{width: width, height: height, bytesPerRow: bytesPerRow, data: data}, nil
+}
+
+func isDark(img image.Image, x int, y int) bool {
+	_, _, _, alpha := img.At(x, y).RGBA()
+	gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+
+	return alpha >= 0x8000 && gray.Y < darkThreshold
+}